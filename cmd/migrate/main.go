@@ -0,0 +1,114 @@
+// migrate 데이터베이스 스키마 마이그레이션 도구
+//
+// 사용법:
+//
+//	go run ./cmd/migrate up            미적용 마이그레이션 모두 적용
+//	go run ./cmd/migrate down [-steps N] 최근 마이그레이션 N개 되돌리기 (기본값 1)
+//	go run ./cmd/migrate status        마이그레이션 적용 상태 출력
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sermo-be/internal/config"
+	"sermo-be/pkg/database"
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Parse()
+	if err != nil {
+		log.Fatalf("설정 로드 실패: %v", err)
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		log.Fatalf("설정 로드 실패: %v", err)
+	}
+
+	if err := database.Connect(&database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	}); err != nil {
+		log.Fatalf("데이터베이스 연결 실패: %v", err)
+	}
+	defer database.Close()
+
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		log.Fatalf("데이터베이스 연결 실패: %v", err)
+	}
+
+	migrator, err := database.NewMigrator(sqlDB)
+	if err != nil {
+		log.Fatalf("마이그레이션 로드 실패: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, migrator, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Printf("❌ %v", err)
+		database.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *database.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ %d개 마이그레이션 적용 완료", applied)
+
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "되돌릴 마이그레이션 개수")
+		fs.Parse(args)
+
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ %d개 마이그레이션 되돌림 완료", reverted)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		usage()
+		return fmt.Errorf("unknown command: %s", command)
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate <up|down|status> [-steps N]")
+}
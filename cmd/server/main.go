@@ -22,26 +22,42 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "sermo-be/docs"
 	"sermo-be/internal/config"
+	"sermo-be/internal/container"
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/session"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/routes"
 	"sermo-be/pkg/database"
+	"sermo-be/pkg/jwt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
-	// 설정 로드
-	cfg := config.Load()
+	migrateOnly := flag.Bool("migrate-only", false, "마이그레이션만 실행하고 종료")
+	flag.Parse()
+
+	// 설정 로드 (필수 값 누락 시 즉시 종료, 마이그레이션만 실행할 때는 DB 설정만 검증)
+	cfg, err := loadConfig(*migrateOnly)
+	if err != nil {
+		log.Fatalf("설정 로드 실패: %v", err)
+	}
 
 	// 데이터베이스 연결
 	dbConfig := &database.Config{
@@ -57,11 +73,57 @@ func main() {
 		log.Fatalf("데이터베이스 연결 실패: %v", err)
 	}
 
-	// 데이터베이스 마이그레이션 실행
-	if err := database.RunMigrations(); err != nil {
-		log.Fatalf("데이터베이스 마이그레이션 실패: %v", err)
+	// 데이터베이스 마이그레이션 실행 (여러 인스턴스가 동시에 기동해도 advisory lock으로 한 번만 적용)
+	if *migrateOnly || cfg.Database.MigrateOnStart {
+		if err := database.RunMigrations(context.Background()); err != nil {
+			log.Fatalf("데이터베이스 마이그레이션 실패: %v", err)
+		}
+	}
+	if *migrateOnly {
+		database.Close()
+		return
+	}
+
+	// JWT 서명 키 설정
+	configureJWT(cfg)
+
+	// 봇 응답 대기 기본 정책 설정 (채팅봇별 설정이 있으면 그 값이 우선)
+	chat.DefaultReplyPolicy = chat.ReplyPolicy{
+		BaseDelay:       cfg.Chat.ReplyDelay,
+		TypingExtension: cfg.Chat.TypingExtension,
+		MaxWait:         cfg.Chat.MaxReplyWait,
+	}
+
+	// 대화 기억(누적 요약)과 프롬프트에 넣을 최근 대화 분량 설정
+	chat.DefaultMemoryPolicy = chat.MemoryPolicy{
+		HistoryTokenBudget: cfg.Chat.HistoryTokenBudget,
+		SummarizeAfter:     cfg.Chat.MemorySummarizeAfter,
+		KeepRecent:         cfg.Chat.MemoryKeepRecent,
+	}
+
+	// 채팅 세션 레지스트리/메시지 버스 설정 (Redis 사용 시 다중 인스턴스 간 세션 라우팅)
+	sseManager, closeSessionBackend := configureSessionBackend(cfg)
+
+	// 동시 세션 수 제한 및 대기열 설정
+	sseManager.SetLimits(middleware.SessionLimits{
+		MaxSessions:        cfg.Chat.MaxSessions,
+		MaxSessionsPerUser: cfg.Chat.MaxSessionsPerUser,
+		MaxQueueLength:     cfg.Chat.MaxQueueLength,
+		QueueTimeout:       cfg.Chat.QueueTimeout,
+	})
+
+	// 기능별 언어 모델과 외부 서비스 클라이언트 생성 (요청마다 만들지 않고 서버가 떠 있는 동안 재사용)
+	appContainer, err := container.New(cfg, database.DB, sseManager)
+	if err != nil {
+		log.Fatalf("클라이언트 초기화 실패: %v", err)
 	}
 
+	// 임베딩 모델의 벡터 차원이 DB 컬럼과 다르면 기억 저장/검색이 모두 실패하므로 기동 중단
+	checkEmbeddingDimensions(appContainer)
+
+	// 유휴 세션 정리 시작 (만료된 세션은 /chat/stop과 같은 종료 처리)
+	stopSessionReaper := startSessionReaper(cfg, appContainer)
+
 	// Fiber 앱 생성
 	app := fiber.New(fiber.Config{
 		AppName: "Sermo Backend",
@@ -86,10 +148,7 @@ func main() {
 	}))
 
 	// DI 미들웨어 설정
-	app.Use(middleware.ConfigMiddleware(cfg))
-	app.Use(middleware.DatabaseMiddleware(database.DB))
-	app.Use(middleware.R2Middleware(cfg))
-	app.Use(middleware.OpenAIMiddleware(cfg))
+	appContainer.Register(app)
 
 	// 라우터 설정
 	routes.SetupRoutes(app)
@@ -114,11 +173,124 @@ func main() {
 
 	// SSE 세션 정리
 	log.Println("🔄 SSE 세션 정리 중...")
-	sseManager := middleware.GetSSEManager()
+	stopSessionReaper()
 	sseManager.Shutdown()
+	closeSessionBackend()
 
 	if err := app.Shutdown(); err != nil {
 		log.Fatalf("서버 종료 실패: %v", err)
 	}
+	appContainer.Close()
 	log.Println("✅ 서버가 안전하게 종료되었습니다")
 }
+
+// loadConfig 설정 로드
+func loadConfig(migrateOnly bool) (*config.Config, error) {
+	if !migrateOnly {
+		return config.Load()
+	}
+
+	cfg, err := config.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, cfg.ValidateDatabase()
+}
+
+// configureSessionBackend SSE 매니저 생성 (Redis가 활성화되어 있으면 Redis 기반)
+// 반환된 함수는 종료 시 버스와 Redis 연결을 정리한다.
+func configureSessionBackend(cfg *config.Config) (*middleware.SSEManager, func()) {
+	if !cfg.Redis.Enabled {
+		log.Println("ℹ️ Redis 비활성화 - 채팅 세션을 단일 인스턴스 메모리에서 관리합니다")
+		return middleware.NewSSEManager(cfg.Chat.MaxSessions), func() {}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Redis 연결 실패: %v", err)
+	}
+
+	bus := session.NewRedisBus(client)
+	sseManager, err := middleware.NewSSEManagerWithBackend(
+		cfg.Chat.MaxSessions,
+		session.NewRedisRegistry(client, cfg.Redis.SessionTTL),
+		bus,
+	)
+	if err != nil {
+		log.Fatalf("채팅 세션 버스 구독 실패: %v", err)
+	}
+	log.Printf("✅ Redis 채팅 세션 백엔드 설정 완료 - 인스턴스: %s", sseManager.InstanceID())
+
+	return sseManager, func() {
+		bus.Close()
+		client.Close()
+	}
+}
+
+// startSessionReaper 유휴 세션 정리 루프 시작 (반환된 함수로 중단)
+// 만료된 세션은 /chat/stop과 같이 종료 후처리(알람 생성 및 FCM 전송)를 실행한다.
+func startSessionReaper(cfg *config.Config, appContainer *container.Container) func() {
+	onExpired := func(s *middleware.SSESession) {
+		go appContainer.Chat.ProcessChatEnd(appContainer.LLM, s.UserUUID, s.ChatbotUUID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go appContainer.SSE.RunReaper(ctx, cfg.Chat.SessionReapInterval, cfg.Chat.SessionIdleTimeout, onExpired)
+
+	log.Printf("✅ 유휴 세션 정리 시작 - 만료 시간: %s, 확인 주기: %s", cfg.Chat.SessionIdleTimeout, cfg.Chat.SessionReapInterval)
+	return cancel
+}
+
+// checkEmbeddingDimensions 임베딩 차원 확인 (차원이 다르면 종료, 확인 자체가 실패하면 경고만 남김)
+func checkEmbeddingDimensions(appContainer *container.Container) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := appContainer.CheckEmbeddingDimensions(ctx)
+	switch {
+	case errors.Is(err, recall.ErrDimensionMismatch):
+		log.Fatalf("임베딩 차원 불일치 - OPENAI_EMBEDDING_MODEL 또는 LLM_FEATURE_EMBEDDING 설정을 확인하세요: %v", err)
+	case err != nil:
+		log.Printf("⚠️ 임베딩 차원 확인 실패 - 기억 검색이 동작하지 않을 수 있습니다: %v", err)
+	}
+}
+
+// configureJWT 설정의 서명 키와 토큰 유효기간을 pkg/jwt에 적용
+func configureJWT(cfg *config.Config) {
+	jwt.AccessTokenTTL = cfg.JWT.AccessTokenTTL
+	jwt.RefreshTokenTTL = cfg.JWT.RefreshTokenTTL
+
+	keys := make([]jwt.KeyConfig, len(cfg.JWT.Keys))
+	for i, key := range cfg.JWT.Keys {
+		keys[i] = jwt.KeyConfig{
+			ID:            key.ID,
+			Algorithm:     key.Algorithm,
+			Secret:        key.Secret,
+			PrivateKeyPEM: key.PrivateKeyPEM,
+			PublicKeyPEM:  key.PublicKeyPEM,
+		}
+	}
+
+	activeKeyID := cfg.JWT.ActiveKeyID
+	if len(keys) == 0 && cfg.JWT.DevRandomKey {
+		key, err := jwt.NewRandomKey("dev-random")
+		if err != nil {
+			log.Fatalf("JWT 임의 키 생성 실패: %v", err)
+		}
+		keys, activeKeyID = []jwt.KeyConfig{key}, key.ID
+		log.Println("⚠️ JWT_DEV_RANDOM_KEY - 임의의 JWT 키를 사용합니다. 재시작하면 발급한 토큰이 모두 무효가 되고 다른 인스턴스와 공유되지 않습니다 (로컬 개발 전용)")
+	}
+
+	if err := jwt.Configure(&jwt.Config{ActiveKeyID: activeKeyID, Keys: keys}); err != nil {
+		log.Fatalf("JWT 키 설정 실패: %v", err)
+	}
+
+	log.Printf("✅ JWT 키 설정 완료 - 활성 키: %s, 전체 키: %d개", jwt.ActiveKeyID(), len(keys))
+}
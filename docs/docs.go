@@ -24,9 +24,29 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "다른 서비스가 액세스 토큰을 검증할 수 있도록 RS256/EdDSA 서명 키의 공개키를 JWK Set 형식으로 반환합니다. HS256 키는 노출되지 않습니다.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "JWKS 공개키 조회",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/jwt.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
-                "description": "사용자 인증 후 JWT 토큰을 반환합니다.",
+                "description": "사용자 인증 후 액세스 토큰과 리프레시 토큰을 반환합니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -72,6 +92,117 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/logout": {
+            "post": {
+                "description": "리프레시 토큰이 속한 세션을 폐기합니다. 해당 세션으로 발급된 액세스 토큰도 즉시 거부됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "로그아웃",
+                "parameters": [
+                    {
+                        "description": "리프레시 토큰",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "리프레시 토큰으로 새 액세스 토큰과 리프레시 토큰을 발급합니다. 사용된 리프레시 토큰은 폐기되며, 폐기된 토큰이 다시 사용되면 해당 세션 전체가 폐기됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "토큰 갱신",
+                "parameters": [
+                    {
+                        "description": "리프레시 토큰",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/auth/signup": {
             "post": {
                 "description": "새로운 사용자 계정을 생성합니다.",
@@ -163,7 +294,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "새로운 문장 북마크를 생성합니다. 문장은 1-1000자까지 입력 가능합니다.",
+                "description": "새로운 문장 북마크를 생성합니다. 언어 모델을 사용하여 자동으로 한글 뜻을 추출합니다. 문장은 1-1000자까지 입력 가능합니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -205,6 +336,20 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "429": {
+                        "description": "AI 사용 한도 초과",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "언어 모델 오류 또는 북마크 생성 실패",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
@@ -306,7 +451,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "새로운 단어 북마크를 생성합니다. 단어는 1-100자까지 입력 가능합니다.",
+                "description": "새로운 단어 북마크를 생성합니다. 언어 모델을 사용하여 자동으로 한글 뜻을 추출합니다. 단어는 1-100자까지 입력 가능합니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -348,6 +493,20 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "429": {
+                        "description": "AI 사용 한도 초과",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "언어 모델 오류 또는 북마크 생성 실패",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
@@ -406,47 +565,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/history": {
-            "post": {
+        "/chat/conversations": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "특정 채팅봇과의 대화 히스토리를 조회합니다",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "사용자의 채팅봇별 마지막 메시지, 읽지 않은 채팅봇 메시지 수, 채팅 세션 연결 여부를 최근 대화 순으로 조회합니다",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Chat"
                 ],
-                "summary": "채팅 히스토리 조회",
-                "parameters": [
-                    {
-                        "description": "히스토리 요청",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/chat.ChatHistoryRequest"
-                        }
-                    }
-                ],
+                "summary": "대화 목록 조회",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/chat.ChatHistoryResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat.ConversationResponse"
+                            }
                         }
                     },
                     "401": {
@@ -466,14 +607,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/onkeyboard": {
+        "/chat/flush": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "키보드 입력 중임을 알리는 이벤트를 처리합니다",
+                "description": "응답 대기 시간을 기다리지 않고 지금까지 보낸 메시지로 바로 봇 응답을 생성하도록 요청합니다",
                 "consumes": [
                     "application/json"
                 ],
@@ -483,15 +624,15 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "키보드 입력 이벤트",
+                "summary": "즉시 응답 요청",
                 "parameters": [
                     {
-                        "description": "키보드 입력 이벤트 요청",
+                        "description": "즉시 응답 요청",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.OnKeyboardRequest"
+                            "$ref": "#/definitions/chat.FlushRequest"
                         }
                     }
                 ],
@@ -517,6 +658,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "세션 없음",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "AI 사용 한도 초과",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "서버 오류",
                         "schema": {
@@ -527,14 +682,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/send": {
+        "/chat/history": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "기존 세션에 메시지를 전송합니다",
+                "description": "특정 채팅봇과의 대화 히스토리를 조회합니다. 커서가 없으면 가장 최근 메시지부터 limit개를 반환하며, before/after에 메시지 UUID를 넣어 이전/이후 페이지를 조회합니다. session_id, from, to(RFC3339)로 범위를 좁힐 수 있습니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,15 +699,15 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "메시지 전송",
+                "summary": "채팅 히스토리 조회",
                 "parameters": [
                     {
-                        "description": "메시지 요청",
+                        "description": "히스토리 요청",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.SendMessageRequest"
+                            "$ref": "#/definitions/chat.ChatHistoryRequest"
                         }
                     }
                 ],
@@ -560,7 +715,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/chat.SendMessageResponse"
+                            "$ref": "#/definitions/chat.ChatHistoryResponse"
                         }
                     },
                     "400": {
@@ -577,6 +732,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -587,56 +749,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/start": {
-            "get": {
+        "/chat/onkeyboard": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "채팅을 시작하고 SSE 연결을 설정합니다",
+                "description": "키보드 입력 중임을 알리는 이벤트를 처리합니다",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/event-stream"
+                    "application/json"
                 ],
                 "tags": [
                     "Chat"
                 ],
-                "summary": "채팅 시작",
+                "summary": "키보드 입력 이벤트",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "채팅봇 UUID",
-                        "name": "chatbot_uuid",
-                        "in": "query",
-                        "required": true
+                        "description": "키보드 입력 이벤트 요청",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.OnKeyboardRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "SSE 스트림",
+                        "description": "이벤트 전송 성공",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "잘못된 요청",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "인증 실패",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "서버 오류",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -645,14 +810,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/stop": {
+        "/chat/read": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "활성 채팅 세션을 중단합니다",
+                "description": "채팅봇과의 대화에서 읽은 위치를 지정한 메시지(생략 시 마지막 메시지)까지 이동합니다. 읽은 위치는 앞으로만 이동합니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -662,15 +827,15 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "채팅 세션 중단",
+                "summary": "읽음 처리",
                 "parameters": [
                     {
-                        "description": "세션 중단 요청",
+                        "description": "읽음 처리 요청",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.StopChatRequest"
+                            "$ref": "#/definitions/chat.MarkReadRequest"
                         }
                     }
                 ],
@@ -678,8 +843,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/chat.MarkReadResponse"
                         }
                     },
                     "400": {
@@ -702,18 +866,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/chatbot": {
-            "get": {
+        "/chat/search": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "현재 인증된 사용자의 모든 채팅봇 목록을 조회합니다.",
+                "description": "대화 메시지 본문을 전문 검색합니다. chatbot_uuid를 생략하면 모든 채팅봇과의 대화에서 검색하며, 히스토리 조회와 같은 필터와 커서를 사용합니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -721,11 +892,390 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chatbot"
+                    "Chat"
                 ],
-                "summary": "사용자별 채팅봇 목록 조회",
-                "responses": {
-                    "200": {
+                "summary": "채팅 메시지 검색",
+                "parameters": [
+                    {
+                        "description": "검색 요청",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.ChatSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ChatHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "기존 세션에 메시지를 전송합니다",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "메시지 전송",
+                "parameters": [
+                    {
+                        "description": "메시지 요청",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.SendMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.SendMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/start": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "채팅을 시작하고 SSE 연결을 설정합니다. 모든 이벤트에는 id 필드가 붙으며, 연결이 끊긴 뒤 Last-Event-ID 헤더(또는 last_event_id 쿼리)로 다시 연결하면 기존 세션에 붙어 놓친 이벤트를 재전송받습니다. 봇 응답 대기 상태가 바뀔 때마다 reply_state 이벤트(pending, typing, first_message_at, due_at)로 응답 예정 시각을 알립니다. 일정 시간 메시지가 없으면 session_expired 이벤트를 보낸 뒤 세션을 종료합니다. 봇 응답 생성이 재시도와 대체 모델까지 모두 실패하면 타이핑 표시를 끄고 bot_error 이벤트(code, error)를 보냅니다. 동시 세션 수가 가득 차면 queued 이벤트(position, queue_length)로 대기 순번을 알리다가 자리가 나면 이어서 채팅을 시작하며, 대기 시간을 넘기면 queue_timeout 이벤트 후 연결을 종료합니다. 사용자별 세션 수 초과는 429, 대기열까지 가득 차면 503을 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "채팅 시작",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "채팅봇 UUID",
+                        "name": "chatbot_uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "마지막으로 받은 이벤트 ID (재연결 시)",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "마지막으로 받은 이벤트 ID (헤더를 설정할 수 없는 클라이언트용)",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "replace",
+                            "fanout"
+                        ],
+                        "type": "string",
+                        "description": "기존 세션 연결 방식: reject(다른 기기가 연결 중이면 409), replace(기존 연결을 끊고 이어받기), fanout(모든 기기에 같은 대화를 함께 전송). 기본값: reject (Last-Event-ID가 있으면 replace). Redis로 여러 인스턴스를 운영할 때 다른 인스턴스가 가진 세션에는 fanout이 409로 거절되고, replace는 그 세션을 종료한 뒤 이 인스턴스에서 새 세션을 시작하므로 재전송 버퍼가 이어지지 않아 놓친 이벤트는 /chat/history로 다시 조회해야 합니다.",
+                        "name": "takeover",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "봇 응답을 bot_delta 이벤트로 나눠 받고 bot_done으로 마무리 (기본값: false, 완성된 응답을 bot 이벤트로 전송)",
+                        "name": "stream",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSE 스트림",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "활성 채팅 세션을 중단합니다",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "채팅 세션 중단",
+                "parameters": [
+                    {
+                        "description": "세션 중단 요청",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.StopChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "하나의 WebSocket 연결로 채팅을 진행합니다. SSE와 같은 이벤트(user, bot, bot_typing, bot_error, onkeyboard, reply_state, session_expired)를 JSON 텍스트 메시지로 주고받으며, 클라이언트는 {\"type\":\"user\",\"content\":\"...\"}, {\"type\":\"onkeyboard\"}, {\"type\":\"flush\"}, {\"type\":\"stop\"}을 보낼 수 있습니다.",
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "채팅 WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "채팅봇 UUID",
+                        "name": "chatbot_uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "replace",
+                            "fanout"
+                        ],
+                        "type": "string",
+                        "description": "기존 세션 연결 방식: reject(다른 기기가 연결 중이면 거절), replace(기존 연결을 끊고 이어받기), fanout(모든 기기에 같은 대화를 함께 전송). 기본값: reject. Redis로 여러 인스턴스를 운영할 때 다른 인스턴스가 가진 세션에는 fanout이 409로 거절되고, replace는 그 세션을 종료한 뒤 이 인스턴스에서 새 세션을 시작하므로 재전송 버퍼가 이어지지 않아 놓친 이벤트는 /chat/history로 다시 조회해야 합니다.",
+                        "name": "takeover",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "봇 응답을 bot_delta 이벤트로 나눠 받고 bot_done으로 마무리 (기본값: false)",
+                        "name": "stream",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/chatbot": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "현재 인증된 사용자의 모든 채팅봇 목록을 조회합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chatbot"
+                ],
+                "summary": "사용자별 채팅봇 목록 조회",
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
@@ -1268,6 +1818,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "429": {
+                        "description": "AI 사용 한도 초과 (토큰 또는 이미지 수)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "서버 오류 (OpenAI/Gemini API 오류 등)",
                         "schema": {
@@ -1483,12 +2040,51 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "사용자 프로필 조회",
+                "summary": "사용자 프로필 조회",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "현재 사용자의 오늘/이번 달 AI 사용량(토큰, 생성 이미지 수)과 한도, 이번 달 기능·모델별 내역을 조회합니다. 기간은 UTC 기준이며 한도가 0이면 무제한입니다.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "AI 사용량 조회",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.ProfileResponse"
+                            "$ref": "#/definitions/usage.Report"
                         }
                     },
                     "401": {
@@ -1498,8 +2094,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1513,6 +2109,10 @@ const docTemplate = `{
         "auth.LoginRequest": {
             "type": "object",
             "properties": {
+                "device_info": {
+                    "description": "선택 (비워두면 User-Agent 사용)",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1524,7 +2124,16 @@ const docTemplate = `{
         "auth.LoginResponse": {
             "type": "object",
             "properties": {
+                "expires_in": {
+                    "description": "액세스 토큰 만료까지 남은 초",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "리프레시 토큰 (1회용, 사용 시 교체됨)",
+                    "type": "string"
+                },
                 "token": {
+                    "description": "액세스 토큰",
                     "type": "string"
                 },
                 "user": {
@@ -1543,6 +2152,40 @@ const docTemplate = `{
                 }
             }
         },
+        "auth.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "device_info": {
+                    "description": "선택 (비워두면 기존 값 유지)",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "auth.SignUpRequest": {
             "type": "object",
             "properties": {
@@ -1622,6 +2265,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "meaning": {
+                    "type": "string"
+                },
                 "sentence": {
                     "type": "string"
                 },
@@ -1636,6 +2282,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "meaning": {
+                    "type": "string"
+                },
                 "uuid": {
                     "type": "string"
                 },
@@ -1650,23 +2299,52 @@ const docTemplate = `{
                 "chatbot_uuid"
             ],
             "properties": {
+                "after": {
+                    "description": "이 메시지 UUID보다 이후 메시지 조회",
+                    "type": "string"
+                },
+                "before": {
+                    "description": "이 메시지 UUID보다 이전 메시지 조회",
+                    "type": "string"
+                },
                 "chatbot_uuid": {
                     "type": "string"
                 },
+                "from": {
+                    "description": "RFC3339, 이 시각 이후 (포함)",
+                    "type": "string"
+                },
                 "limit": {
                     "type": "integer",
                     "maximum": 100,
                     "minimum": 1
                 },
-                "offset": {
-                    "type": "integer",
-                    "minimum": 0
+                "session_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "RFC3339, 이 시각 이전 (미포함)",
+                    "type": "string"
                 }
             }
         },
         "chat.ChatHistoryResponse": {
             "type": "object",
             "properties": {
+                "after_cursor": {
+                    "description": "이후 페이지 조회 시 after로 전달",
+                    "type": "string"
+                },
+                "before_cursor": {
+                    "description": "이전 페이지 조회 시 before로 전달",
+                    "type": "string"
+                },
+                "has_more_after": {
+                    "type": "boolean"
+                },
+                "has_more_before": {
+                    "type": "boolean"
+                },
                 "messages": {
                     "type": "array",
                     "items": {
@@ -1679,6 +2357,104 @@ const docTemplate = `{
             }
         },
         "chat.ChatMessageResponse": {
+            "type": "object",
+            "properties": {
+                "chatbot_uuid": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat.ChatSearchRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "after": {
+                    "description": "이 메시지 UUID보다 이후 메시지 조회",
+                    "type": "string"
+                },
+                "before": {
+                    "description": "이 메시지 UUID보다 이전 메시지 조회",
+                    "type": "string"
+                },
+                "chatbot_uuid": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "RFC3339, 이 시각 이후 (포함)",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "query": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "RFC3339, 이 시각 이전 (미포함)",
+                    "type": "string"
+                }
+            }
+        },
+        "chat.ConversationResponse": {
+            "type": "object",
+            "properties": {
+                "chatbot_uuid": {
+                    "type": "string"
+                },
+                "image_id": {
+                    "type": "string"
+                },
+                "last_message": {
+                    "description": "대화가 없으면 null",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.LastMessageResponse"
+                        }
+                    ]
+                },
+                "live": {
+                    "description": "채팅 세션이 열려 있는지",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat.FlushRequest": {
+            "type": "object",
+            "properties": {
+                "chatbot_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat.LastMessageResponse": {
             "type": "object",
             "properties": {
                 "content": {
@@ -1695,6 +2471,35 @@ const docTemplate = `{
                 }
             }
         },
+        "chat.MarkReadRequest": {
+            "type": "object",
+            "required": [
+                "chatbot_uuid"
+            ],
+            "properties": {
+                "chatbot_uuid": {
+                    "type": "string"
+                },
+                "message_uuid": {
+                    "description": "비어 있으면 마지막 메시지까지 읽음 처리",
+                    "type": "string"
+                }
+            }
+        },
+        "chat.MarkReadResponse": {
+            "type": "object",
+            "properties": {
+                "last_read_at": {
+                    "type": "string"
+                },
+                "last_read_message_uuid": {
+                    "type": "string"
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "chat.OnKeyboardRequest": {
             "type": "object",
             "properties": {
@@ -1826,9 +2631,21 @@ const docTemplate = `{
                     "description": "사진 ID",
                     "type": "string"
                 },
+                "max_reply_wait_ms": {
+                    "description": "첫 메시지부터 최대 대기 시간",
+                    "type": "integer"
+                },
                 "name": {
                     "description": "채팅봇 이름 (3-100자)",
                     "type": "string"
+                },
+                "reply_delay_ms": {
+                    "description": "마지막 메시지 후 응답까지 대기 시간",
+                    "type": "integer"
+                },
+                "typing_extension_ms": {
+                    "description": "입력 중 이벤트마다 연장하는 시간",
+                    "type": "integer"
                 }
             }
         },
@@ -1873,9 +2690,21 @@ const docTemplate = `{
                     "description": "사진 ID",
                     "type": "string"
                 },
+                "max_reply_wait_ms": {
+                    "description": "첫 메시지부터 최대 대기 시간",
+                    "type": "integer"
+                },
                 "name": {
                     "description": "채팅봇 이름 (3-100자)",
                     "type": "string"
+                },
+                "reply_delay_ms": {
+                    "description": "마지막 메시지 후 응답까지 대기 시간",
+                    "type": "integer"
+                },
+                "typing_extension_ms": {
+                    "description": "입력 중 이벤트마다 연장하는 시간",
+                    "type": "integer"
                 }
             }
         },
@@ -1985,6 +2814,46 @@ const docTemplate = `{
                 }
             }
         },
+        "jwt.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwt.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwt.JWK"
+                    }
+                }
+            }
+        },
         "models.FCMToken": {
             "type": "object",
             "properties": {
@@ -2038,6 +2907,97 @@ const docTemplate = `{
                 }
             }
         },
+        "usage.FeatureUsage": {
+            "type": "object",
+            "properties": {
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "feature": {
+                    "description": "answer, validation, status, summary, alarm, bookmark, image_prompt, image, embedding",
+                    "type": "string"
+                },
+                "images": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usage.PeriodUsage": {
+            "type": "object",
+            "properties": {
+                "image_limit": {
+                    "description": "0이면 무제한",
+                    "type": "integer"
+                },
+                "images_left": {
+                    "description": "한도가 있을 때만",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "daily 또는 monthly",
+                    "type": "string"
+                },
+                "reset_at": {
+                    "description": "한도가 초기화되는 시각",
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "token_limit": {
+                    "description": "0이면 무제한",
+                    "type": "integer"
+                },
+                "tokens_left": {
+                    "description": "한도가 있을 때만",
+                    "type": "integer"
+                },
+                "used": {
+                    "$ref": "#/definitions/usage.Totals"
+                }
+            }
+        },
+        "usage.Report": {
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "$ref": "#/definitions/usage.PeriodUsage"
+                },
+                "features": {
+                    "description": "이번 달 기능·모델별 내역",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usage.FeatureUsage"
+                    }
+                },
+                "monthly": {
+                    "$ref": "#/definitions/usage.PeriodUsage"
+                }
+            }
+        },
+        "usage.Totals": {
+            "type": "object",
+            "properties": {
+                "images": {
+                    "type": "integer"
+                },
+                "tokens": {
+                    "type": "integer"
+                }
+            }
+        },
         "user.ProfileResponse": {
             "type": "object",
             "properties": {
@@ -1,90 +1,552 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"sermo-be/pkg/llm"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	R2       R2Config
-	Gemini   GeminiConfig
-	OpenAI   OpenAIConfig
-	Firebase FirebaseConfig
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Chat     ChatConfig     `yaml:"chat"`
+	R2       R2Config       `yaml:"r2"`
+	Gemini   GeminiConfig   `yaml:"gemini"`
+	OpenAI   OpenAIConfig   `yaml:"openai"`
+	LLM      LLMConfig      `yaml:"llm"`
+	Quota    QuotaConfig    `yaml:"quota"`
+	Firebase FirebaseConfig `yaml:"firebase"`
+	JWT      JWTConfig      `yaml:"jwt"`
+
+	// envProblems 해석하지 못한 환경변수 (Validate에서 함께 보고)
+	envProblems []string
 }
 
 type ServerConfig struct {
-	Port string
-	Host string
+	Port string `yaml:"port"`
+	Host string `yaml:"host"`
+	Env  string `yaml:"env"` // development, production
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"db_name"`
+	SSLMode  string `yaml:"ssl_mode"`
+	// MigrateOnStart 서버 기동 시 미적용 마이그레이션 자동 실행 여부
+	// 별도 마이그레이션 잡(cmd/migrate)을 운영하는 환경에서는 false로 둔다.
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+// RedisConfig 다중 인스턴스 채팅 세션 레지스트리/메시지 버스용 Redis 설정
+// 비활성화하면 세션을 프로세스 메모리에서만 관리한다 (단일 인스턴스 배포).
+type RedisConfig struct {
+	Enabled    bool          `yaml:"enabled"`
+	Host       string        `yaml:"host"`
+	Port       string        `yaml:"port"`
+	Password   string        `yaml:"password"`
+	DB         int           `yaml:"db"`
+	SessionTTL time.Duration `yaml:"session_ttl"` // 갱신되지 않은 세션 등록 만료 시간
+}
+
+// ChatConfig 채팅 봇 응답 대기(debounce) 기본값, 세션 만료, 동시 세션 제한 및 대화 기억 설정
+// 응답 대기 값은 채팅봇별 설정이 있으면 그 값이 우선한다.
+type ChatConfig struct {
+	ReplyDelay      time.Duration `yaml:"reply_delay"`      // 마지막 메시지 후 응답까지 기본 대기 시간
+	TypingExtension time.Duration `yaml:"typing_extension"` // 입력 중 이벤트를 받을 때마다 연장하는 대기 시간
+	MaxReplyWait    time.Duration `yaml:"max_reply_wait"`   // 첫 메시지부터 응답까지 최대 대기 시간
+
+	SessionIdleTimeout  time.Duration `yaml:"session_idle_timeout"`  // 사용자/봇 메시지가 없으면 세션을 만료시키는 시간
+	SessionReapInterval time.Duration `yaml:"session_reap_interval"` // 유휴 세션 정리 주기
+
+	MaxSessions        int           `yaml:"max_sessions"`          // 인스턴스당 최대 동시 세션 수
+	MaxSessionsPerUser int           `yaml:"max_sessions_per_user"` // 사용자별 최대 동시 세션 수, 세션 레지스트리 기준으로 모든 인스턴스 합산 (0이면 제한 없음)
+	MaxQueueLength     int           `yaml:"max_queue_length"`      // 자리가 없을 때 대기할 수 있는 최대 인원 (0이면 대기 없이 거절)
+	QueueTimeout       time.Duration `yaml:"queue_timeout"`         // 대기열 최대 대기 시간
+
+	HistoryTokenBudget   int `yaml:"history_token_budget"`   // 봇 응답 생성 시 넣는 최근 대화의 최대 토큰 수 (추정치)
+	MemorySummarizeAfter int `yaml:"memory_summarize_after"` // 요약되지 않은 메시지가 이 수에 도달하면 오래된 메시지를 요약
+	MemoryKeepRecent     int `yaml:"memory_keep_recent"`     // 요약할 때 원문으로 남겨두는 최근 메시지 수
 }
 
 type R2Config struct {
-	AccessKeyID     string
-	SecretAccessKey string
-	Endpoint        string
-	Bucket          string
+	Enabled         bool   `yaml:"enabled"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	Endpoint        string `yaml:"endpoint"`
+	Bucket          string `yaml:"bucket"`
 }
 
 type GeminiConfig struct {
-	APIKey     string
-	ImageSize  string
-	ImageStyle string
+	Enabled    bool   `yaml:"enabled"`
+	APIKey     string `yaml:"api_key"`
+	ImageSize  string `yaml:"image_size"`
+	ImageStyle string `yaml:"image_style"`
 }
 
 type OpenAIConfig struct {
-	APIKey              string
-	Model               string
-	MaxCompletionTokens int
+	Enabled             bool   `yaml:"enabled"`
+	APIKey              string `yaml:"api_key"`
+	Model               string `yaml:"model"`
+	MaxCompletionTokens int    `yaml:"max_completion_tokens"`
+	EmbeddingModel      string `yaml:"embedding_model"` // 대화 기억 검색용 임베딩 모델 (1536차원 벡터를 반환해야 함)
+
+	// 호출 안정성 (openai 종류의 모든 제공자에 적용)
+	FallbackModels   []string      `yaml:"fallback_models"`   // 채팅 모델이 계속 실패할 때 순서대로 시도할 대체 모델
+	MaxAttempts      int           `yaml:"max_attempts"`      // 모델별 최대 시도 횟수 (429, 5xx, 네트워크 오류일 때만 재시도)
+	CallTimeout      time.Duration `yaml:"call_timeout"`      // 시도 한 번의 제한 시간
+	BreakerThreshold int           `yaml:"breaker_threshold"` // 회로를 여는 연속 실패 횟수 (음수면 회로 차단 안 함)
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // 회로가 열린 뒤 다시 시험 호출까지 기다리는 시간
+}
+
+// LLMConfig 기능별 언어 모델 선택
+// openai 설정은 항상 "openai" 제공자로 사용할 수 있고, 그 외 제공자는 providers에 이름을 붙여 추가한다.
+type LLMConfig struct {
+	Providers map[string]LLMProviderConfig `yaml:"providers"`
+	// Features 기능(chat, status, summary, alarm, bookmark, image_prompt, embedding)별 제공자 이름 (지정하지 않은 기능은 "openai")
+	Features map[string]string `yaml:"features"`
+}
+
+// 언어 모델 제공자 종류
+const (
+	LLMProviderOpenAI     = "openai"     // OpenAI API (openai 설정과 같은 방식)
+	LLMProviderCompatible = "compatible" // OpenAI 호환 API를 제공하는 자체 호스팅 서버 (vLLM, Ollama 등)
+	LLMProviderFake       = "fake"       // 네트워크 없이 결정적으로 응답하는 테스트용 모델
+)
+
+// DefaultLLMProvider 기능별 제공자를 지정하지 않았을 때 사용하는 제공자 (openai 설정)
+const DefaultLLMProvider = "openai"
+
+// ProviderFor 기능에 지정된 제공자 이름
+func (c LLMConfig) ProviderFor(feature llm.Feature) string {
+	if name := c.Features[string(feature)]; name != "" {
+		return name
+	}
+	return DefaultLLMProvider
+}
+
+// LLMProviderConfig 이름으로 참조하는 언어 모델 제공자
+type LLMProviderConfig struct {
+	Type                string `yaml:"type"` // openai, compatible, fake
+	BaseURL             string `yaml:"base_url"`
+	APIKey              string `yaml:"api_key"`
+	Model               string `yaml:"model"`
+	MaxCompletionTokens int    `yaml:"max_completion_tokens"`
+	EmbeddingModel      string `yaml:"embedding_model"`
+	// FallbackModels openai 종류에서 채팅 모델이 계속 실패할 때 시도할 대체 모델
+	FallbackModels []string `yaml:"fallback_models"`
+}
+
+// QuotaConfig 사용자별 AI 사용 한도 (UTC 기준 일/월, 0이면 무제한)
+type QuotaConfig struct {
+	DailyTokens   int `yaml:"daily_tokens"`
+	MonthlyTokens int `yaml:"monthly_tokens"`
+	DailyImages   int `yaml:"daily_images"`
+	MonthlyImages int `yaml:"monthly_images"`
 }
 
 type FirebaseConfig struct {
-	ProjectID           string
-	PrivateKeyID        string
-	PrivateKey          string
-	ClientEmail         string
-	ClientID            string
-	AuthURI             string
-	TokenURI            string
-	AuthProviderCertURL string
-	ClientCertURL       string
-	UniverseDomain      string
+	Enabled             bool   `yaml:"enabled"`
+	ProjectID           string `yaml:"project_id"`
+	PrivateKeyID        string `yaml:"private_key_id"`
+	PrivateKey          string `yaml:"private_key"`
+	ClientEmail         string `yaml:"client_email"`
+	ClientID            string `yaml:"client_id"`
+	AuthURI             string `yaml:"auth_uri"`
+	TokenURI            string `yaml:"token_uri"`
+	AuthProviderCertURL string `yaml:"auth_provider_cert_url"`
+	ClientCertURL       string `yaml:"client_cert_url"`
+	UniverseDomain      string `yaml:"universe_domain"`
+}
+
+// JWTConfig JWT 서명 키 및 토큰 유효기간 설정
+type JWTConfig struct {
+	ActiveKeyID     string         `yaml:"active_kid"`
+	Keys            []JWTKeyConfig `yaml:"keys"`
+	AccessTokenTTL  time.Duration  `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration  `yaml:"refresh_token_ttl"`
+
+	// DevRandomKey 키가 없을 때 시작할 때마다 임의의 비밀키를 만들어 사용 (로컬 개발 전용, 운영 환경에서는 금지)
+	DevRandomKey bool `yaml:"dev_random_key"`
 }
 
-func Load() *Config {
-	return &Config{}
+// JWTKeyConfig kid로 식별되는 개별 서명 키
+type JWTKeyConfig struct {
+	ID            string `yaml:"kid"`
+	Algorithm     string `yaml:"alg"` // HS256, RS256, EdDSA
+	Secret        string `yaml:"secret"`
+	PrivateKeyPEM string `yaml:"private_key"`
+	PublicKeyPEM  string `yaml:"public_key"`
 }
 
-func getEnv(key, defaultValue string) string {
+// Load 설정 로드
+// 기본값 → 설정 파일(CONFIG_FILE, YAML) → 환경변수 순으로 적용하며 뒤의 값이 우선한다.
+// 활성화된 기능의 필수 값이 없으면 누락 항목을 모두 모아 에러로 반환한다.
+func Load() (*Config, error) {
+	cfg, err := Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Parse 검증 없이 설정만 로드 (데이터베이스만 필요한 cmd/migrate 등에서 사용)
+func Parse() (*Config, error) {
+	cfg := defaultConfig()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	applyEnv(cfg)
+	return cfg, nil
+}
+
+// IsProduction 운영 환경 여부
+func (c *Config) IsProduction() bool {
+	return c.Server.Env == "production"
+}
+
+// defaultConfig 기본 설정값
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: "3000",
+			Host: "localhost",
+			Env:  "development",
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    "5432",
+			User:    "postgres",
+			DBName:  "sermo",
+			SSLMode: "disable",
+			// 기존 동작 유지: 기동 시 스키마 최신화
+			MigrateOnStart: true,
+		},
+		Redis: RedisConfig{
+			Enabled:    false,
+			Host:       "localhost",
+			Port:       "6379",
+			SessionTTL: 90 * time.Second,
+		},
+		Chat: ChatConfig{
+			ReplyDelay:      4 * time.Second,
+			TypingExtension: 5 * time.Second,
+			MaxReplyWait:    15 * time.Second,
+
+			SessionIdleTimeout:  30 * time.Minute,
+			SessionReapInterval: time.Minute,
+
+			MaxSessions:        20,
+			MaxSessionsPerUser: 3,
+			MaxQueueLength:     100,
+			QueueTimeout:       5 * time.Minute,
+
+			HistoryTokenBudget:   1500,
+			MemorySummarizeAfter: 40,
+			MemoryKeepRecent:     20,
+		},
+		R2:     R2Config{Enabled: true},
+		Gemini: GeminiConfig{Enabled: true},
+		OpenAI: OpenAIConfig{
+			Enabled:             true,
+			Model:               "gpt-5-nano-2025-08-07",
+			MaxCompletionTokens: 2048,
+			EmbeddingModel:      "text-embedding-3-small",
+			MaxAttempts:         3,
+			CallTimeout:         60 * time.Second,
+			BreakerThreshold:    5,
+			BreakerCooldown:     30 * time.Second,
+		},
+		Firebase: FirebaseConfig{
+			Enabled:        true,
+			AuthURI:        "https://accounts.google.com/o/oauth2/auth",
+			TokenURI:       "https://oauth2.googleapis.com/token",
+			UniverseDomain: "googleapis.com",
+		},
+		JWT: JWTConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+	}
+}
+
+// loadFile YAML 설정 파일을 기존 설정 위에 덮어쓰기
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv 설정된 환경변수로 값 덮어쓰기 (없는 값은 기존 값 유지)
+// 형식이 잘못된 값이나 읽을 수 없는 *_FILE 경로는 기존 값을 유지하되 Validate에서 보고하도록 기록한다.
+func applyEnv(cfg *Config) {
+	env := &envReader{}
+
+	cfg.Server.Port = env.getEnv("PORT", cfg.Server.Port)
+	cfg.Server.Host = env.getEnv("HOST", cfg.Server.Host)
+	cfg.Server.Env = env.getEnv("APP_ENV", cfg.Server.Env)
+
+	cfg.Database.Host = env.getEnv("DB_HOST", cfg.Database.Host)
+	cfg.Database.Port = env.getEnv("DB_PORT", cfg.Database.Port)
+	cfg.Database.User = env.getEnv("DB_USER", cfg.Database.User)
+	cfg.Database.Password = env.getEnv("DB_PASSWORD", cfg.Database.Password)
+	cfg.Database.DBName = env.getEnv("DB_NAME", cfg.Database.DBName)
+	cfg.Database.SSLMode = env.getEnv("DB_SSLMODE", cfg.Database.SSLMode)
+	cfg.Database.MigrateOnStart = env.getEnvAsBool("DB_MIGRATE_ON_START", cfg.Database.MigrateOnStart)
+
+	cfg.Redis.Enabled = env.getEnvAsBool("REDIS_ENABLED", cfg.Redis.Enabled)
+	cfg.Redis.Host = env.getEnv("REDIS_HOST", cfg.Redis.Host)
+	cfg.Redis.Port = env.getEnv("REDIS_PORT", cfg.Redis.Port)
+	cfg.Redis.Password = env.getEnv("REDIS_PASSWORD", cfg.Redis.Password)
+	cfg.Redis.DB = env.getEnvAsInt("REDIS_DB", cfg.Redis.DB)
+	cfg.Redis.SessionTTL = env.getEnvAsDuration("REDIS_SESSION_TTL", cfg.Redis.SessionTTL)
+
+	cfg.Chat.ReplyDelay = env.getEnvAsDuration("CHAT_REPLY_DELAY", cfg.Chat.ReplyDelay)
+	cfg.Chat.TypingExtension = env.getEnvAsDuration("CHAT_TYPING_EXTENSION", cfg.Chat.TypingExtension)
+	cfg.Chat.MaxReplyWait = env.getEnvAsDuration("CHAT_MAX_REPLY_WAIT", cfg.Chat.MaxReplyWait)
+	cfg.Chat.SessionIdleTimeout = env.getEnvAsDuration("CHAT_SESSION_IDLE_TIMEOUT", cfg.Chat.SessionIdleTimeout)
+	cfg.Chat.SessionReapInterval = env.getEnvAsDuration("CHAT_SESSION_REAP_INTERVAL", cfg.Chat.SessionReapInterval)
+	cfg.Chat.MaxSessions = env.getEnvAsInt("CHAT_MAX_SESSIONS", cfg.Chat.MaxSessions)
+	cfg.Chat.MaxSessionsPerUser = env.getEnvAsInt("CHAT_MAX_SESSIONS_PER_USER", cfg.Chat.MaxSessionsPerUser)
+	cfg.Chat.MaxQueueLength = env.getEnvAsInt("CHAT_MAX_QUEUE_LENGTH", cfg.Chat.MaxQueueLength)
+	cfg.Chat.QueueTimeout = env.getEnvAsDuration("CHAT_QUEUE_TIMEOUT", cfg.Chat.QueueTimeout)
+	cfg.Chat.HistoryTokenBudget = env.getEnvAsInt("CHAT_HISTORY_TOKEN_BUDGET", cfg.Chat.HistoryTokenBudget)
+	cfg.Chat.MemorySummarizeAfter = env.getEnvAsInt("CHAT_MEMORY_SUMMARIZE_AFTER", cfg.Chat.MemorySummarizeAfter)
+	cfg.Chat.MemoryKeepRecent = env.getEnvAsInt("CHAT_MEMORY_KEEP_RECENT", cfg.Chat.MemoryKeepRecent)
+
+	cfg.R2.Enabled = env.getEnvAsBool("R2_ENABLED", cfg.R2.Enabled)
+	cfg.R2.AccessKeyID = env.getEnv("R2_ACCESS_KEY_ID", cfg.R2.AccessKeyID)
+	cfg.R2.SecretAccessKey = env.getEnv("R2_SECRET_ACCESS_KEY", cfg.R2.SecretAccessKey)
+	cfg.R2.Endpoint = env.getEnv("R2_ENDPOINT", cfg.R2.Endpoint)
+	cfg.R2.Bucket = env.getEnv("R2_BUCKET", cfg.R2.Bucket)
+
+	cfg.Gemini.Enabled = env.getEnvAsBool("GEMINI_ENABLED", cfg.Gemini.Enabled)
+	cfg.Gemini.APIKey = env.getEnv("GEMINI_API_KEY", cfg.Gemini.APIKey)
+	cfg.Gemini.ImageSize = env.getEnv("GEMINI_IMAGE_SIZE", cfg.Gemini.ImageSize)
+	cfg.Gemini.ImageStyle = env.getEnv("GEMINI_IMAGE_STYLE", cfg.Gemini.ImageStyle)
+
+	cfg.OpenAI.Enabled = env.getEnvAsBool("OPENAI_ENABLED", cfg.OpenAI.Enabled)
+	cfg.OpenAI.APIKey = env.getEnv("OPENAI_API_KEY", cfg.OpenAI.APIKey)
+	cfg.OpenAI.Model = env.getEnv("OPENAI_MODEL", cfg.OpenAI.Model)
+	cfg.OpenAI.MaxCompletionTokens = env.getEnvAsInt("OPENAI_MAX_COMPLETION_TOKENS", cfg.OpenAI.MaxCompletionTokens)
+	cfg.OpenAI.EmbeddingModel = env.getEnv("OPENAI_EMBEDDING_MODEL", cfg.OpenAI.EmbeddingModel)
+	cfg.OpenAI.FallbackModels = env.getEnvAsList("OPENAI_FALLBACK_MODELS", cfg.OpenAI.FallbackModels)
+	cfg.OpenAI.MaxAttempts = env.getEnvAsInt("OPENAI_MAX_ATTEMPTS", cfg.OpenAI.MaxAttempts)
+	cfg.OpenAI.CallTimeout = env.getEnvAsDuration("OPENAI_CALL_TIMEOUT", cfg.OpenAI.CallTimeout)
+	cfg.OpenAI.BreakerThreshold = env.getEnvAsInt("OPENAI_BREAKER_THRESHOLD", cfg.OpenAI.BreakerThreshold)
+	cfg.OpenAI.BreakerCooldown = env.getEnvAsDuration("OPENAI_BREAKER_COOLDOWN", cfg.OpenAI.BreakerCooldown)
+
+	applyLLMEnv(&cfg.LLM, env)
+
+	cfg.Quota.DailyTokens = env.getEnvAsInt("QUOTA_DAILY_TOKENS", cfg.Quota.DailyTokens)
+	cfg.Quota.MonthlyTokens = env.getEnvAsInt("QUOTA_MONTHLY_TOKENS", cfg.Quota.MonthlyTokens)
+	cfg.Quota.DailyImages = env.getEnvAsInt("QUOTA_DAILY_IMAGES", cfg.Quota.DailyImages)
+	cfg.Quota.MonthlyImages = env.getEnvAsInt("QUOTA_MONTHLY_IMAGES", cfg.Quota.MonthlyImages)
+
+	cfg.Firebase.Enabled = env.getEnvAsBool("FIREBASE_ENABLED", cfg.Firebase.Enabled)
+	cfg.Firebase.ProjectID = env.getEnv("FIREBASE_PROJECT_ID", cfg.Firebase.ProjectID)
+	cfg.Firebase.PrivateKeyID = env.getEnv("FIREBASE_PRIVATE_KEY_ID", cfg.Firebase.PrivateKeyID)
+	cfg.Firebase.PrivateKey = env.getEnvOrFile("FIREBASE_PRIVATE_KEY", cfg.Firebase.PrivateKey)
+	cfg.Firebase.ClientEmail = env.getEnv("FIREBASE_CLIENT_EMAIL", cfg.Firebase.ClientEmail)
+	cfg.Firebase.ClientID = env.getEnv("FIREBASE_CLIENT_ID", cfg.Firebase.ClientID)
+	cfg.Firebase.AuthURI = env.getEnv("FIREBASE_AUTH_URI", cfg.Firebase.AuthURI)
+	cfg.Firebase.TokenURI = env.getEnv("FIREBASE_TOKEN_URI", cfg.Firebase.TokenURI)
+	cfg.Firebase.AuthProviderCertURL = env.getEnv("FIREBASE_AUTH_PROVIDER_CERT_URL", cfg.Firebase.AuthProviderCertURL)
+	cfg.Firebase.ClientCertURL = env.getEnv("FIREBASE_CLIENT_CERT_URL", cfg.Firebase.ClientCertURL)
+	cfg.Firebase.UniverseDomain = env.getEnv("FIREBASE_UNIVERSE_DOMAIN", cfg.Firebase.UniverseDomain)
+
+	applyJWTEnv(&cfg.JWT, env)
+
+	cfg.envProblems = env.problems
+}
+
+// applyJWTEnv 환경변수에서 JWT 키 설정 로드
+// JWT_KEYS=kid1,kid2 형태로 키 목록을 지정하고 키별로 JWT_KEY_<KID>_* 값을 읽는다.
+// JWT_KEYS가 없으면 JWT_SECRET 하나를 HS256 키로 사용한다. 둘 다 없으면 설정 파일의 키를 유지한다.
+func applyJWTEnv(cfg *JWTConfig, env *envReader) {
+	cfg.ActiveKeyID = env.getEnv("JWT_ACTIVE_KID", cfg.ActiveKeyID)
+	cfg.AccessTokenTTL = env.getEnvAsDuration("JWT_ACCESS_TOKEN_TTL", cfg.AccessTokenTTL)
+	cfg.RefreshTokenTTL = env.getEnvAsDuration("JWT_REFRESH_TOKEN_TTL", cfg.RefreshTokenTTL)
+	cfg.DevRandomKey = env.getEnvAsBool("JWT_DEV_RANDOM_KEY", cfg.DevRandomKey)
+
+	var keys []JWTKeyConfig
+	for _, kid := range strings.Split(env.getEnv("JWT_KEYS", ""), ",") {
+		kid = strings.TrimSpace(kid)
+		if kid == "" {
+			continue
+		}
+
+		prefix := "JWT_KEY_" + strings.ToUpper(strings.ReplaceAll(kid, "-", "_")) + "_"
+		keys = append(keys, JWTKeyConfig{
+			ID:            kid,
+			Algorithm:     env.getEnv(prefix+"ALG", "HS256"),
+			Secret:        env.getEnvOrFile(prefix+"SECRET", ""),
+			PrivateKeyPEM: env.getEnvOrFile(prefix+"PRIVATE_KEY", ""),
+			PublicKeyPEM:  env.getEnvOrFile(prefix+"PUBLIC_KEY", ""),
+		})
+	}
+
+	if len(keys) == 0 {
+		if secret := env.getEnvOrFile("JWT_SECRET", ""); secret != "" {
+			keys = append(keys, JWTKeyConfig{
+				ID:        env.getEnv("JWT_SECRET_KID", "default"),
+				Algorithm: "HS256",
+				Secret:    secret,
+			})
+		}
+	}
+
+	if len(keys) > 0 {
+		cfg.Keys = keys
+	}
+}
+
+// applyLLMEnv 환경변수에서 언어 모델 제공자와 기능별 선택 로드
+// LLM_PROVIDERS=local,small 형태로 제공자 목록을 지정하고 제공자별로 LLM_PROVIDER_<NAME>_* 값을 읽는다.
+// 기능별 제공자는 LLM_FEATURE_<FEATURE>=<name>으로 지정한다 (예: LLM_FEATURE_STATUS=small).
+func applyLLMEnv(cfg *LLMConfig, env *envReader) {
+	for _, name := range strings.Split(env.getEnv("LLM_PROVIDERS", ""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "LLM_PROVIDER_" + envName(name) + "_"
+		provider := cfg.Providers[name]
+		provider.Type = env.getEnv(prefix+"TYPE", provider.Type)
+		provider.BaseURL = env.getEnv(prefix+"BASE_URL", provider.BaseURL)
+		provider.APIKey = env.getEnvOrFile(prefix+"API_KEY", provider.APIKey)
+		provider.Model = env.getEnv(prefix+"MODEL", provider.Model)
+		provider.MaxCompletionTokens = env.getEnvAsInt(prefix+"MAX_COMPLETION_TOKENS", provider.MaxCompletionTokens)
+		provider.EmbeddingModel = env.getEnv(prefix+"EMBEDDING_MODEL", provider.EmbeddingModel)
+		provider.FallbackModels = env.getEnvAsList(prefix+"FALLBACK_MODELS", provider.FallbackModels)
+
+		if cfg.Providers == nil {
+			cfg.Providers = make(map[string]LLMProviderConfig)
+		}
+		cfg.Providers[name] = provider
+	}
+
+	for _, feature := range llm.Features {
+		if provider := env.getEnv("LLM_FEATURE_"+envName(string(feature)), ""); provider != "" {
+			if cfg.Features == nil {
+				cfg.Features = make(map[string]string)
+			}
+			cfg.Features[string(feature)] = provider
+		}
+	}
+}
+
+// envName 환경변수 이름에 쓰는 형태로 변환 (대문자, '-'는 '_')
+func envName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// envReader 환경변수 값을 읽으며 해석할 수 없는 값을 문제 목록에 모음
+type envReader struct {
+	problems []string
+}
+
+func (env *envReader) invalid(key, kind, value string, err error) {
+	env.problems = append(env.problems, fmt.Sprintf("%s must be a valid %s (got %q: %v)", key, kind, value, err))
+}
+
+func (env *envReader) getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvOrFile 환경변수 값 또는 <KEY>_FILE 경로의 파일 내용 반환 (PEM 키 등)
+func (env *envReader) getEnvOrFile(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			env.problems = append(env.problems, fmt.Sprintf("%s_FILE: cannot read %s: %v", key, path, err))
+			return defaultValue
+		}
+		return string(data)
+	}
+	return defaultValue
+}
+
+func (env *envReader) getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			env.invalid(key, "boolean", value, err)
+			return defaultValue
+		}
+		return b
+	}
 	return defaultValue
 }
 
-func getEnvAsFloat(key string, defaultValue float64) float64 {
+func (env *envReader) getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
-		if f, err := strconv.ParseFloat(value, 64); err == nil {
-			return f
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			env.invalid(key, "duration", value, err)
+			return defaultValue
 		}
+		return d
 	}
 	return defaultValue
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
+// getEnvAsList 쉼표로 구분한 환경변수 값 목록 (빈 항목 제외)
+func (env *envReader) getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (env *envReader) getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
-		if i, err := strconv.Atoi(value); err == nil {
-			return i
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			env.invalid(key, "integer", value, err)
+			return defaultValue
 		}
+		return i
 	}
 	return defaultValue
 }
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testEnvKeys 테스트가 다루는 환경변수 (실행 환경의 값이 섞이지 않도록 비워 둠, 빈 값은 설정되지 않은 것으로 취급)
+var testEnvKeys = []string{
+	"CONFIG_FILE", "PORT", "APP_ENV", "DB_HOST", "DB_NAME", "DB_MIGRATE_ON_START",
+	"CHAT_REPLY_DELAY", "CHAT_MAX_SESSIONS",
+	"R2_ENABLED", "GEMINI_ENABLED", "OPENAI_ENABLED", "FIREBASE_ENABLED",
+	"JWT_KEYS", "JWT_SECRET", "JWT_SECRET_FILE", "JWT_ACCESS_TOKEN_TTL", "JWT_DEV_RANDOM_KEY", "LLM_PROVIDERS",
+}
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range testEnvKeys {
+		t.Setenv(key, "")
+	}
+}
+
+// disableServices 외부 서비스 설정 검증을 끄고 나머지 항목만 검증
+func disableServices(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"R2_ENABLED", "GEMINI_ENABLED", "OPENAI_ENABLED", "FIREBASE_ENABLED"} {
+		t.Setenv(key, "false")
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	return path
+}
+
+func validationProblems(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error = %v, want *ValidationError", err)
+	}
+	return verr.Problems
+}
+
+func hasProblem(problems []string, substr string) bool {
+	for _, problem := range problems {
+		if strings.Contains(problem, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseAppliesFileThenEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, `
+server:
+  port: "4000"
+database:
+  host: db.internal
+chat:
+  reply_delay: 2s
+  max_sessions: 50
+`))
+	t.Setenv("PORT", "5000")
+	t.Setenv("CHAT_MAX_SESSIONS", "80")
+
+	cfg, err := Parse()
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"env overrides file", cfg.Server.Port, "5000"},
+		{"env overrides file (int)", cfg.Chat.MaxSessions, 80},
+		{"file overrides default", cfg.Database.Host, "db.internal"},
+		{"file overrides default (duration)", cfg.Chat.ReplyDelay, 2 * time.Second},
+		{"default kept", cfg.Database.DBName, "sermo"},
+		{"default kept (duration)", cfg.Chat.TypingExtension, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_MIGRATE_ON_START", "sometimes")
+	t.Setenv("CHAT_MAX_SESSIONS", "many")
+	t.Setenv("JWT_ACCESS_TOKEN_TTL", "15")
+	t.Setenv("JWT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("DB_HOST", "")
+
+	_, err := Load()
+	problems := validationProblems(t, err)
+
+	want := []string{
+		"DB_MIGRATE_ON_START must be a valid boolean",
+		"CHAT_MAX_SESSIONS must be a valid integer",
+		"JWT_ACCESS_TOKEN_TTL must be a valid duration",
+		"JWT_SECRET_FILE: cannot read",
+		"R2_ACCESS_KEY_ID (r2.access_key_id) is required",
+		"GEMINI_API_KEY (gemini.api_key) is required",
+		"OPENAI_API_KEY (openai.api_key) is required",
+		"FIREBASE_PROJECT_ID (firebase.project_id) is required",
+	}
+	for _, substr := range want {
+		if !hasProblem(problems, substr) {
+			t.Errorf("missing problem %q in %q", substr, problems)
+		}
+	}
+}
+
+func TestValidateDatabaseReportsEnvProblems(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_MIGRATE_ON_START", "sometimes")
+
+	cfg, err := Parse()
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !cfg.Database.MigrateOnStart {
+		t.Error("MigrateOnStart should keep the default when the value is malformed")
+	}
+	if problems := validationProblems(t, cfg.ValidateDatabase()); !hasProblem(problems, "DB_MIGRATE_ON_START") {
+		t.Errorf("ValidateDatabase problems = %q, want DB_MIGRATE_ON_START", problems)
+	}
+}
+
+func TestLoadRequiresJWTKeys(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "jwt-secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantErr    bool
+		wantRandom bool
+	}{
+		{"development without keys", map[string]string{}, true, false},
+		{"development with random key opt-in", map[string]string{"JWT_DEV_RANDOM_KEY": "true"}, false, false},
+		{"production without keys", map[string]string{"APP_ENV": "production"}, true, false},
+		{"production with random key opt-in", map[string]string{"APP_ENV": "production", "JWT_DEV_RANDOM_KEY": "true"}, true, true},
+		{"production with JWT_SECRET", map[string]string{"APP_ENV": "production", "JWT_SECRET": "secret"}, false, false},
+		{"production with JWT_SECRET_FILE", map[string]string{"APP_ENV": "production", "JWT_SECRET_FILE": secretFile}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			disableServices(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			problems := validationProblems(t, err)
+			if got := hasProblem(problems, "JWT_SECRET or JWT_KEYS") || hasProblem(problems, "JWT_DEV_RANDOM_KEY"); got != tt.wantErr {
+				t.Fatalf("JWT key problem = %v, want %v (problems: %q)", got, tt.wantErr, problems)
+			}
+			if got := hasProblem(problems, "JWT_DEV_RANDOM_KEY"); got != tt.wantRandom {
+				t.Fatalf("random key problem = %v, want %v (problems: %q)", got, tt.wantRandom, problems)
+			}
+			if !tt.wantErr && len(problems) > 0 {
+				t.Fatalf("unexpected problems: %q", problems)
+			}
+			if tt.env["JWT_SECRET_FILE"] != "" && cfg.JWT.Keys[0].Secret != "file-secret" {
+				t.Errorf("secret = %q, want the file contents", cfg.JWT.Keys[0].Secret)
+			}
+		})
+	}
+}
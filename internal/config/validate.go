@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sermo-be/pkg/llm"
+)
+
+// ValidationError 누락되거나 잘못된 설정 항목 모음
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("invalid configuration (%d problems):", len(e.Problems)))
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem)
+	}
+	return b.String()
+}
+
+// Validate 활성화된 기능별 필수 설정 검증
+// 처음 발견한 항목에서 멈추지 않고 모든 문제를 한 번에 보고한다.
+func (c *Config) Validate() error {
+	v := &validator{}
+	v.problems = append(v.problems, c.envProblems...)
+
+	v.require(c.Server.Port, "PORT", "server.port")
+	c.validateDatabase(v)
+
+	if c.Redis.Enabled {
+		v.require(c.Redis.Host, "REDIS_HOST", "redis.host")
+		v.require(c.Redis.Port, "REDIS_PORT", "redis.port")
+		// 세션 등록은 30초마다 갱신되므로 그보다 충분히 길어야 한다
+		if c.Redis.SessionTTL < time.Minute {
+			v.invalid("REDIS_SESSION_TTL (redis.session_ttl) must be at least 1m")
+		}
+	}
+
+	if c.Chat.ReplyDelay <= 0 || c.Chat.TypingExtension < 0 {
+		v.invalid("CHAT_REPLY_DELAY must be positive and CHAT_TYPING_EXTENSION must not be negative")
+	}
+	if c.Chat.MaxReplyWait < c.Chat.ReplyDelay {
+		v.invalid("CHAT_MAX_REPLY_WAIT (chat.max_reply_wait) must be at least CHAT_REPLY_DELAY")
+	}
+	if c.Chat.SessionIdleTimeout <= 0 || c.Chat.SessionReapInterval <= 0 {
+		v.invalid("CHAT_SESSION_IDLE_TIMEOUT and CHAT_SESSION_REAP_INTERVAL must be positive")
+	}
+	if c.Chat.MaxSessions <= 0 {
+		v.invalid("CHAT_MAX_SESSIONS (chat.max_sessions) must be positive")
+	}
+	if c.Chat.MaxSessionsPerUser < 0 || c.Chat.MaxQueueLength < 0 {
+		v.invalid("CHAT_MAX_SESSIONS_PER_USER and CHAT_MAX_QUEUE_LENGTH must not be negative")
+	}
+	if c.Chat.MaxQueueLength > 0 && c.Chat.QueueTimeout <= 0 {
+		v.invalid("CHAT_QUEUE_TIMEOUT (chat.queue_timeout) must be positive when the waiting room is enabled")
+	}
+	if c.Chat.HistoryTokenBudget <= 0 {
+		v.invalid("CHAT_HISTORY_TOKEN_BUDGET (chat.history_token_budget) must be positive")
+	}
+	if c.Chat.MemoryKeepRecent < 0 || c.Chat.MemorySummarizeAfter <= c.Chat.MemoryKeepRecent {
+		v.invalid("CHAT_MEMORY_SUMMARIZE_AFTER must be greater than CHAT_MEMORY_KEEP_RECENT (which must not be negative)")
+	}
+
+	if c.R2.Enabled {
+		v.require(c.R2.AccessKeyID, "R2_ACCESS_KEY_ID", "r2.access_key_id")
+		v.require(c.R2.SecretAccessKey, "R2_SECRET_ACCESS_KEY", "r2.secret_access_key")
+		v.require(c.R2.Endpoint, "R2_ENDPOINT", "r2.endpoint")
+		v.require(c.R2.Bucket, "R2_BUCKET", "r2.bucket")
+	}
+
+	if c.Gemini.Enabled {
+		v.require(c.Gemini.APIKey, "GEMINI_API_KEY", "gemini.api_key")
+	}
+
+	if c.OpenAI.Enabled {
+		v.require(c.OpenAI.APIKey, "OPENAI_API_KEY", "openai.api_key")
+		if c.OpenAI.MaxCompletionTokens <= 0 {
+			v.invalid("OPENAI_MAX_COMPLETION_TOKENS (openai.max_completion_tokens) must be positive")
+		}
+		if c.OpenAI.MaxAttempts <= 0 {
+			v.invalid("OPENAI_MAX_ATTEMPTS (openai.max_attempts) must be positive")
+		}
+		if c.OpenAI.CallTimeout <= 0 || c.OpenAI.BreakerCooldown <= 0 {
+			v.invalid("OPENAI_CALL_TIMEOUT / OPENAI_BREAKER_COOLDOWN must be positive durations")
+		}
+	}
+
+	c.validateLLM(v)
+
+	if c.Quota.DailyTokens < 0 || c.Quota.MonthlyTokens < 0 || c.Quota.DailyImages < 0 || c.Quota.MonthlyImages < 0 {
+		v.invalid("QUOTA_* (quota.*) limits must not be negative (0 means unlimited)")
+	}
+
+	if c.Firebase.Enabled {
+		v.require(c.Firebase.ProjectID, "FIREBASE_PROJECT_ID", "firebase.project_id")
+		v.require(c.Firebase.PrivateKeyID, "FIREBASE_PRIVATE_KEY_ID", "firebase.private_key_id")
+		v.require(c.Firebase.PrivateKey, "FIREBASE_PRIVATE_KEY", "firebase.private_key")
+		v.require(c.Firebase.ClientEmail, "FIREBASE_CLIENT_EMAIL", "firebase.client_email")
+	}
+
+	// 서명 키는 항상 필요 (로컬 개발에서만 JWT_DEV_RANDOM_KEY로 임의 키 허용)
+	if len(c.JWT.Keys) == 0 && !c.JWT.DevRandomKey {
+		v.missing("JWT_SECRET or JWT_KEYS", "jwt.keys")
+	}
+	if c.IsProduction() && c.JWT.DevRandomKey {
+		v.invalid("JWT_DEV_RANDOM_KEY (jwt.dev_random_key) must not be set in production")
+	}
+	for _, key := range c.JWT.Keys {
+		if key.ID == "" {
+			v.invalid("jwt.keys: every key needs a kid")
+		}
+	}
+	if c.JWT.AccessTokenTTL <= 0 || c.JWT.RefreshTokenTTL <= 0 {
+		v.invalid("JWT_ACCESS_TOKEN_TTL / JWT_REFRESH_TOKEN_TTL must be positive durations")
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// ValidateDatabase 데이터베이스 접속 설정만 검증 (해석하지 못한 환경변수도 함께 보고)
+func (c *Config) ValidateDatabase() error {
+	v := &validator{}
+	v.problems = append(v.problems, c.envProblems...)
+	c.validateDatabase(v)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (c *Config) validateDatabase(v *validator) {
+	v.require(c.Database.Host, "DB_HOST", "database.host")
+	v.require(c.Database.Port, "DB_PORT", "database.port")
+	v.require(c.Database.User, "DB_USER", "database.user")
+	v.require(c.Database.DBName, "DB_NAME", "database.db_name")
+}
+
+// validateLLM 언어 모델 제공자와 기능별 선택 검증
+func (c *Config) validateLLM(v *validator) {
+	for name, provider := range c.LLM.Providers {
+		prefix := "LLM_PROVIDER_" + envName(name) + "_"
+		fileKey := "llm.providers." + name + "."
+		switch provider.Type {
+		case LLMProviderOpenAI:
+			v.require(provider.APIKey, prefix+"API_KEY", fileKey+"api_key")
+		case LLMProviderCompatible:
+			v.require(provider.BaseURL, prefix+"BASE_URL", fileKey+"base_url")
+			v.require(provider.Model, prefix+"MODEL", fileKey+"model")
+		case LLMProviderFake:
+		default:
+			v.invalid(fmt.Sprintf("%sTYPE (%stype) must be one of openai, compatible, fake", prefix, fileKey))
+		}
+		if provider.MaxCompletionTokens < 0 {
+			v.invalid(fmt.Sprintf("%sMAX_COMPLETION_TOKENS (%smax_completion_tokens) must not be negative", prefix, fileKey))
+		}
+	}
+
+	known := make(map[string]bool, len(llm.Features))
+	for _, feature := range llm.Features {
+		known[string(feature)] = true
+	}
+	for feature, name := range c.LLM.Features {
+		if !known[feature] {
+			v.invalid(fmt.Sprintf("llm.features.%s is not a known feature", feature))
+			continue
+		}
+		if _, ok := c.LLM.Providers[name]; ok {
+			continue
+		}
+		if name == DefaultLLMProvider && c.OpenAI.Enabled {
+			continue
+		}
+		v.invalid(fmt.Sprintf("LLM_FEATURE_%s (llm.features.%s) refers to unknown or disabled provider %q", envName(feature), feature, name))
+	}
+}
+
+// validator 검증 문제 수집기
+type validator struct {
+	problems []string
+}
+
+func (v *validator) require(value, envKey, fileKey string) {
+	if strings.TrimSpace(value) == "" {
+		v.missing(envKey, fileKey)
+	}
+}
+
+func (v *validator) missing(envKey, fileKey string) {
+	v.problems = append(v.problems, fmt.Sprintf("%s (%s) is required", envKey, fileKey))
+}
+
+func (v *validator) invalid(message string) {
+	v.problems = append(v.problems, message)
+}
@@ -0,0 +1,149 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"sermo-be/internal/config"
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/core/usage"
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/firebase"
+	"sermo-be/pkg/gemini"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/r2"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Container 서버가 떠 있는 동안 재사용하는 설정, 외부 서비스 클라이언트, 토큰/사용량/채팅 서비스
+// main에서 한 번 만들고, Register로 요청 컨텍스트에 주입하며, 종료 시 Close로 정리한다.
+// 비활성화된 기능의 클라이언트는 nil이다.
+type Container struct {
+	Config   *config.Config
+	DB       *gorm.DB
+	LLM      *llm.Router
+	R2       *r2.Client
+	Gemini   *gemini.ImageClient
+	Firebase *firebase.Client
+	Repos    *repository.Repositories
+	Tokens   *token.TokenService
+	Usage    *usage.UsageService
+	Chat     *chat.Services
+	SSE      *middleware.SSEManager
+}
+
+// New 설정에 따라 클라이언트와 채팅 서비스를 만든 Container 생성
+// 채팅 서비스는 db 기반 저장소를 사용하며, 세션 관리는 sse(Redis 백엔드 여부는 main에서 결정)에 맡긴다.
+func New(cfg *config.Config, db *gorm.DB, sse *middleware.SSEManager) (*Container, error) {
+	c := &Container{Config: cfg, DB: db, SSE: sse}
+
+	router, err := middleware.NewLLMRouter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure LLM router: %w", err)
+	}
+	c.LLM = router
+
+	if cfg.R2.Enabled {
+		c.R2, err = r2.NewClient(&r2.Config{
+			AccessKeyID:     cfg.R2.AccessKeyID,
+			SecretAccessKey: cfg.R2.SecretAccessKey,
+			Endpoint:        cfg.R2.Endpoint,
+			Bucket:          cfg.R2.Bucket,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create R2 client: %w", err)
+		}
+	}
+
+	if cfg.Gemini.Enabled {
+		c.Gemini, err = gemini.NewImageClient(&gemini.Config{APIKey: cfg.Gemini.APIKey})
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+	}
+
+	if cfg.Firebase.Enabled {
+		// FCM은 필수 기능이 아니므로 실패해도 서버는 계속 실행
+		if c.Firebase, err = firebase.NewClient(cfg); err != nil {
+			log.Printf("⚠️ Firebase 클라이언트 생성 실패 - 푸시 알림 비활성화: %v", err)
+		}
+	}
+
+	// 사용자별 AI 사용 한도 (0이면 무제한)
+	c.Usage = usage.NewUsageService(db, quotaFromConfig(cfg))
+	c.Tokens = token.NewTokenService(db)
+
+	c.Repos = repository.NewGormRepositories(db)
+	var push chat.PushSender
+	if c.Firebase != nil {
+		push = chat.NewFCMSender(c.Firebase, c.Repos.FCMTokens)
+	}
+	c.Chat = chat.NewServices(c.Repos, db, recall.NewService(recall.NewPgStore(db)), c.Usage, push)
+
+	return c, nil
+}
+
+// CheckEmbeddingDimensions 임베딩 모델의 벡터 차원이 memory_embeddings.embedding 컬럼 차원과 같은지 확인
+// 임베딩 모델이 없으면 기억 검색을 하지 않으므로 확인하지 않는다.
+func (c *Container) CheckEmbeddingDimensions(ctx context.Context) error {
+	embedder := c.LLM.For(llm.FeatureEmbedding)
+	if embedder == nil {
+		return nil
+	}
+
+	dimensions, err := recall.NewPgStore(c.DB).Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+	// 기동 시 확인은 사용자 요청이 아니므로 시스템 사용량으로 기록
+	return recall.CheckDimensions(ctx, recall.Tracked(embedder, c.Usage, usage.SystemUserUUID), dimensions)
+}
+
+// quotaFromConfig 설정의 사용자별 AI 사용 한도
+func quotaFromConfig(cfg *config.Config) usage.Quota {
+	return usage.Quota{
+		DailyTokens:   int64(cfg.Quota.DailyTokens),
+		MonthlyTokens: int64(cfg.Quota.MonthlyTokens),
+		DailyImages:   int64(cfg.Quota.DailyImages),
+		MonthlyImages: int64(cfg.Quota.MonthlyImages),
+	}
+}
+
+// Register 설정, DB, 클라이언트, 서비스를 요청 컨텍스트에 주입하는 미들웨어 등록
+func (c *Container) Register(app *fiber.App) {
+	app.Use(middleware.ConfigMiddleware(c.Config))
+	app.Use(middleware.DatabaseMiddleware(c.DB))
+	if c.R2 != nil {
+		app.Use(middleware.R2Middleware(c.R2))
+	}
+	if c.Gemini != nil {
+		app.Use(middleware.GeminiMiddleware(c.Gemini))
+	}
+	if c.Firebase != nil {
+		app.Use(middleware.FirebaseMiddleware(c.Firebase))
+	}
+	app.Use(middleware.LLMMiddleware(c.LLM))
+	app.Use(middleware.TokenServiceMiddleware(c.Tokens))
+	app.Use(middleware.UsageMiddleware(c.Usage))
+	app.Use(middleware.SSEManagerMiddleware(c.SSE))
+	app.Use(chat.ServicesMiddleware(c.Chat))
+}
+
+// Close 클라이언트 리소스 정리 (서버 종료 시)
+func (c *Container) Close() {
+	if c.Gemini != nil {
+		c.Gemini.Close()
+	}
+	if c.Firebase != nil {
+		if err := c.Firebase.Close(); err != nil {
+			log.Printf("Firebase 클라이언트 정리 실패: %v", err)
+		}
+	}
+}
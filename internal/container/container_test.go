@@ -0,0 +1,139 @@
+package container
+
+import (
+	"testing"
+
+	"sermo-be/internal/config"
+	"sermo-be/internal/middleware"
+	"sermo-be/pkg/gemini"
+	"sermo-be/pkg/r2"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		R2: config.R2Config{
+			Enabled:         true,
+			AccessKeyID:     "test",
+			SecretAccessKey: "test",
+			Endpoint:        "http://localhost:9000",
+			Bucket:          "test",
+		},
+		Gemini: config.GeminiConfig{Enabled: true, APIKey: "test"},
+	}
+}
+
+// newTestApp 요청마다 R2/Gemini 클라이언트를 꺼내 쓰는 핸들러가 있는 앱
+func newTestApp(register func(app *fiber.App), seen func(*r2.Client, *gemini.ImageClient)) *fiber.App {
+	app := fiber.New()
+	register(app)
+	app.Get("/", func(c *fiber.Ctx) error {
+		seen(middleware.GetR2Client(c), middleware.GetGeminiClient(c))
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestContainerReusesClients(t *testing.T) {
+	c, err := New(testConfig(), nil, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	var r2Clients []*r2.Client
+	var geminiClients []*gemini.ImageClient
+	app := newTestApp(c.Register, func(r *r2.Client, g *gemini.ImageClient) {
+		r2Clients = append(r2Clients, r)
+		geminiClients = append(geminiClients, g)
+	})
+
+	handler := app.Handler()
+	for i := 0; i < 2; i++ {
+		var ctx fasthttp.RequestCtx
+		ctx.Request.SetRequestURI("/")
+		handler(&ctx)
+	}
+
+	if len(r2Clients) != 2 || r2Clients[0] == nil || r2Clients[0] != r2Clients[1] || r2Clients[0] != c.R2 {
+		t.Errorf("r2 clients = %v, want the container's client on every request", r2Clients)
+	}
+	if len(geminiClients) != 2 || geminiClients[0] == nil || geminiClients[0] != geminiClients[1] {
+		t.Errorf("gemini clients = %v, want the container's client on every request", geminiClients)
+	}
+}
+
+func TestContainerSkipsDisabledClients(t *testing.T) {
+	c, err := New(&config.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	if c.R2 != nil || c.Gemini != nil || c.Firebase != nil || c.LLM.For("chat") != nil {
+		t.Errorf("disabled clients should be nil: %+v", c)
+	}
+	// 푸시 전송이 없어도 토큰/사용량/채팅 서비스는 만들어져야 함
+	if c.Chat == nil || c.Repos == nil || c.Tokens == nil || c.Usage == nil {
+		t.Error("services not built")
+	}
+}
+
+// benchmarkRequests 클라이언트를 꺼내 쓰는 요청을 반복 처리
+func benchmarkRequests(b *testing.B, register func(app *fiber.App)) {
+	app := newTestApp(register, func(r *r2.Client, g *gemini.ImageClient) {
+		if r == nil || g == nil {
+			b.Fatal("client not injected")
+		}
+	})
+	handler := app.Handler()
+
+	var ctx fasthttp.RequestCtx
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler(&ctx)
+	}
+}
+
+// BenchmarkPerRequestClients 요청마다 클라이언트를 만들던 이전 방식
+func BenchmarkPerRequestClients(b *testing.B) {
+	cfg := testConfig()
+	benchmarkRequests(b, func(app *fiber.App) {
+		app.Use(func(c *fiber.Ctx) error {
+			r2Client, err := r2.NewClient(&r2.Config{
+				AccessKeyID:     cfg.R2.AccessKeyID,
+				SecretAccessKey: cfg.R2.SecretAccessKey,
+				Endpoint:        cfg.R2.Endpoint,
+				Bucket:          cfg.R2.Bucket,
+			})
+			if err != nil {
+				return err
+			}
+			geminiClient, err := gemini.NewImageClient(&gemini.Config{APIKey: cfg.Gemini.APIKey})
+			if err != nil {
+				return err
+			}
+			defer geminiClient.Close()
+
+			c.Locals("r2_client", r2Client)
+			c.Locals("gemini_client", geminiClient)
+			return c.Next()
+		})
+	})
+}
+
+// BenchmarkContainerClients 서버 시작 시 만든 클라이언트를 주입하는 방식
+func BenchmarkContainerClients(b *testing.B) {
+	c, err := New(testConfig(), nil, nil)
+	if err != nil {
+		b.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	benchmarkRequests(b, c.Register)
+}
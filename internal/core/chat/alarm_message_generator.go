@@ -5,15 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/openai"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
 	"sermo-be/pkg/prompt"
 	"strings"
 	"time"
-
-	"gorm.io/gorm"
 )
 
+// keywordsFormat 키워드 추출 응답 형식
+var keywordsFormat = llm.JSONSchema("alarm_keywords", `{
+	"type": "object",
+	"properties": {
+		"keywords": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["keywords"],
+	"additionalProperties": false
+}`)
+
+// alarmFormat 알람 메시지와 전송 시간 응답 형식
+var alarmFormat = llm.JSONSchema("alarm_message", `{
+	"type": "object",
+	"properties": {
+		"message": {"type": "string"},
+		"send_time": {"type": "string", "description": "YYYY-MM-DD HH:MM:SS"}
+	},
+	"required": ["message", "send_time"],
+	"additionalProperties": false
+}`)
+
 type AlarmMessageConfig struct {
 	UserUUID    string
 	ChatbotUUID string
@@ -24,51 +45,73 @@ type AlarmMessage struct {
 	SendTime time.Time
 }
 
-func AlarmMessageGeneate(openaiClient *openai.Client, db *gorm.DB, config AlarmMessageConfig) (AlarmMessage, error) {
+// AlarmMessageGenerator 채팅 종료 후 보낼 알람 메시지 생성 및 저장
+type AlarmMessageGenerator struct {
+	statuses repository.UserStatusRepository
+	chatbots repository.ChatbotRepository
+	users    repository.UserRepository
+	messages repository.ChatMessageRepository
+	alarms   repository.AlarmRepository
+	usage    UsageTracker
+}
 
-	var userStatuses []models.UserStatus
-	err := db.Where("user_uuid = ? AND chatbot_uuid = ? AND valid_until > ?",
-		config.UserUUID, config.ChatbotUUID, time.Now()).Find(&userStatuses).Error
+// NewAlarmMessageGenerator 새로운 AlarmMessageGenerator 생성
+func NewAlarmMessageGenerator(repos *repository.Repositories, tracker UsageTracker) *AlarmMessageGenerator {
+	return &AlarmMessageGenerator{
+		statuses: repos.Statuses,
+		chatbots: repos.Chatbots,
+		users:    repos.Users,
+		messages: repos.Messages,
+		alarms:   repos.Alarms,
+		usage:    tracker,
+	}
+}
+
+// Generate 사용자 상태 정보와 최근 대화로 알람 메시지를 만들고 알람 스케줄로 저장
+func (g *AlarmMessageGenerator) Generate(model llm.LLM, config AlarmMessageConfig) (AlarmMessage, error) {
+	userStatuses, err := g.statuses.ListValid(config.UserUUID, config.ChatbotUUID, time.Now())
 	if err != nil {
 		return AlarmMessage{}, err
 	}
 
-	var chatbot models.Chatbot
-	err = db.Where("uuid = ?", config.ChatbotUUID).First(&chatbot).Error
+	chatbot, err := g.chatbots.FindByUUID(config.ChatbotUUID)
 	if err != nil {
 		return AlarmMessage{}, err
 	}
 
 	// 유저 정보 조회
-	var user models.User
-	err = db.Where("uuid = ?", config.UserUUID).First(&user).Error
+	user, err := g.users.FindByUUID(config.UserUUID)
 	if err != nil {
 		return AlarmMessage{}, err
 	}
 
-	var chatHistory []models.ChatMessage
-	err = db.Where("user_uuid = ? AND chatbot_uuid = ?",
-		config.UserUUID, config.ChatbotUUID).Order("created_at DESC").Limit(10).Find(&chatHistory).Error
+	// 최근 대화 10개 (최신순)
+	chatHistory, err := g.messages.Recent(config.UserUUID, config.ChatbotUUID, nil, 10)
 	if err != nil {
 		return AlarmMessage{}, err
 	}
+	reverseMessages(chatHistory)
 
 	// 프롬프트 구성
-	summaryPrompt := prompt.BuildSummaryPrompt(userStatuses, &chatbot, chatHistory)
-
-	response, err := openaiClient.ChatCompletion(context.Background(), []openai.ChatMessage{
-		{
-			Role:    "system",
-			Content: "You are an alarm message generator. Extract 2 key keywords and create an alarm message based on the user's status and chat history.",
-		},
-		{
-			Role:    "user",
-			Content: summaryPrompt,
+	summaryPrompt := prompt.BuildSummaryPrompt(userStatuses, chatbot, chatHistory)
+
+	response, err := model.ChatCompletionWithOptions(context.Background(), llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{
+				Role:    "system",
+				Content: "You are an alarm message generator. Extract 2 key keywords and create an alarm message based on the user's status and chat history.",
+			},
+			{
+				Role:    "user",
+				Content: summaryPrompt,
+			},
 		},
+		ResponseFormat: keywordsFormat,
 	})
 	if err != nil {
 		return AlarmMessage{}, err
 	}
+	g.usage.RecordChat(config.UserUUID, usage.FeatureAlarm, model, response)
 
 	// AI 응답 파싱
 	keywords := parseKeywords(response.Message.Content)
@@ -77,7 +120,7 @@ func AlarmMessageGeneate(openaiClient *openai.Client, db *gorm.DB, config AlarmM
 	log.Printf("🔑 추출된 키워드: %v", keywords)
 
 	// 키워드와 최신 userStatus를 가지고 알람 메시지 생성
-	alarmMessage, sendTime := generatePersonalizedAlarmMessage(openaiClient, keywords, userStatuses, &chatbot, &user)
+	alarmMessage, sendTime := g.generatePersonalizedAlarmMessage(model, keywords, userStatuses, chatbot, user)
 
 	// keywords를 JSON으로 직렬화
 	keywordsJSON, err := json.Marshal(keywords)
@@ -108,7 +151,7 @@ func AlarmMessageGeneate(openaiClient *openai.Client, db *gorm.DB, config AlarmM
 	}
 
 	// 데이터베이스에 알람 스케줄 저장
-	if err := db.Create(alarmSchedule).Error; err != nil {
+	if err := g.alarms.Create(alarmSchedule); err != nil {
 		log.Printf("❌ 알람 스케줄 데이터베이스 저장 실패: %v", err)
 	} else {
 		log.Printf("✅ 알람 스케줄 데이터베이스 저장 성공 - 전송 시간: %s", sendTime.Format("2006-01-02 15:04:05"))
@@ -121,14 +164,41 @@ func AlarmMessageGeneate(openaiClient *openai.Client, db *gorm.DB, config AlarmM
 }
 
 // parseKeywords AI 응답에서 키워드만 파싱
+// {"keywords": [...]} JSON 응답을 우선 사용하고, 형식 지정을 따르지 않은 응답은 "Keywords: a, b" 줄에서 찾는다.
 func parseKeywords(aiResponse string) []string {
+	var keywords []string
+
+	var result struct {
+		Keywords []string `json:"keywords"`
+	}
+	if err := llm.DecodeJSON(aiResponse, &result); err == nil {
+		for _, keyword := range result.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+
+	if len(keywords) == 0 {
+		keywords = parseKeywordLines(aiResponse)
+	}
+
+	// 키워드가 없으면 기본값 설정
+	if len(keywords) == 0 {
+		keywords = []string{"", ""}
+	}
+
+	return keywords
+}
+
+// parseKeywordLines "Keywords: [keyword1], [keyword2]" 형식의 줄에서 키워드 파싱
+func parseKeywordLines(aiResponse string) []string {
 	lines := strings.Split(aiResponse, "\n")
 	var keywords []string
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "Keywords:") {
-			// Keywords: [keyword1], [keyword2] 형식 파싱
 			keywordPart := strings.TrimPrefix(line, "Keywords:")
 			keywordPart = strings.TrimSpace(keywordPart)
 			keywordList := strings.Split(keywordPart, ",")
@@ -141,16 +211,11 @@ func parseKeywords(aiResponse string) []string {
 		}
 	}
 
-	// 키워드가 없으면 기본값 설정
-	if len(keywords) == 0 {
-		keywords = []string{"", ""}
-	}
-
 	return keywords
 }
 
 // generatePersonalizedAlarmMessage OpenAI를 이용해서 개인화된 알람 메시지 생성
-func generatePersonalizedAlarmMessage(openaiClient *openai.Client, keywords []string, userStatuses []models.UserStatus, chatbot *models.Chatbot, user *models.User) (string, time.Time) {
+func (g *AlarmMessageGenerator) generatePersonalizedAlarmMessage(model llm.LLM, keywords []string, userStatuses []models.UserStatus, chatbot *models.Chatbot, user *models.User) (string, time.Time) {
 	if len(userStatuses) == 0 {
 		return "You have a scheduled reminder.", time.Now().Add(1 * time.Hour)
 	}
@@ -158,15 +223,18 @@ func generatePersonalizedAlarmMessage(openaiClient *openai.Client, keywords []st
 	// 1차: 개인화된 프롬프트로 기본 메시지 생성
 	initialPrompt := prompt.BuildPersonalizedAlarmPrompt(keywords, userStatuses, chatbot, user)
 
-	initialResponse, err := openaiClient.ChatCompletion(context.Background(), []openai.ChatMessage{
-		{
-			Role:    "system",
-			Content: "You are a friendly alarm message generator. Create personalized, character-appropriate alarm messages and suggest the best time to send them.",
-		},
-		{
-			Role:    "user",
-			Content: initialPrompt,
+	initialResponse, err := model.ChatCompletionWithOptions(context.Background(), llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{
+				Role:    "system",
+				Content: "You are a friendly alarm message generator. Create personalized, character-appropriate alarm messages and suggest the best time to send them.",
+			},
+			{
+				Role:    "user",
+				Content: initialPrompt,
+			},
 		},
+		ResponseFormat: alarmFormat,
 	})
 
 	if err != nil {
@@ -176,6 +244,7 @@ func generatePersonalizedAlarmMessage(openaiClient *openai.Client, keywords []st
 		}
 		return fmt.Sprintf("Hi %s! You have a scheduled reminder.", user.Nickname), time.Now().Add(1 * time.Hour)
 	}
+	g.usage.RecordChat(user.UUID.String(), usage.FeatureAlarm, model, initialResponse)
 
 	// 1차 응답에서 메시지와 시간 파싱
 	var initialMessage string
@@ -184,7 +253,7 @@ func generatePersonalizedAlarmMessage(openaiClient *openai.Client, keywords []st
 	if len(userStatuses) > 0 {
 		initialMessage, sendTime = parseAIResponseWithTime(initialResponse.Message.Content, userStatuses[0])
 		// 2차: 1차 결과를 더 구체적이고 개인화된 메시지로 재생성
-		finalMessage := generateEnhancedAlarmMessage(openaiClient, initialMessage, userStatuses[0], chatbot, user, keywords)
+		finalMessage := g.generateEnhancedAlarmMessage(model, initialMessage, userStatuses[0], chatbot, user, keywords)
 		return finalMessage, sendTime
 	} else {
 		// userStatuses가 비어있는 경우 기본 메시지 반환
@@ -193,7 +262,35 @@ func generatePersonalizedAlarmMessage(openaiClient *openai.Client, keywords []st
 }
 
 // parseAIResponseWithTime AI 응답에서 메시지와 시간을 파싱
+// {"message": ..., "send_time": ...} JSON 응답을 우선 사용하고, 형식 지정을 따르지 않은 응답은 첫 줄과 "Send Time:" 줄에서 찾는다.
 func parseAIResponseWithTime(aiResponse string, userStatus models.UserStatus) (string, time.Time) {
+	var message string
+	var sendTime time.Time
+
+	var result struct {
+		Message  string `json:"message"`
+		SendTime string `json:"send_time"`
+	}
+	if err := llm.DecodeJSON(aiResponse, &result); err == nil && strings.TrimSpace(result.Message) != "" {
+		message = strings.TrimSpace(result.Message)
+		sendTime = parseSuggestedTime(result.SendTime, userStatus)
+	} else {
+		message, sendTime = parseMessageLines(aiResponse, userStatus)
+	}
+
+	// 메시지나 시간이 없으면 기본값 설정
+	if message == "" {
+		message = "You have a scheduled reminder."
+	}
+	if sendTime.IsZero() {
+		sendTime = time.Now().Add(1 * time.Hour)
+	}
+
+	return message, sendTime
+}
+
+// parseMessageLines 첫 번째 비어있지 않은 줄을 메시지로, "Send Time: [시간]" 줄을 전송 시간으로 파싱
+func parseMessageLines(aiResponse string, userStatus models.UserStatus) (string, time.Time) {
 	lines := strings.Split(aiResponse, "\n")
 	var message string
 	var sendTime time.Time
@@ -201,27 +298,15 @@ func parseAIResponseWithTime(aiResponse string, userStatus models.UserStatus) (s
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "Send Time:") {
-			// Send Time: [시간] 형식 파싱
 			timePart := strings.TrimPrefix(line, "Send Time:")
 			timePart = strings.TrimSpace(timePart)
 			// AI가 제안한 시간을 파싱 (예: "tomorrow at midnight", "1 hour before event" 등)
 			sendTime = parseSuggestedTime(timePart, userStatus)
-		} else if message == "" {
-			// 첫 번째 비어있지 않은 줄을 메시지로 사용
-			if line != "" && !strings.HasPrefix(line, "Send Time:") {
-				message = line
-			}
+		} else if message == "" && line != "" {
+			message = line
 		}
 	}
 
-	// 메시지나 시간이 없으면 기본값 설정
-	if message == "" {
-		message = "You have a scheduled reminder."
-	}
-	if sendTime.IsZero() {
-		sendTime = time.Now().Add(1 * time.Hour)
-	}
-
 	return message, sendTime
 }
 
@@ -245,7 +330,7 @@ func parseSuggestedTime(timeStr string, userStatus models.UserStatus) time.Time
 }
 
 // generateEnhancedAlarmMessage 1차 메시지를 더 구체적이고 개인화된 메시지로 재생성
-func generateEnhancedAlarmMessage(openaiClient *openai.Client, initialMessage string, userStatus models.UserStatus, chatbot *models.Chatbot, user *models.User, keywords []string) string {
+func (g *AlarmMessageGenerator) generateEnhancedAlarmMessage(model llm.LLM, initialMessage string, userStatus models.UserStatus, chatbot *models.Chatbot, user *models.User, keywords []string) string {
 	// 2차 가공을 위한 프롬프트 구성
 	enhancementPrompt := fmt.Sprintf(`
 1차로 생성된 알람 메시지: "%s"
@@ -281,7 +366,7 @@ func generateEnhancedAlarmMessage(openaiClient *openai.Client, initialMessage st
 `, initialMessage, userStatus.Event, userStatus.Context, userStatus.ValidUntil.Format("2006-01-02 15:04"), chatbot.Name, *chatbot.Summary, keywords)
 
 	// 2차 가공 API 호출
-	response, err := openaiClient.ChatCompletion(context.Background(), []openai.ChatMessage{
+	response, err := model.ChatCompletion(context.Background(), []llm.ChatMessage{
 		{
 			Role:    "system",
 			Content: "You are an expert at transforming alarm messages into more specific and personalized messages. Your task is to enhance the initial message by incorporating the user's specific situation while maintaining the chatbot's unique personality and speech patterns. Create messages that are engaging, encouraging, and true to the character's established traits.",
@@ -296,6 +381,7 @@ func generateEnhancedAlarmMessage(openaiClient *openai.Client, initialMessage st
 		log.Printf("⚠️ 2차 메시지 가공 실패: %v", err)
 		return initialMessage // 실패 시 1차 메시지 반환
 	}
+	g.usage.RecordChat(user.UUID.String(), usage.FeatureAlarm, model, response)
 
 	// 응답에서 메시지만 추출 (시간 정보 제거)
 	enhancedMessage := strings.TrimSpace(response.Message.Content)
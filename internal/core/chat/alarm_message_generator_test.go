@@ -0,0 +1,56 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+)
+
+func TestParseKeywords(t *testing.T) {
+	tests := []struct {
+		response string
+		want     []string
+	}{
+		{`{"keywords": ["birthday", " celebration "]}`, []string{"birthday", "celebration"}},
+		{"Keywords: exam, stress", []string{"exam", "stress"}},
+		{`{"keywords": []}`, []string{"", ""}},
+		{"nothing useful", []string{"", ""}},
+	}
+
+	for _, tt := range tests {
+		got := parseKeywords(tt.response)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseKeywords(%q) = %q, want %q", tt.response, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseKeywords(%q) = %q, want %q", tt.response, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseAIResponseWithTime(t *testing.T) {
+	status := models.UserStatus{ValidUntil: time.Date(2025, 1, 25, 14, 0, 0, 0, time.UTC)}
+	want := time.Date(2025, 1, 25, 13, 30, 0, 0, time.UTC)
+
+	message, sendTime := parseAIResponseWithTime(`{"message": "Good luck on your exam!", "send_time": "2025-01-25 13:30:00"}`, status)
+	if message != "Good luck on your exam!" || !sendTime.Equal(want) {
+		t.Errorf("JSON response = %q, %s", message, sendTime)
+	}
+
+	// 형식 지정을 따르지 않은 응답
+	message, sendTime = parseAIResponseWithTime("Good luck on your exam!\n\nSend Time: 2025-01-25 13:30:00", status)
+	if message != "Good luck on your exam!" || !sendTime.Equal(want) {
+		t.Errorf("text response = %q, %s", message, sendTime)
+	}
+
+	// 알 수 없는 시간 형식이면 이벤트 1시간 전
+	_, sendTime = parseAIResponseWithTime(`{"message": "hi", "send_time": "soon"}`, status)
+	if !sendTime.Equal(status.ValidUntil.Add(-time.Hour)) {
+		t.Errorf("fallback send time = %s", sendTime)
+	}
+}
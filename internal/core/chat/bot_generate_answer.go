@@ -9,22 +9,44 @@ import (
 	"sync"
 	"time"
 
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
-	"sermo-be/pkg/openai"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
 	"sermo-be/pkg/prompt"
 )
 
+// answerTimeout 초기 응답 생성 전체 제한 시간 (재시도와 대체 모델 포함, 멈춘 호출이 봇 고루틴을 붙잡지 않도록)
+const answerTimeout = 90 * time.Second
+
+// bot_error 이벤트 코드
+const (
+	botErrorLLMUnavailable = "llm_unavailable" // 언어 모델 호출이 재시도와 대체 모델까지 모두 실패
+	botErrorInternal       = "internal"        // 데이터 조회나 저장 실패
+	botErrorQuotaExceeded  = "quota_exceeded"  // 사용자의 AI 사용 한도 초과
+)
+
 // AnswerGenerator AI 응답 생성을 담당하는 구조체
 type AnswerGenerator struct {
 	messageService *MessageService
+	memoryService  *MemoryService
+	chatbots       repository.ChatbotRepository
+	statuses       repository.UserStatusRepository
+	recall         *recall.Service // 지난 대화 검색 (nil이면 검색하지 않음)
+	usage          UsageTracker
 }
 
 // NewAnswerGenerator 새로운 AnswerGenerator 생성
-func NewAnswerGenerator() *AnswerGenerator {
+func NewAnswerGenerator(messageService *MessageService, memoryService *MemoryService, repos *repository.Repositories, recaller *recall.Service, tracker UsageTracker) *AnswerGenerator {
 	return &AnswerGenerator{
-		messageService: GetMessageService(),
+		messageService: messageService,
+		memoryService:  memoryService,
+		chatbots:       repos.Chatbots,
+		statuses:       repos.Statuses,
+		recall:         recaller,
+		usage:          tracker,
 	}
 }
 
@@ -37,56 +59,80 @@ type ChatbotInfo struct {
 	Summary  *string         `json:"summary"` // 추가: 요약 정보
 }
 
-// WeightedMessage 가중치가 적용된 메시지
-type WeightedMessage struct {
-	Content  string  `json:"content"`
-	Weight   float64 `json:"weight"`
-	Role     string  `json:"role"`
-	IsRecent bool    `json:"is_recent"`
-}
-
 // DataCollectionResult 고루틴으로 수집된 데이터 결과
 type DataCollectionResult struct {
 	ChatbotInfo *ChatbotInfo
-	History     []models.ChatMessage
-	UserStatus  *models.UserStatus
+	History     []models.ChatMessage       // 요약에 반영되지 않은 최근 메시지
+	Memory      *models.ConversationMemory // 이전 대화의 누적 요약 (없으면 nil)
+	UserStatus  *models.UserStatus         // 현재 메시지와 관련된 유효한 상태 정보 (없으면 nil)
+	Recalled    []recall.Match             // 현재 메시지와 관련된 지난 사용자 메시지
 	Err         error
 }
 
 // GenerateAnswer AI 응답 생성 및 저장
-func (ag *AnswerGenerator) GenerateAnswer(session *middleware.SSESession, combinedMessage string, openaiClient *openai.Client) *models.ChatMessage {
+// 응답 생성과 검증은 chat, 캐릭터/대화 요약은 summary, 지난 대화 검색은 embedding 기능에 지정된 언어 모델을 사용한다.
+// 응답을 만들지 못하면 타이핑 표시를 끄고 bot_error 이벤트로 알린다.
+func (ag *AnswerGenerator) GenerateAnswer(session *middleware.SSESession, combinedMessage string, router *llm.Router) *models.ChatMessage {
+	chatModel := router.For(llm.FeatureChat)
+	if chatModel == nil {
+		log.Printf("응답 생성용 언어 모델이 설정되지 않음 - 세션: %s", session.SessionID)
+		ag.sendErrorEvent(session, botErrorLLMUnavailable, "LLM service unavailable")
+		return nil
+	}
+	embedder := router.For(llm.FeatureEmbedding)
+
 	// 타이핑 이벤트 시작 전송
 	ag.sendTypingEvent(session, true)
 
 	// 1. 고루틴으로 필요한 데이터 병렬 조회
-	dataResult := ag.collectDataParallel(session.UserUUID, session.ChatbotUUID, combinedMessage, openaiClient)
+	dataResult := ag.collectDataParallel(session.Context(), session.UserUUID, session.ChatbotUUID, combinedMessage, router)
 	if dataResult.Err != nil {
 		ag.sendTypingEvent(session, false) // 타이핑 이벤트 종료
+		ag.sendErrorEvent(session, botErrorInternal, "Failed to prepare reply")
 		return nil
 	}
 
-	// 2. 가중치 기반 대화 히스토리 구성
-	weightedHistory := ag.buildWeightedHistory(dataResult.History)
+	// 2. 토큰 예산 안에서 최근 대화 선택 (이번에 답할 사용자 메시지는 따로 전달)
+	answered := trimPendingUserMessages(dataResult.History)
+	pending := dataResult.History[len(answered):]
+	history := SelectHistory(answered, DefaultMemoryPolicy.HistoryTokenBudget)
+
+	memory := ""
+	if dataResult.Memory != nil {
+		memory = dataResult.Memory.Summary
+	}
+	recalled := recalledSnippets(dataResult.Recalled, history)
 
-	// 3. 초기 프롬프팅으로 응답 생성
-	initialResponse, err := ag.generateInitialResponse(dataResult.ChatbotInfo, weightedHistory, dataResult.UserStatus, combinedMessage, openaiClient)
+	// 3. 초기 프롬프팅으로 응답 생성 (세션이 종료되거나 제한 시간이 지나면 취소)
+	ctx, cancel := context.WithTimeout(session.Context(), answerTimeout)
+	initialResponse, err := ag.generateInitialResponse(ctx, session.UserUUID, dataResult.ChatbotInfo, memory, recalled, history, dataResult.UserStatus, combinedMessage, chatModel)
+	cancel()
 	if err != nil {
+		log.Printf("초기 응답 생성 실패 - 세션: %s, 에러: %v", session.SessionID, err)
 		ag.sendTypingEvent(session, false) // 타이핑 이벤트 종료
+		ag.sendErrorEvent(session, botErrorLLMUnavailable, "Failed to generate reply")
 		return nil
 	}
 
 	// 4. 응답 검증 및 재조정 (2단계)
-	finalResponse := ag.validateAndAdjustResponse(dataResult.ChatbotInfo, dataResult.UserStatus, initialResponse, combinedMessage, openaiClient)
+	finalResponse := ag.validateAndAdjustResponse(session, dataResult.ChatbotInfo, dataResult.UserStatus, initialResponse, combinedMessage, chatModel)
+
+	// 검증 중 세션이 종료됐으면 저장하지 않음 (검증 실패 시 원본 응답으로 대체되므로 따로 확인)
+	if !session.IsActive() {
+		return nil
+	}
 
 	// 최종 응답 검증 - 빈 응답인 경우 처리
 	if strings.TrimSpace(finalResponse) == "" {
 		ag.sendTypingEvent(session, false) // 타이핑 이벤트 종료
+		ag.sendErrorEvent(session, botErrorLLMUnavailable, "Empty reply from LLM")
 		return nil
 	}
 
 	// 최종 응답 길이 검증
 	if len(strings.TrimSpace(finalResponse)) < 1 {
 		ag.sendTypingEvent(session, false) // 타이핑 이벤트 종료
+		ag.sendErrorEvent(session, botErrorLLMUnavailable, "Empty reply from LLM")
 		return nil
 	}
 
@@ -100,12 +146,24 @@ func (ag *AnswerGenerator) GenerateAnswer(session *middleware.SSESession, combin
 
 	if err != nil {
 		ag.sendTypingEvent(session, false) // 타이핑 이벤트 종료
+		ag.sendErrorEvent(session, botErrorInternal, "Failed to save reply")
 		return nil
 	}
 
 	// 타이핑 이벤트 종료 전송
 	ag.sendTypingEvent(session, false)
 
+	// 이번에 답한 사용자 메시지를 이후 대화에서 찾을 수 있도록 임베딩하고,
+	// 오래된 메시지가 충분히 쌓였으면 요약해 기억으로 저장 (백그라운드)
+	session.Go(func(ctx context.Context) {
+		if err := indexUserMessages(ctx, ag.recall, recall.Tracked(embedder, ag.usage, session.UserUUID), pending); err != nil {
+			log.Printf("사용자 메시지 임베딩 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		}
+		if err := ag.memoryService.Summarize(ctx, router.For(llm.FeatureSummary), session.UserUUID, session.ChatbotUUID, DefaultMemoryPolicy); err != nil {
+			log.Printf("대화 요약 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		}
+	})
+
 	return botChatMessage
 }
 
@@ -130,20 +188,46 @@ func (ag *AnswerGenerator) sendTypingEvent(session *middleware.SSESession, isTyp
 	sseMessage := "data: " + string(eventData) + "\n\n"
 
 	// 세션 채널로 이벤트 전송
-	select {
-	case session.Channel <- sseMessage:
-		if isTyping {
-			log.Printf("봇 타이핑 시작 이벤트 전송 - 세션: %s", session.SessionID)
-		} else {
-			log.Printf("봇 타이핑 종료 이벤트 전송 - 세션: %s", session.SessionID)
-		}
-	default:
-		log.Printf("세션 채널이 가득 참 - 타이핑 이벤트 전송 실패 - 세션: %s", session.SessionID)
+	if err := session.Send(sseMessage); err != nil {
+		log.Printf("타이핑 이벤트 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		return
+	}
+	if isTyping {
+		log.Printf("봇 타이핑 시작 이벤트 전송 - 세션: %s", session.SessionID)
+	} else {
+		log.Printf("봇 타이핑 종료 이벤트 전송 - 세션: %s", session.SessionID)
+	}
+}
+
+// sendErrorEvent 응답 생성 실패를 bot_error 이벤트로 전송 (세션이 이미 종료됐으면 보내지 않음)
+func (ag *AnswerGenerator) sendErrorEvent(session *middleware.SSESession, code, message string) {
+	if !session.IsActive() {
+		return
+	}
+
+	errorEvent := map[string]interface{}{
+		"type":       "bot_error",
+		"code":       code,
+		"error":      message,
+		"session_id": session.SessionID,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	}
+
+	eventData, err := json.Marshal(errorEvent)
+	if err != nil {
+		log.Printf("bot_error 이벤트 직렬화 실패: %v", err)
+		return
 	}
+
+	if err := session.Send("data: " + string(eventData) + "\n\n"); err != nil {
+		log.Printf("bot_error 이벤트 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		return
+	}
+	log.Printf("bot_error 이벤트 전송 - 세션: %s, 코드: %s", session.SessionID, code)
 }
 
 // collectDataParallel 고루틴으로 필요한 데이터를 병렬로 수집
-func (ag *AnswerGenerator) collectDataParallel(userUUID, chatbotUUID, currentMessage string, openaiClient *openai.Client) *DataCollectionResult {
+func (ag *AnswerGenerator) collectDataParallel(ctx context.Context, userUUID, chatbotUUID, currentMessage string, router *llm.Router) *DataCollectionResult {
 	result := &DataCollectionResult{}
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -152,7 +236,7 @@ func (ag *AnswerGenerator) collectDataParallel(userUUID, chatbotUUID, currentMes
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		chatbotInfo, err := ag.getChatbotInfo(chatbotUUID, openaiClient)
+		chatbotInfo, err := ag.getChatbotInfo(userUUID, chatbotUUID, router.For(llm.FeatureSummary))
 		mu.Lock()
 		if err != nil {
 			result.Err = fmt.Errorf("채팅봇 정보 조회 실패: %w", err)
@@ -162,31 +246,39 @@ func (ag *AnswerGenerator) collectDataParallel(userUUID, chatbotUUID, currentMes
 		mu.Unlock()
 	}()
 
-	// 대화 히스토리 조회
+	// 대화 요약과 요약 이후의 최근 대화 히스토리 조회
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		history, err := ag.messageService.GetChatHistory(userUUID, chatbotUUID, 10) // 20개에서 10개로 줄임
+		memory, err := ag.memoryService.GetMemory(userUUID, chatbotUUID)
+		if err != nil {
+			// 요약이 없어도 최근 대화만으로 계속 진행
+			log.Printf("대화 요약 조회 실패: %v", err)
+		}
+		history, err := ag.memoryService.GetRecentHistory(memory, userUUID, chatbotUUID, memoryHistoryFetchLimit)
 		mu.Lock()
 		if err != nil {
 			result.Err = fmt.Errorf("대화 히스토리 조회 실패: %w", err)
 		} else {
 			result.History = history
+			result.Memory = memory
 		}
 		mu.Unlock()
 	}()
 
-	// 사용자 상태 정보 조회
+	// 현재 메시지와 관련된 지난 메시지와 사용자 상태 정보 검색 (임베딩 유사도)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		userStatus, err := ag.getRelevantUserStatus(userUUID, chatbotUUID, currentMessage)
-		mu.Lock()
+		userStatus, recalled, err := ag.recallMemories(ctx, userUUID, chatbotUUID, currentMessage, router.For(llm.FeatureEmbedding))
 		if err != nil {
-			// 상태 정보 없어도 계속 진행 (에러가 아님)
-		} else {
-			result.UserStatus = userStatus
+			// 검색에 실패해도 최근 대화만으로 계속 진행
+			log.Printf("지난 대화 검색 실패: %v", err)
+			return
 		}
+		mu.Lock()
+		result.UserStatus = userStatus
+		result.Recalled = recalled
 		mu.Unlock()
 	}()
 
@@ -211,22 +303,22 @@ func (ag *AnswerGenerator) collectDataParallel(userUUID, chatbotUUID, currentMes
 	return result
 }
 
-// getChatbotInfo 채팅봇 정보 조회
-func (ag *AnswerGenerator) getChatbotInfo(chatbotUUID string, openaiClient *openai.Client) (*ChatbotInfo, error) {
-	var chatbot models.Chatbot
-	if err := database.DB.Where("uuid = ?", chatbotUUID).First(&chatbot).Error; err != nil {
+// getChatbotInfo 채팅봇 정보 조회 (요약을 새로 만들면 사용량은 userUUID에 기록)
+func (ag *AnswerGenerator) getChatbotInfo(userUUID, chatbotUUID string, summarizer llm.LLM) (*ChatbotInfo, error) {
+	chatbot, err := ag.chatbots.FindByUUID(chatbotUUID)
+	if err != nil {
 		return nil, fmt.Errorf("채팅봇 조회 실패: %w", err)
 	}
 
 	// 요약이 없으면 AI로 생성
 	if chatbot.GetSummary() == nil {
-		summary := ag.generateCharacterSummary(&chatbot, openaiClient)
+		summary := ag.generateCharacterSummary(userUUID, chatbot, summarizer)
 
 		// 생성된 요약을 Chatbot 모델에 설정
 		chatbot.SetSummary(summary)
 
 		// 생성된 요약을 DB에 저장
-		if err := database.DB.Model(&chatbot).Update("summary", summary).Error; err != nil {
+		if err := ag.chatbots.UpdateSummary(chatbotUUID, summary); err != nil {
 			log.Printf("요약 저장 실패: %v", err)
 		} else {
 			log.Printf("요약 생성 및 저장 완료 - 길이: %d", len(summary))
@@ -242,105 +334,35 @@ func (ag *AnswerGenerator) getChatbotInfo(chatbotUUID string, openaiClient *open
 	}, nil
 }
 
-// buildWeightedHistory 가중치 기반 대화 히스토리 구성
-func (ag *AnswerGenerator) buildWeightedHistory(history []models.ChatMessage) []WeightedMessage {
-	var weightedMessages []WeightedMessage
-	totalMessages := len(history)
-
-	for i, msg := range history {
-		weight := 0.2 // 기본 가중치를 더 낮게 설정
-		isRecent := false
-
-		// 최근 메시지일수록 높은 가중치 (최근 메시지 우선)
-		if i == totalMessages-1 {
-			weight = 1.0 // 가장 최근 메시지는 최고 가중치
-			isRecent = true
-		} else if i >= totalMessages-2 {
-			weight = 0.9 // 최근 2개 메시지는 높은 가중치
-			isRecent = true
-		} else if i >= totalMessages-5 {
-			weight = 0.6 // 중간 가중치
-		} else {
-			weight = 0.2 // 오래된 메시지는 낮은 가중치 (문맥 파악용)
-		}
-
-		role := "user"
-		if msg.MessageType == models.MessageTypeChatbot {
-			role = "assistant"
-		}
-
-		weightedMessages = append(weightedMessages, WeightedMessage{
-			Content:  msg.Content,
-			Weight:   weight,
-			Role:     role,
-			IsRecent: isRecent,
-		})
-	}
-
-	return weightedMessages
-}
-
-// getRelevantUserStatus 맥락에 맞는 사용자 상태 정보 조회
-func (ag *AnswerGenerator) getRelevantUserStatus(userUUID, chatbotUUID, currentMessage string) (*models.UserStatus, error) {
-	var userStatus models.UserStatus
-	if err := database.DB.Where("user_uuid = ? AND chatbot_uuid = ? AND is_active = ?",
-		userUUID, chatbotUUID, true).First(&userStatus).Error; err != nil {
-		return nil, err
-	}
-
-	// 현재 메시지와 상태 정보의 맥락 일치성 검증
-	if ag.isContextRelevant(currentMessage, userStatus.Event, userStatus.Context) {
-		return &userStatus, nil
-	}
-
-	return nil, fmt.Errorf("맥락이 일치하지 않음")
-}
-
-// isContextRelevant 현재 메시지와 상태 정보의 맥락 일치성 검증
-func (ag *AnswerGenerator) isContextRelevant(currentMessage, event, context string) bool {
-	currentMessage = strings.ToLower(currentMessage)
-	event = strings.ToLower(event)
-	context = strings.ToLower(context)
-
-	// 키워드 기반 맥락 일치성 검증
-	keywords := []string{event, context}
-	for _, keyword := range keywords {
-		if keyword != "" && strings.Contains(currentMessage, keyword) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // generateCharacterSummary AI를 이용해 캐릭터 상세 정보를 요약
-func (ag *AnswerGenerator) generateCharacterSummary(chatbotInfo *models.Chatbot, openaiClient *openai.Client) string {
-	// 상세 정보가 짧으면 요약 불필요
-	if len(chatbotInfo.Details) < 200 {
+func (ag *AnswerGenerator) generateCharacterSummary(userUUID string, chatbotInfo *models.Chatbot, summarizer llm.LLM) string {
+	// 상세 정보가 짧거나 요약 모델이 없으면 요약하지 않음
+	if len(chatbotInfo.Details) < 200 || summarizer == nil {
 		return chatbotInfo.Details
 	}
 
 	// 요약 프롬프트 구성 (pkg/prompt 사용)
 	summaryPrompt := prompt.BuildCharacterSummaryPrompt(chatbotInfo.Name, string(chatbotInfo.Gender), chatbotInfo.Details)
 
-	var messages []openai.ChatMessage
-	messages = append(messages, openai.ChatMessage{
+	var messages []llm.ChatMessage
+	messages = append(messages, llm.ChatMessage{
 		Role:    "system",
 		Content: "You are an expert at analyzing and summarizing AI chatbot personalities. Focus on capturing the character's unique speech patterns, vocabulary choices, and communication style. Create summaries that highlight what makes each character distinct in how they talk and express themselves.",
 	})
-	messages = append(messages, openai.ChatMessage{
+	messages = append(messages, llm.ChatMessage{
 		Role:    "user",
 		Content: summaryPrompt,
 	})
 
-	// OpenAI API 호출하여 요약 생성
+	// 언어 모델을 호출하여 요약 생성
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	response, err := openaiClient.ChatCompletion(ctx, messages)
+	response, err := summarizer.ChatCompletion(ctx, messages)
 	if err != nil {
 		return chatbotInfo.Details
 	}
+	ag.usage.RecordChat(userUUID, usage.FeatureSummary, summarizer, response)
 
 	summary := strings.TrimSpace(response.Message.Content)
 
@@ -353,7 +375,7 @@ func (ag *AnswerGenerator) generateCharacterSummary(chatbotInfo *models.Chatbot,
 }
 
 // convertToPromptChatbotInfo ChatbotInfo를 prompt.ChatbotInfo로 변환
-func convertToPromptChatbotInfo(chatbotInfo *ChatbotInfo, openaiClient *openai.Client) *prompt.ChatbotInfo {
+func convertToPromptChatbotInfo(chatbotInfo *ChatbotInfo) *prompt.ChatbotInfo {
 	// 요약이 있으면 요약 사용, 없으면 상세 정보 사용
 	details := chatbotInfo.Details
 	if chatbotInfo.Summary != nil && *chatbotInfo.Summary != "" {
@@ -369,9 +391,10 @@ func convertToPromptChatbotInfo(chatbotInfo *ChatbotInfo, openaiClient *openai.C
 }
 
 // validateAndAdjustResponse 2단계: 응답 검증 및 재조정
-func (ag *AnswerGenerator) validateAndAdjustResponse(chatbotInfo *ChatbotInfo, userStatus *models.UserStatus, initialResponse, currentMessage string, openaiClient *openai.Client) string {
+// 세션이 스트리밍을 요청한 경우 최종 응답을 생성하는 동안 bot_delta 이벤트로 조각을 전송한다.
+func (ag *AnswerGenerator) validateAndAdjustResponse(session *middleware.SSESession, chatbotInfo *ChatbotInfo, userStatus *models.UserStatus, initialResponse, currentMessage string, model llm.LLM) string {
 	// ChatbotInfo를 prompt.ChatbotInfo로 변환
-	promptChatbotInfo := convertToPromptChatbotInfo(chatbotInfo, openaiClient)
+	promptChatbotInfo := convertToPromptChatbotInfo(chatbotInfo)
 
 	// 검증 프롬프트 구성
 	validationPrompt := prompt.BuildValidationPrompt(promptChatbotInfo, userStatus, currentMessage, initialResponse)
@@ -383,7 +406,7 @@ func (ag *AnswerGenerator) validateAndAdjustResponse(chatbotInfo *ChatbotInfo, u
 	validationPrompt += "\n\nCHARACTER AUTHENTICITY CHECK: Ensure the response maintains the character's unique speech patterns, vocabulary, and personality. If the response feels generic or doesn't match the character's established traits, adjust it to be more authentic to this specific character. Preserve any catchphrases or unique expressions that make the character distinct."
 
 	// 검증 요청을 위한 메시지 구성
-	validationMessages := []openai.ChatMessage{
+	validationMessages := []llm.ChatMessage{
 		{
 			Role:    "system",
 			Content: "당신은 AI 응답을 검증하고 재조정하는 전문가입니다. 친구다운 자연스러운 대화가 되도록 검증하고 필요시 수정해주세요.",
@@ -394,14 +417,23 @@ func (ag *AnswerGenerator) validateAndAdjustResponse(chatbotInfo *ChatbotInfo, u
 		},
 	}
 
-	// 검증 API 호출
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 검증 API 호출 (세션이 종료되면 취소)
+	ctx, cancel := context.WithTimeout(session.Context(), 30*time.Second)
 	defer cancel()
 
-	response, err := openaiClient.ChatCompletion(ctx, validationMessages)
+	var response *llm.ChatResponse
+	var err error
+	if session.StreamReplies {
+		deltas := newDeltaSender(session)
+		response, err = model.ChatCompletionStream(ctx, validationMessages, deltas.Write)
+		deltas.Flush()
+	} else {
+		response, err = model.ChatCompletion(ctx, validationMessages)
+	}
 	if err != nil {
-		return initialResponse // 실패시 원본 응답 사용
+		return initialResponse // 실패시 원본 응답 사용 (스트리밍 중이었다면 bot_done의 내용이 최종 응답)
 	}
+	ag.usage.RecordChat(session.UserUUID, usage.FeatureValidation, model, response)
 
 	finalResponse := strings.TrimSpace(response.Message.Content)
 
@@ -414,11 +446,12 @@ func (ag *AnswerGenerator) validateAndAdjustResponse(chatbotInfo *ChatbotInfo, u
 }
 
 // generateInitialResponse 초기 프롬프팅으로 응답 생성
-func (ag *AnswerGenerator) generateInitialResponse(chatbotInfo *ChatbotInfo, weightedHistory []WeightedMessage,
-	userStatus *models.UserStatus, currentMessage string, openaiClient *openai.Client) (string, error) {
+// memory는 이전 대화의 누적 요약, recalled는 현재 메시지와 관련된 지난 사용자 메시지, history는 요약 이후의 최근 대화 (오래된 순)
+func (ag *AnswerGenerator) generateInitialResponse(ctx context.Context, userUUID string, chatbotInfo *ChatbotInfo, memory string, recalled []string, history []models.ChatMessage,
+	userStatus *models.UserStatus, currentMessage string, model llm.LLM) (string, error) {
 
 	// 시스템 프롬프트 구성 (pkg/prompt 사용)
-	systemPrompt := prompt.BuildSystemPrompt(convertToPromptChatbotInfo(chatbotInfo, openaiClient), userStatus)
+	systemPrompt := prompt.BuildSystemPrompt(convertToPromptChatbotInfo(chatbotInfo), userStatus, memory, recalled)
 
 	// 영어 응답 강제 프롬프트 추가
 	systemPrompt += "\n\nIMPORTANT INSTRUCTION: You MUST respond in English only. Do not use Korean, Japanese, or any other language. Always use natural, conversational English that matches your character's personality."
@@ -427,27 +460,29 @@ func (ag *AnswerGenerator) generateInitialResponse(chatbotInfo *ChatbotInfo, wei
 	systemPrompt += "\n\nCHARACTER CONSISTENCY: Stay true to your character's unique speech patterns, vocabulary, and personality. If you have specific catchphrases, speaking habits, or unique expressions, use them naturally. Avoid generic responses - make every response feel authentic to your specific character. Maintain your character's background, age, and personality traits throughout the conversation."
 
 	// 대화 컨텍스트 구성
-	var messages []openai.ChatMessage
-	messages = append(messages, openai.ChatMessage{
+	var messages []llm.ChatMessage
+	messages = append(messages, llm.ChatMessage{
 		Role:    "system",
 		Content: systemPrompt,
 	})
 
-	// 가중치가 높은 메시지부터 추가 (최근 메시지 우선)
-	// 최근 메시지부터 역순으로 추가하여 최신 메시지가 마지막에 오도록 함
-	for i := len(weightedHistory) - 1; i >= 0; i-- {
-		msg := weightedHistory[i]
-		// 가중치 기준을 더 엄격하게 설정하여 메시지 수 줄임
-		if msg.Weight >= 0.7 && strings.TrimSpace(msg.Content) != "" && len(strings.TrimSpace(msg.Content)) > 2 {
-			messages = append(messages, openai.ChatMessage{
-				Role:    msg.Role,
-				Content: msg.Content,
-			})
+	// 최근 대화를 오래된 순으로 추가 (토큰 예산으로 이미 선택됨)
+	for _, msg := range history {
+		if strings.TrimSpace(msg.Content) == "" {
+			continue
 		}
+		role := "user"
+		if msg.MessageType == models.MessageTypeChatbot {
+			role = "assistant"
+		}
+		messages = append(messages, llm.ChatMessage{
+			Role:    role,
+			Content: msg.Content,
+		})
 	}
 
 	// 현재 사용자 메시지 추가 (가장 최근 메시지)
-	messages = append(messages, openai.ChatMessage{
+	messages = append(messages, llm.ChatMessage{
 		Role:    "user",
 		Content: currentMessage,
 	})
@@ -458,15 +493,16 @@ func (ag *AnswerGenerator) generateInitialResponse(chatbotInfo *ChatbotInfo, wei
 			return "", fmt.Errorf("메시지 %d의 content가 빈 값입니다", i)
 		}
 		if len(strings.TrimSpace(msg.Content)) < 1 {
-			return "", fmt.Errorf("메시지 %d의 content가 너무 짧습니다: %s", msg.Content)
+			return "", fmt.Errorf("메시지 %d의 content가 너무 짧습니다: %s", i, msg.Content)
 		}
 	}
 
 	// AI 응답 생성
-	response, err := openaiClient.ChatCompletion(context.Background(), messages)
+	response, err := model.ChatCompletion(ctx, messages)
 	if err != nil {
 		return "", fmt.Errorf("AI 응답 생성 실패: %w", err)
 	}
+	ag.usage.RecordChat(userUUID, usage.FeatureAnswer, model, response)
 
 	return response.Message.Content, nil
 }
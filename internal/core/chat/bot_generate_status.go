@@ -5,46 +5,58 @@ import (
 	"log"
 	"time"
 
+	"sermo-be/internal/core/recall"
 	"sermo-be/internal/core/status"
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/middleware"
-	"sermo-be/pkg/openai"
+	"sermo-be/pkg/llm"
 	"sermo-be/pkg/prompt"
 )
 
 // StatusGenerator 상태 정보 수집 및 저장을 담당하는 구조체
 type StatusGenerator struct {
 	statusService *status.StatusService
+	recall        *recall.Service // 상태 정보 임베딩 저장 (nil이면 저장하지 않음)
+	usage         UsageTracker
 }
 
 // NewStatusGenerator 새로운 StatusGenerator 생성
-func NewStatusGenerator() *StatusGenerator {
+func NewStatusGenerator(statusService *status.StatusService, recaller *recall.Service, tracker UsageTracker) *StatusGenerator {
 	return &StatusGenerator{
-		statusService: status.GetStatusService(),
+		statusService: statusService,
+		recall:        recaller,
+		usage:         tracker,
 	}
 }
 
 // ExtractAndSaveStatus 사용자 메시지에서 상태 정보 추출 및 저장
-func (sg *StatusGenerator) ExtractAndSaveStatus(session *middleware.SSESession, userMessage string, openaiClient *openai.Client) {
+// 추출은 status 기능, 저장한 상태 정보의 임베딩은 embedding 기능에 지정된 언어 모델을 사용한다.
+func (sg *StatusGenerator) ExtractAndSaveStatus(session *middleware.SSESession, userMessage string, router *llm.Router) {
+	extractor := router.For(llm.FeatureStatus)
+	if extractor == nil {
+		return
+	}
+
 	// 상태 정보 추출
-	statusResult := sg.extractUserStatus(userMessage, openaiClient)
+	statusResult := sg.extractUserStatus(session.UserUUID, userMessage, extractor)
 	if statusResult == nil {
 		return
 	}
 
 	// 저장이 필요한 경우에만 저장
 	if statusResult.NeedsSave {
-		go sg.saveUserStatus(session, statusResult.Event, statusResult.ValidUntil, statusResult.Context)
+		go sg.saveUserStatus(session, statusResult.Event, statusResult.ValidUntil, statusResult.Context, router.For(llm.FeatureEmbedding))
 	}
 }
 
 // extractUserStatus 사용자 메시지에서 상태 정보 추출
-func (sg *StatusGenerator) extractUserStatus(userMessage string, openaiClient *openai.Client) *status.StatusExtractionResult {
+func (sg *StatusGenerator) extractUserStatus(userUUID, userMessage string, model llm.LLM) *status.StatusExtractionResult {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// 상태 정보 추출을 위한 프롬프트 구성
 	statusPrompt := prompt.GetStatusExtractionPrompt()
-	statusMessages := []openai.ChatMessage{
+	statusMessages := []llm.ChatMessage{
 		{
 			Role:    "system",
 			Content: statusPrompt,
@@ -56,10 +68,14 @@ func (sg *StatusGenerator) extractUserStatus(userMessage string, openaiClient *o
 	}
 
 	// 상태 정보 추출 요청
-	statusResponse, err := openaiClient.ChatCompletion(ctx, statusMessages)
+	statusResponse, err := model.ChatCompletionWithOptions(ctx, llm.ChatRequest{
+		Messages:       statusMessages,
+		ResponseFormat: status.StatusExtractionFormat,
+	})
 	if err != nil {
 		return nil
 	}
+	sg.usage.RecordChat(userUUID, usage.FeatureStatus, model, statusResponse)
 
 	// 상태 정보 파싱
 	statusResult, err := sg.statusService.ParseStatusExtractionResult(statusResponse.Message.Content)
@@ -70,16 +86,21 @@ func (sg *StatusGenerator) extractUserStatus(userMessage string, openaiClient *o
 	return statusResult
 }
 
-// saveUserStatus 사용자 상태 정보 저장
-func (sg *StatusGenerator) saveUserStatus(session *middleware.SSESession, event string, validUntil time.Time, context string) {
-	err := sg.statusService.SaveUserStatus(
+// saveUserStatus 사용자 상태 정보 저장 후 이후 대화에서 찾을 수 있도록 임베딩
+func (sg *StatusGenerator) saveUserStatus(session *middleware.SSESession, event string, validUntil time.Time, statusContext string, embedder llm.LLM) {
+	userStatus, err := sg.statusService.SaveUserStatus(
 		session.UserUUID,
 		session.ChatbotUUID,
 		event,
 		validUntil,
-		context,
+		statusContext,
 	)
 	if err != nil {
 		log.Printf("상태 정보 저장 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		return
+	}
+
+	if err := indexUserStatus(context.Background(), sg.recall, recall.Tracked(embedder, sg.usage, session.UserUUID), userStatus); err != nil {
+		log.Printf("상태 정보 임베딩 실패 - 세션: %s, 에러: %v", session.SessionID, err)
 	}
 }
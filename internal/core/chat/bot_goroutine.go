@@ -1,14 +1,18 @@
 package chat
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/openai"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
 )
 
 // BotMessage 봇 메시지 구조
@@ -19,6 +23,17 @@ type BotMessage struct {
 	SessionID string `json:"session_id"`
 }
 
+// ReplyStateMessage 봇 응답 대기 상태 이벤트 (대기 상태가 바뀔 때마다 전송)
+// 시각은 밀리초 단위 지연도 표현할 수 있도록 RFC3339Nano 형식이며, 대기 중이 아니면 생략된다.
+type ReplyStateMessage struct {
+	Type           string `json:"type"` // reply_state
+	SessionID      string `json:"session_id"`
+	Pending        bool   `json:"pending"`
+	Typing         bool   `json:"typing"`
+	FirstMessageAt string `json:"first_message_at,omitempty"`
+	DueAt          string `json:"due_at,omitempty"`
+}
+
 // UserMessage 사용자 메시지 구조
 type UserMessage struct {
 	Type      string `json:"type"`
@@ -39,63 +54,116 @@ type BotGoroutine struct {
 	messageService  *MessageService
 	answerGenerator *AnswerGenerator
 	statusGenerator *StatusGenerator
-	openaiClient    *openai.Client
+	chatbots        repository.ChatbotRepository
+	usage           UsageTracker
 }
 
 // NewBotGoroutine 새로운 봇 고루틴 관리자 생성
-func NewBotGoroutine() *BotGoroutine {
+func NewBotGoroutine(messageService *MessageService, answerGenerator *AnswerGenerator, statusGenerator *StatusGenerator, chatbots repository.ChatbotRepository, tracker UsageTracker) *BotGoroutine {
 	return &BotGoroutine{
-		messageService:  GetMessageService(),
-		answerGenerator: NewAnswerGenerator(),
-		statusGenerator: NewStatusGenerator(),
-		openaiClient:    nil, // StartBotGoroutine에서 설정됨
+		messageService:  messageService,
+		answerGenerator: answerGenerator,
+		statusGenerator: statusGenerator,
+		chatbots:        chatbots,
+		usage:           tracker,
 	}
 }
 
 // StartBotGoroutine 봇 고루틴 시작
-func (bg *BotGoroutine) StartBotGoroutine(session *middleware.SSESession, openaiClient *openai.Client) chan string {
-	// OpenAI 클라이언트 설정
-	bg.openaiClient = openaiClient
-
-	// 봇 고루틴 전용 채널 생성
-	botChannel := make(chan string, 100)
-
-	go func() {
+// 봇 고루틴은 세션에 묶여 실행되며(session.Go) 세션 context가 취소되면 진행 중인 응답 생성과 함께 종료된다.
+func (bg *BotGoroutine) StartBotGoroutine(session *middleware.SSESession, router *llm.Router) chan string {
+	started := session.Go(func(ctx context.Context) {
 		log.Printf("봇 고루틴 시작 - 세션: %s", session.SessionID)
-		bg.runBotGoroutine(session, botChannel, openaiClient)
-	}()
+		bg.runBotGoroutine(ctx, session, router)
+	})
+	if !started {
+		log.Printf("이미 종료된 세션 - 봇 고루틴을 시작하지 않음 - 세션: %s", session.SessionID)
+	}
 
-	return botChannel
+	return session.BotChannel
 }
 
 // runBotGoroutine 봇 고루틴 메인 로직
-func (bg *BotGoroutine) runBotGoroutine(session *middleware.SSESession, botChannel chan string, openaiClient *openai.Client) {
-	// 메시지 버퍼와 타이머
+// 메시지 버퍼와 응답 대기 상태는 이 고루틴만 다루고, 응답 생성은 세션 고루틴으로 분리해 입력 처리를 막지 않는다.
+func (bg *BotGoroutine) runBotGoroutine(ctx context.Context, session *middleware.SSESession, router *llm.Router) {
+	// 메시지 버퍼와 응답 대기 타이머 (타이머 만료는 flushSignal로 이 고루틴에 전달해 버퍼를 한 곳에서만 다룸)
 	var messageBuffer []string
-	var responseTimer *time.Timer
+	flushSignal := make(chan struct{}, 1)
+	debouncer := NewReplyDebouncer(bg.replyPolicy(session.ChatbotUUID), nil, func() {
+		select {
+		case flushSignal <- struct{}{}:
+		default:
+		}
+	})
 
-	log.Printf("봇 고루틴 시작 - 세션: %s", session.SessionID)
+	log.Printf("봇 고루틴 시작 - 세션: %s, 응답 대기 정책: %+v", session.SessionID, debouncer.Policy())
 
 	// 봇 채널에서 메시지 수신
-	for session.IsActive {
+	for {
 		select {
-		case message := <-botChannel:
+		case message := <-session.BotChannel:
 			log.Printf("봇 채널에서 메시지 수신 - 세션: %s, 메시지: %s", session.SessionID, message)
 			// 사용자 메시지 처리
-			bg.handleIncomingMessage(session, message, &messageBuffer, &responseTimer, openaiClient)
-
-		case <-session.Done:
+			bg.handleIncomingMessage(session, message, &messageBuffer, debouncer)
+
+		case <-flushSignal:
+			// 응답 예정 시각 도달 또는 즉시 응답 요청: 버퍼에 쌓인 모든 메시지로 봇 응답 생성
+			if len(messageBuffer) > 0 {
+				messages := messageBuffer
+				messageBuffer = nil
+				log.Printf("응답 대기 종료 - AI 응답 생성 시작 - 세션: %s, 메시지 수: %d", session.SessionID, len(messages))
+				session.Go(func(ctx context.Context) {
+					bg.generateAIResponse(ctx, session, messages, router)
+				})
+			}
+
+		case <-ctx.Done():
 			// 세션 종료 신호
-			bg.handleSessionDone(session, responseTimer)
+			bg.handleSessionDone(session, debouncer)
+			log.Printf("봇 고루틴 종료 - 세션: %s", session.SessionID)
 			return
 		}
+
+		bg.updateReplyState(session, debouncer.State())
+	}
+}
+
+// updateReplyState 대기 상태가 바뀌었으면 세션에 저장하고 reply_state 이벤트로 클라이언트에 알림
+func (bg *BotGoroutine) updateReplyState(session *middleware.SSESession, state middleware.ReplyState) {
+	if state.Equal(session.ReplyState()) {
+		return
 	}
+	session.SetReplyState(state)
 
-	log.Printf("봇 고루틴 종료 - 세션: %s", session.SessionID)
+	message := ReplyStateMessage{
+		Type:      "reply_state",
+		SessionID: session.SessionID,
+		Pending:   state.Pending,
+		Typing:    state.Typing,
+	}
+	if state.Pending {
+		message.FirstMessageAt = state.FirstMessageAt.Format(time.RFC3339Nano)
+		message.DueAt = state.DueAt.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(message)
+	if err := session.Send(fmt.Sprintf("data: %s\n\n", string(data))); err != nil {
+		log.Printf("응답 대기 상태 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+	}
+}
+
+// replyPolicy 채팅봇별 응답 대기 정책 조회 (조회 실패 시 기본 정책)
+func (bg *BotGoroutine) replyPolicy(chatbotUUID string) ReplyPolicy {
+	chatbot, err := bg.chatbots.FindByUUID(chatbotUUID)
+	if err != nil {
+		log.Printf("채팅봇 응답 대기 설정 조회 실패 - 기본값 사용 - 채팅봇: %s, 에러: %v", chatbotUUID, err)
+		return DefaultReplyPolicy
+	}
+	return DefaultReplyPolicy.ForChatbot(chatbot)
 }
 
 // handleIncomingMessage 들어오는 메시지 처리
-func (bg *BotGoroutine) handleIncomingMessage(session *middleware.SSESession, message string, messageBuffer *[]string, responseTimer **time.Timer, openaiClient *openai.Client) {
+func (bg *BotGoroutine) handleIncomingMessage(session *middleware.SSESession, message string, messageBuffer *[]string, debouncer *ReplyDebouncer) {
 	log.Printf("handleIncomingMessage 시작 - 세션: %s", session.SessionID)
 
 	// 메시지 파싱
@@ -111,10 +179,14 @@ func (bg *BotGoroutine) handleIncomingMessage(session *middleware.SSESession, me
 	switch sseMessage.Type {
 	case "user":
 		log.Printf("사용자 메시지 처리 시작 - 세션: %s", session.SessionID)
-		bg.processUserMessage(session, sseMessage, messageBuffer, responseTimer, openaiClient)
+		bg.processUserMessage(session, sseMessage, messageBuffer, debouncer)
 	case "onkeyboard":
 		log.Printf("onkeyboard 이벤트 처리 시작 - 세션: %s", session.SessionID)
-		bg.processOnKeyboardEvent(session, responseTimer)
+		bg.processOnKeyboardEvent(session, debouncer)
+	case "flush":
+		// 사용자가 입력을 마쳤다고 알린 경우 대기 없이 바로 응답
+		log.Printf("즉시 응답 요청 - 세션: %s", session.SessionID)
+		debouncer.Flush()
 	case "bot_typing":
 		// 타이핑 이벤트는 봇 채널로 전달하지 않음 (클라이언트에만 전송)
 		log.Printf("봇 타이핑 이벤트 수신 - 봇 채널로 전달하지 않음 - 세션: %s", session.SessionID)
@@ -141,50 +213,30 @@ func (bg *BotGoroutine) parseUserMessage(message string) (*UserMessage, error) {
 }
 
 // processUserMessage 사용자 메시지 처리
-func (bg *BotGoroutine) processUserMessage(session *middleware.SSESession, sseMessage *UserMessage, messageBuffer *[]string, responseTimer **time.Timer, openaiClient *openai.Client) {
+func (bg *BotGoroutine) processUserMessage(session *middleware.SSESession, sseMessage *UserMessage, messageBuffer *[]string, debouncer *ReplyDebouncer) {
 	log.Printf("processUserMessage 시작 - 세션: %s, 메시지: %s", session.SessionID, sseMessage.Content)
 
-	// 기존 타이머가 있다면 취소
-	if *responseTimer != nil {
-		log.Printf("기존 타이머 취소 - 세션: %s", session.SessionID)
-		(*responseTimer).Stop()
-	}
-
 	// 메시지를 버퍼에 추가
 	*messageBuffer = append(*messageBuffer, sseMessage.Content)
 	log.Printf("메시지 버퍼에 추가됨 - 버퍼 크기: %d, 세션: %s", len(*messageBuffer), session.SessionID)
 
-	// 4초 후 버퍼에 쌓인 모든 메시지로 봇 응답 생성 (버퍼링 구현)
-	*responseTimer = time.AfterFunc(4*time.Second, func() {
-		log.Printf("타이머 만료 - AI 응답 생성 시작 - 세션: %s", session.SessionID)
-		bg.generateAIResponse(session, *messageBuffer, openaiClient)
-		// 응답 생성 후 버퍼 초기화
-		*messageBuffer = (*messageBuffer)[:0]
-		log.Printf("메시지 버퍼 초기화 완료 - 세션: %s", session.SessionID)
-	})
+	// 대기 시간 후 버퍼에 쌓인 모든 메시지로 봇 응답 생성 (버퍼링 구현)
+	debouncer.Message()
 
-	log.Printf("processUserMessage 완료 - 타이머 설정됨 (4초 후 실행) - 세션: %s", session.SessionID)
+	log.Printf("processUserMessage 완료 - 응답 예정: %s - 세션: %s", debouncer.State().DueAt.Format(time.RFC3339), session.SessionID)
 }
 
 // processOnKeyboardEvent 키보드 입력 이벤트 처리
-func (bg *BotGoroutine) processOnKeyboardEvent(session *middleware.SSESession, responseTimer **time.Timer) {
-	// 기존 타이머가 있다면 취소
-	// if *responseTimer != nil {
-	// 	(*responseTimer).Stop()
-	// }
-
-	// // 키보드 입력 중일 때는 최소 5초 대기
-	// *responseTimer = time.AfterFunc(5*time.Second, func() {
-	// 	// 5초 후에 타이머를 nil로 설정하여 추가 대기 없이 즉시 응답 가능하게 함
-	// 	*responseTimer = nil
-	// })
+// 대기 중인 메시지가 있으면 사용자가 이어서 입력할 수 있도록 응답을 미룬다 (최대 대기 시간 내).
+func (bg *BotGoroutine) processOnKeyboardEvent(session *middleware.SSESession, debouncer *ReplyDebouncer) {
+	debouncer.Typing()
 }
 
-// generateAIResponse AI 응답 생성
-func (bg *BotGoroutine) generateAIResponse(session *middleware.SSESession, messageBuffer []string, openaiClient *openai.Client) {
+// generateAIResponse AI 응답 생성 (ctx가 취소되면 응답을 전송하지 않음)
+func (bg *BotGoroutine) generateAIResponse(ctx context.Context, session *middleware.SSESession, messageBuffer []string, router *llm.Router) {
 	log.Printf("generateAIResponse 시작 - 세션: %s, 버퍼 크기: %d", session.SessionID, len(messageBuffer))
 
-	if !session.IsActive {
+	if ctx.Err() != nil {
 		log.Printf("세션이 비활성 상태 - AI 응답 생성 중단 - 세션: %s", session.SessionID)
 		return
 	}
@@ -195,6 +247,18 @@ func (bg *BotGoroutine) generateAIResponse(session *middleware.SSESession, messa
 		return
 	}
 
+	// 세션 중에 사용 한도를 넘었으면 응답하지 않고 알림
+	if err := bg.usage.CheckQuota(session.UserUUID, time.Now(), usage.ResourceTokens); err != nil {
+		log.Printf("사용 한도 초과 또는 확인 실패 - AI 응답 생성 중단 - 세션: %s, 에러: %v", session.SessionID, err)
+		var exceeded *usage.QuotaExceededError
+		if errors.As(err, &exceeded) {
+			bg.answerGenerator.sendErrorEvent(session, botErrorQuotaExceeded, "AI usage quota exceeded")
+		} else {
+			bg.answerGenerator.sendErrorEvent(session, botErrorInternal, "Failed to check usage quota")
+		}
+		return
+	}
+
 	// 버퍼의 모든 메시지를 하나의 컨텍스트로 결합
 	combinedMessage := bg.combineMessages(messageBuffer)
 	log.Printf("메시지 결합 완료 - 결합된 메시지: %s - 세션: %s", combinedMessage, session.SessionID)
@@ -207,7 +271,7 @@ func (bg *BotGoroutine) generateAIResponse(session *middleware.SSESession, messa
 	// 고루틴 1: AI 답변 생성
 	go func() {
 		log.Printf("AI 답변 생성 시작 - 세션: %s", session.SessionID)
-		botChatMessage := bg.answerGenerator.GenerateAnswer(session, combinedMessage, openaiClient)
+		botChatMessage := bg.answerGenerator.GenerateAnswer(session, combinedMessage, router)
 		log.Printf("AI 답변 생성 완료 - 세션: %s, 응답: %v", session.SessionID, botChatMessage)
 		responseChan <- botChatMessage
 	}()
@@ -215,7 +279,7 @@ func (bg *BotGoroutine) generateAIResponse(session *middleware.SSESession, messa
 	log.Printf("상태 정보 추출 고루틴 시작 - 세션: %s", session.SessionID)
 	// 고루틴 2: 상태 정보 추출 및 저장
 	go func() {
-		bg.statusGenerator.ExtractAndSaveStatus(session, combinedMessage, openaiClient)
+		bg.statusGenerator.ExtractAndSaveStatus(session, combinedMessage, router)
 		statusChan <- true
 	}()
 
@@ -263,9 +327,13 @@ func (bg *BotGoroutine) combineMessages(messages []string) string {
 func (bg *BotGoroutine) sendBotMessage(session *middleware.SSESession, botChatMessage *models.ChatMessage) {
 	log.Printf("sendBotMessage 시작 - 세션: %s, 응답 내용: %s", session.SessionID, botChatMessage.Content)
 
-	// 봇 응답을 SSE로 전송
+	// 봇 응답을 SSE로 전송 (스트리밍 세션은 앞서 보낸 bot_delta를 대체하는 bot_done으로 마무리)
+	messageType := "bot"
+	if session.StreamReplies {
+		messageType = "bot_done"
+	}
 	botSSEMessage := BotMessage{
-		Type:      "bot",
+		Type:      messageType,
 		Content:   botChatMessage.Content,
 		Timestamp: botChatMessage.CreatedAt.Format(time.RFC3339),
 		SessionID: session.SessionID,
@@ -275,29 +343,19 @@ func (bg *BotGoroutine) sendBotMessage(session *middleware.SSESession, botChatMe
 	botMessageData := fmt.Sprintf("data: %s\n\n", string(botSSEData))
 	log.Printf("봇 SSE 메시지 생성 완료 - 세션: %s, SSE 메시지: %s", session.SessionID, botMessageData)
 
-	// session.Channel로 전송 (클라이언트에 전달)
-	select {
-	case session.Channel <- botMessageData:
+	// session.Channel로 전송 (클라이언트에 전달, 종료된 세션이면 버림)
+	if err := session.Send(botMessageData); err != nil {
+		log.Printf("봇 응답 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+	} else {
+		session.Touch()
 		log.Printf("봇 응답 전송 성공 - 세션: %s", session.SessionID)
-	default:
-		log.Printf("세션 채널이 가득 참 - 세션: %s", session.SessionID)
 	}
 
 	log.Printf("sendBotMessage 완료 - 세션: %s", session.SessionID)
 }
 
 // handleSessionDone 세션 종료 처리
-func (bg *BotGoroutine) handleSessionDone(session *middleware.SSESession, responseTimer *time.Timer) {
+func (bg *BotGoroutine) handleSessionDone(session *middleware.SSESession, debouncer *ReplyDebouncer) {
 	log.Printf("봇 고루틴 종료 신호 수신 - 세션: %s", session.SessionID)
-	if responseTimer != nil {
-		responseTimer.Stop()
-	}
-}
-
-// 전역 BotGoroutine 인스턴스
-var globalBotGoroutine = NewBotGoroutine()
-
-// GetBotGoroutine 전역 BotGoroutine 반환
-func GetBotGoroutine() *BotGoroutine {
-	return globalBotGoroutine
+	debouncer.Stop()
 }
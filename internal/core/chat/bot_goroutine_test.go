@@ -0,0 +1,120 @@
+package chat
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/models"
+)
+
+// newManagedSession 테스트용 SSE 매니저에 등록된 세션
+func newManagedSession(t *testing.T) (*middleware.SSEManager, *middleware.SSESession) {
+	t.Helper()
+
+	sm := middleware.NewSSEManager(middleware.DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	session, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	return sm, session
+}
+
+// newStoppedSession 이미 종료된 세션
+func newStoppedSession(t *testing.T) *middleware.SSESession {
+	t.Helper()
+
+	sm, session := newManagedSession(t)
+	if err := sm.StopSession(session.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	return session
+}
+
+func TestBotGoroutineExitsOnStop(t *testing.T) {
+	sm, session := newManagedSession(t)
+	services, _, _ := newTestServices(t)
+
+	services.Bot.StartBotGoroutine(session, nil)
+
+	frame := "data: {\"type\":\"user\",\"content\":\"hi\",\"session_id\":\"" + session.SessionID + "\"}\n\n"
+	session.BotChannel <- frame
+
+	if err := sm.StopSession(session.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	if !session.Wait(time.Second) {
+		t.Fatal("bot goroutine did not exit after stop")
+	}
+
+	// 종료 후 들어온 입력과 응답은 버려져야 함 (panic 없음)
+	session.BotChannel <- frame
+	services.Bot.sendBotMessage(session, &models.ChatMessage{Content: "late reply", CreatedAt: time.Now()})
+}
+
+func TestStopDuringStreamedReply(t *testing.T) {
+	sm, session := newManagedSession(t)
+
+	// 응답 스트리밍 중인 생성 고루틴 흉내: 세션이 끝날 때까지 조각을 계속 보냄
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sender := newDeltaSender(session)
+		for sender.Write("token ") == nil {
+			sender.Flush()
+			// 클라이언트 역할로 채널을 비워 가득 차지 않게 함
+			select {
+			case <-session.Channel:
+			default:
+			}
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := sm.StopSession(session.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("streaming writer kept running after stop")
+	}
+}
+
+func TestStartBotGoroutineOnStoppedSession(t *testing.T) {
+	session := newStoppedSession(t)
+	services, _, _ := newTestServices(t)
+
+	services.Bot.StartBotGoroutine(session, nil)
+	if !session.Wait(time.Second) {
+		t.Fatal("bot goroutine started on a stopped session")
+	}
+}
+
+func TestSendBotMessageCountsAsActivity(t *testing.T) {
+	_, session := newManagedSession(t)
+	services, _, _ := newTestServices(t)
+
+	before := session.LastActivity()
+	time.Sleep(5 * time.Millisecond)
+
+	services.Bot.updateReplyState(session, middleware.ReplyState{Pending: true, DueAt: time.Now().Add(time.Second)})
+	if !session.LastActivity().Equal(before) {
+		t.Fatal("reply_state event should not count as session activity")
+	}
+
+	services.Bot.sendBotMessage(session, &models.ChatMessage{Content: "hi", CreatedAt: time.Now()})
+	if !session.LastActivity().After(before) {
+		t.Fatal("bot reply should count as session activity")
+	}
+}
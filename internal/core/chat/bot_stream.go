@@ -0,0 +1,69 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"sermo-be/internal/middleware"
+)
+
+// deltaFlushInterval 응답 조각을 모아서 보내는 최소 간격 (토큰마다 이벤트를 보내 세션 채널이 넘치지 않도록)
+const deltaFlushInterval = 100 * time.Millisecond
+
+// deltaSender 스트리밍 응답 조각을 모아 bot_delta 이벤트로 전송
+// 조각이 유실되더라도 마지막 bot_done 이벤트에 전체 응답이 담기므로 클라이언트는 그 내용으로 교체하면 된다.
+type deltaSender struct {
+	session  *middleware.SSESession
+	pending  strings.Builder
+	lastSent time.Time
+}
+
+// newDeltaSender 세션용 deltaSender 생성
+func newDeltaSender(session *middleware.SSESession) *deltaSender {
+	return &deltaSender{
+		session:  session,
+		lastSent: time.Now(),
+	}
+}
+
+// Write 응답 조각 추가 (세션이 종료되면 에러를 반환해 스트림을 중단)
+func (d *deltaSender) Write(delta string) error {
+	if !d.session.IsActive() {
+		return fmt.Errorf("세션이 종료됨: %s", d.session.SessionID)
+	}
+
+	d.pending.WriteString(delta)
+	if time.Since(d.lastSent) >= deltaFlushInterval {
+		d.Flush()
+	}
+	return nil
+}
+
+// Flush 모아둔 조각을 bot_delta 이벤트로 전송
+func (d *deltaSender) Flush() {
+	if d.pending.Len() == 0 {
+		return
+	}
+
+	deltaMessage := BotMessage{
+		Type:      "bot_delta",
+		Content:   d.pending.String(),
+		Timestamp: time.Now().Format(time.RFC3339),
+		SessionID: d.session.SessionID,
+	}
+	d.pending.Reset()
+	d.lastSent = time.Now()
+
+	deltaData, err := json.Marshal(deltaMessage)
+	if err != nil {
+		log.Printf("bot_delta 이벤트 직렬화 실패: %v", err)
+		return
+	}
+
+	if err := d.session.Send(fmt.Sprintf("data: %s\n\n", string(deltaData))); err != nil {
+		log.Printf("bot_delta 이벤트 전송 실패 - 세션: %s, 에러: %v", d.session.SessionID, err)
+	}
+}
@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sermo-be/internal/middleware"
+)
+
+// readDelta 세션 채널에서 bot_delta 이벤트 하나를 꺼내 파싱
+func readDelta(t *testing.T, session *middleware.SSESession) BotMessage {
+	t.Helper()
+
+	select {
+	case frame := <-session.Channel:
+		var message BotMessage
+		data := strings.TrimSuffix(strings.TrimPrefix(frame, "data: "), "\n\n")
+		if err := json.Unmarshal([]byte(data), &message); err != nil {
+			t.Fatalf("unmarshal %q: %v", frame, err)
+		}
+		return message
+	default:
+		t.Fatal("expected a bot_delta event")
+		return BotMessage{}
+	}
+}
+
+func TestDeltaSenderCoalescesUntilFlush(t *testing.T) {
+	session := middleware.NewSSESession("user-1", "bot-1")
+	sender := newDeltaSender(session)
+
+	for _, delta := range []string{"Hel", "lo", "!"} {
+		if err := sender.Write(delta); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if len(session.Channel) != 0 {
+		t.Fatalf("expected deltas to be buffered, got %d events", len(session.Channel))
+	}
+
+	sender.Flush()
+	message := readDelta(t, session)
+	if message.Type != "bot_delta" || message.Content != "Hello!" {
+		t.Errorf("unexpected event %+v", message)
+	}
+
+	sender.Flush()
+	if len(session.Channel) != 0 {
+		t.Error("empty flush must not emit an event")
+	}
+}
+
+func TestDeltaSenderStopsWhenSessionEnds(t *testing.T) {
+	session := newStoppedSession(t)
+
+	if err := newDeltaSender(session).Write("hi"); err == nil {
+		t.Error("expected error for inactive session")
+	}
+}
+
+func TestGenerateAnswerSendsBotError(t *testing.T) {
+	session := middleware.NewSSESession("user-1", "bot-1")
+	services, _, _ := newTestServices(t)
+
+	// 응답 생성용 모델이 없으면 조용히 끝내지 않고 bot_error 이벤트로 알림
+	if message := services.Bot.answerGenerator.GenerateAnswer(session, "hi", nil); message != nil {
+		t.Fatalf("message = %+v, want nil", message)
+	}
+
+	select {
+	case frame := <-session.Channel:
+		var event map[string]any
+		data := strings.TrimSuffix(strings.TrimPrefix(frame, "data: "), "\n\n")
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("unmarshal %q: %v", frame, err)
+		}
+		if event["type"] != "bot_error" || event["code"] != botErrorLLMUnavailable || event["session_id"] != session.SessionID {
+			t.Errorf("event = %v", event)
+		}
+	default:
+		t.Fatal("expected a bot_error event")
+	}
+}
@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"context"
+	"log"
+
+	"sermo-be/pkg/llm"
+)
+
+// ProcessChatEnd 채팅 종료 후처리 (알람 메시지 생성 및 FCM 전송, 대화 요약)
+// /chat/stop, WebSocket stop 이벤트 등 채팅이 끝나는 모든 경로에서 호출한다.
+func (s *Services) ProcessChatEnd(router *llm.Router, userUUID, chatbotUUID string) {
+	log.Printf("🔄 알람 메시지 생성 시작 - 사용자: %s, 챗봇: %s", userUUID, chatbotUUID)
+
+	// 알람 처리가 끝나면 남은 대화를 요약해 기억으로 저장
+	defer func() {
+		if err := s.Memory.Summarize(context.Background(), router.For(llm.FeatureSummary), userUUID, chatbotUUID, DefaultMemoryPolicy); err != nil {
+			log.Printf("❌ 대화 요약 실패: %v", err)
+		}
+	}()
+
+	alarmModel := router.For(llm.FeatureAlarm)
+	if alarmModel == nil {
+		log.Printf("❌ 알람 생성용 언어 모델이 설정되지 않음 - 알람 생성 중단")
+		return
+	}
+
+	// 알람 메시지 생성 및 데이터베이스 저장
+	config := AlarmMessageConfig{
+		UserUUID:    userUUID,
+		ChatbotUUID: chatbotUUID,
+	}
+
+	log.Printf("📝 알람 메시지 생성 중...")
+	alarmMessage, err := s.Alarms.Generate(alarmModel, config)
+	if err != nil {
+		log.Printf("❌ 알람 메시지 생성 실패: %v", err)
+		return
+	}
+
+	log.Printf("✅ 알람 메시지 생성 성공 - 전송 시간: %s", alarmMessage.SendTime.Format("2006-01-02 15:04:05"))
+
+	// FCM 즉시 전송 (푸시 전송이 설정되지 않았으면 저장만)
+	if s.push == nil {
+		log.Printf("⚠️ 푸시 전송이 설정되지 않음 - FCM 전송 생략")
+		return
+	}
+	if err := s.push.SendToUser(context.Background(), userUUID, newSimpleNotification(alarmMessage.Message)); err != nil {
+		log.Printf("❌ FCM 전송 실패: %v", err)
+	} else {
+		log.Printf("✅ FCM 알람 전송 완료")
+	}
+}
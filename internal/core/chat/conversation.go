@@ -0,0 +1,118 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sermo-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrMessageNotFound 읽음 처리할 메시지가 없거나 해당 대화에 속하지 않는 경우
+var ErrMessageNotFound = errors.New("message not found")
+
+// Conversation 대화 목록의 채팅봇 한 개 (마지막 메시지와 읽지 않은 메시지 수 포함)
+type Conversation struct {
+	ChatbotUUID        string
+	Name               string
+	ImageID            string
+	LastMessageUUID    *string // 대화가 없으면 nil
+	LastMessageType    *string
+	LastMessageContent *string
+	LastMessageAt      *time.Time
+	UnreadCount        int64
+}
+
+// unreadCondition 읽음 위치 r 이후의 채팅봇 메시지 m (사용자가 보낸 메시지는 세지 않음)
+const unreadCondition = `m.message_type = 'chatbot'
+	AND (r.last_read_at IS NULL OR (m.created_at, m.uuid) > (r.last_read_at, r.last_read_message_uuid))`
+
+// conversationsQuery 사용자의 채팅봇별 마지막 메시지와 읽지 않은 메시지 수 (최근 대화 순)
+// LATERAL 없이 상관 서브쿼리만 사용해 Postgres와 SQLite(테스트)에서 같은 쿼리로 동작한다.
+const conversationsQuery = `
+SELECT CAST(c.uuid AS TEXT) AS chatbot_uuid, c.name, c.image_id,
+	CAST(lm.uuid AS TEXT) AS last_message_uuid, lm.message_type AS last_message_type,
+	lm.content AS last_message_content, lm.created_at AS last_message_at,
+	(
+		SELECT COUNT(*)
+		FROM chat_messages m
+		WHERE m.user_uuid = c.user_uuid AND m.chatbot_uuid = CAST(c.uuid AS TEXT) AND ` + unreadCondition + `
+	) AS unread_count
+FROM chatbots c
+LEFT JOIN chat_messages lm ON lm.uuid = (
+	SELECT m.uuid
+	FROM chat_messages m
+	WHERE m.user_uuid = c.user_uuid AND m.chatbot_uuid = CAST(c.uuid AS TEXT)
+	ORDER BY m.created_at DESC, m.uuid DESC
+	LIMIT 1
+)
+LEFT JOIN chat_read_cursors r ON r.user_uuid = c.user_uuid AND r.chatbot_uuid = CAST(c.uuid AS TEXT)
+WHERE c.user_uuid = ?
+ORDER BY lm.created_at IS NULL, lm.created_at DESC, c.created_at DESC`
+
+// ListConversations 사용자의 대화 목록 조회
+func (s *MessageService) ListConversations(userUUID string) ([]Conversation, error) {
+	var conversations []Conversation
+	if err := s.db.Raw(conversationsQuery, userUUID).Scan(&conversations).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch conversations: %w", err)
+	}
+	return conversations, nil
+}
+
+// GetUnreadCount 사용자와 채팅봇 대화의 읽지 않은 메시지 수
+func (s *MessageService) GetUnreadCount(userUUID, chatbotUUID string) (int64, error) {
+	var count int64
+	err := s.db.Table("chat_messages AS m").
+		Joins("LEFT JOIN chat_read_cursors r ON r.user_uuid = m.user_uuid AND r.chatbot_uuid = m.chatbot_uuid").
+		Where("m.user_uuid = ? AND m.chatbot_uuid = ?", userUUID, chatbotUUID).
+		Where(unreadCondition).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead 읽음 위치를 메시지까지 이동 (messageUUID가 비어 있으면 마지막 메시지까지)
+// 읽음 위치는 앞으로만 이동하므로 오래된 메시지로 요청해도 이미 읽은 메시지가 다시 읽지 않음이 되지 않는다.
+func (s *MessageService) MarkRead(userUUID, chatbotUUID, messageUUID string) (*models.ChatReadCursor, error) {
+	query := s.db.Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID)
+	if messageUUID != "" {
+		query = query.Where("uuid = ?", messageUUID)
+	}
+
+	var message models.ChatMessage
+	err := query.Order("created_at DESC, uuid DESC").First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if messageUUID == "" {
+			// 대화가 없으면 읽을 메시지도 없음
+			return nil, nil
+		}
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message: %w", err)
+	}
+
+	err = s.db.Exec(`
+INSERT INTO chat_read_cursors (user_uuid, chatbot_uuid, last_read_message_uuid, last_read_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_uuid, chatbot_uuid) DO UPDATE SET
+	last_read_message_uuid = EXCLUDED.last_read_message_uuid,
+	last_read_at = EXCLUDED.last_read_at,
+	updated_at = EXCLUDED.updated_at
+WHERE (EXCLUDED.last_read_at, EXCLUDED.last_read_message_uuid)
+	> (chat_read_cursors.last_read_at, chat_read_cursors.last_read_message_uuid)`,
+		userUUID, chatbotUUID, message.UUID, message.CreatedAt, time.Now()).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update read cursor: %w", err)
+	}
+
+	var cursor models.ChatReadCursor
+	if err := s.db.Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID).First(&cursor).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch read cursor: %w", err)
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,175 @@
+package chat_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/models"
+	"sermo-be/internal/repository"
+	"sermo-be/internal/testutil"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// conversationFixture 채팅봇 두 개(하나는 대화 없음)를 가진 사용자의 대화
+type conversationFixture struct {
+	db      *gorm.DB
+	service *chat.MessageService
+	chatbot models.Chatbot
+	silent  models.Chatbot
+	base    time.Time
+}
+
+func newConversationFixture(t *testing.T) *conversationFixture {
+	t.Helper()
+	db := testutil.NewDB(t)
+	f := &conversationFixture{
+		db:      db,
+		service: chat.NewMessageService(repository.NewGormRepositories(db).Messages, db),
+		base:    time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	for i, bot := range []*models.Chatbot{&f.silent, &f.chatbot} {
+		*bot = models.Chatbot{
+			UUID:      uuid.New(),
+			Name:      "bot",
+			ImageID:   "no-image",
+			Gender:    models.GenderUnspecified,
+			UserUUID:  historyUser,
+			CreatedAt: f.base.Add(time.Duration(i) * time.Minute),
+		}
+		if err := db.Create(bot).Error; err != nil {
+			t.Fatalf("create chatbot: %v", err)
+		}
+	}
+	return f
+}
+
+// addMessage 채팅봇 대화에 base+offset 시각의 메시지 저장
+func (f *conversationFixture) addMessage(t *testing.T, messageType models.MessageType, content string, offset time.Duration) models.ChatMessage {
+	t.Helper()
+	message := models.ChatMessage{
+		UUID:        uuid.New(),
+		SessionID:   "session-1",
+		UserUUID:    historyUser,
+		ChatbotUUID: f.chatbot.UUID.String(),
+		MessageType: messageType,
+		Content:     content,
+		CreatedAt:   f.base.Add(offset),
+	}
+	if err := f.db.Create(&message).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return message
+}
+
+func (f *conversationFixture) unread(t *testing.T) int64 {
+	t.Helper()
+	count, err := f.service.GetUnreadCount(historyUser, f.chatbot.UUID.String())
+	if err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	return count
+}
+
+func TestUnreadCountIgnoresUserMessages(t *testing.T) {
+	f := newConversationFixture(t)
+
+	f.addMessage(t, models.MessageTypeUser, "hi", time.Second)
+	f.addMessage(t, models.MessageTypeUser, "are you there?", 2*time.Second)
+	if unread := f.unread(t); unread != 0 {
+		t.Errorf("unread = %d, want 0 for user messages only", unread)
+	}
+
+	f.addMessage(t, models.MessageTypeChatbot, "hello!", 3*time.Second)
+	if unread := f.unread(t); unread != 1 {
+		t.Errorf("unread = %d, want 1 after a bot reply", unread)
+	}
+}
+
+func TestMarkReadOnlyMovesForward(t *testing.T) {
+	f := newConversationFixture(t)
+	chatbotUUID := f.chatbot.UUID.String()
+
+	older := f.addMessage(t, models.MessageTypeChatbot, "first", time.Second)
+	f.addMessage(t, models.MessageTypeUser, "reply", 2*time.Second)
+	latest := f.addMessage(t, models.MessageTypeChatbot, "second", 3*time.Second)
+
+	cursor, err := f.service.MarkRead(historyUser, chatbotUUID, "")
+	if err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if cursor.LastReadMessageUUID != latest.UUID {
+		t.Errorf("cursor = %s, want the latest message %s", cursor.LastReadMessageUUID, latest.UUID)
+	}
+	if unread := f.unread(t); unread != 0 {
+		t.Errorf("unread = %d, want 0 after reading everything", unread)
+	}
+
+	// 오래된 메시지로 읽음 처리해도 읽음 위치는 그대로
+	cursor, err = f.service.MarkRead(historyUser, chatbotUUID, older.UUID.String())
+	if err != nil {
+		t.Fatalf("MarkRead older: %v", err)
+	}
+	if cursor.LastReadMessageUUID != latest.UUID {
+		t.Errorf("cursor moved back to %s, want %s", cursor.LastReadMessageUUID, latest.UUID)
+	}
+	if unread := f.unread(t); unread != 0 {
+		t.Errorf("unread = %d, want 0 after marking an older message", unread)
+	}
+
+	f.addMessage(t, models.MessageTypeChatbot, "third", 4*time.Second)
+	if unread := f.unread(t); unread != 1 {
+		t.Errorf("unread = %d, want 1 for the message after the cursor", unread)
+	}
+}
+
+func TestMarkReadRejectsForeignMessage(t *testing.T) {
+	f := newConversationFixture(t)
+
+	if cursor, err := f.service.MarkRead(historyUser, f.chatbot.UUID.String(), ""); err != nil || cursor != nil {
+		t.Errorf("MarkRead on an empty conversation = %v, %v, want nil, nil", cursor, err)
+	}
+	if _, err := f.service.MarkRead(historyUser, f.chatbot.UUID.String(), uuid.NewString()); !errors.Is(err, chat.ErrMessageNotFound) {
+		t.Errorf("MarkRead unknown message = %v, want ErrMessageNotFound", err)
+	}
+}
+
+func TestListConversations(t *testing.T) {
+	f := newConversationFixture(t)
+
+	read := f.addMessage(t, models.MessageTypeChatbot, "hello", time.Second)
+	if _, err := f.service.MarkRead(historyUser, f.chatbot.UUID.String(), read.UUID.String()); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	f.addMessage(t, models.MessageTypeChatbot, "how was your day?", 2*time.Second)
+	last := f.addMessage(t, models.MessageTypeUser, "good", 3*time.Second)
+
+	conversations, err := f.service.ListConversations(historyUser)
+	if err != nil {
+		t.Fatalf("ListConversations: %v", err)
+	}
+	if len(conversations) != 2 {
+		t.Fatalf("conversations = %+v, want 2", conversations)
+	}
+
+	// 대화가 있는 채팅봇이 먼저, 대화가 없는 채팅봇은 뒤로
+	got := conversations[0]
+	if got.ChatbotUUID != f.chatbot.UUID.String() || got.LastMessageUUID == nil || *got.LastMessageUUID != last.UUID.String() {
+		t.Errorf("first conversation = %+v, want %s with last message %s", got, f.chatbot.UUID, last.UUID)
+	}
+	if got.LastMessageContent == nil || *got.LastMessageContent != "good" || got.LastMessageAt == nil || !got.LastMessageAt.Equal(last.CreatedAt) {
+		t.Errorf("last message = %v at %v", got.LastMessageContent, got.LastMessageAt)
+	}
+	if got.UnreadCount != 1 {
+		t.Errorf("unread = %d, want 1", got.UnreadCount)
+	}
+
+	empty := conversations[1]
+	if empty.ChatbotUUID != f.silent.UUID.String() || empty.LastMessageUUID != nil || empty.UnreadCount != 0 {
+		t.Errorf("empty conversation = %+v", empty)
+	}
+}
@@ -6,28 +6,26 @@ import (
 	"log"
 	"time"
 
-	"sermo-be/internal/config"
-	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
+	"sermo-be/internal/repository"
 	"sermo-be/pkg/firebase"
 )
 
-// FCMSender FCM 전송을 담당하는 구조체
+// FCMSender FCM 전송을 담당하는 구조체 (PushSender 구현)
 type FCMSender struct {
 	firebaseClient *firebase.Client
+	tokens         repository.FCMTokenRepository
 }
 
 // NewFCMSender 새로운 FCMSender 생성
-func NewFCMSender(firebaseClient *firebase.Client) *FCMSender {
+func NewFCMSender(firebaseClient *firebase.Client, tokens repository.FCMTokenRepository) *FCMSender {
 	return &FCMSender{
 		firebaseClient: firebaseClient,
+		tokens:         tokens,
 	}
 }
 
-// SendImmediateFCM 즉시 FCM 전송 (테스트용)
-func (fs *FCMSender) SendImmediateFCM(userUUID, message string) error {
-	ctx := context.Background()
-
+// SendToUser 사용자의 모든 기기로 알림 전송 (일부 토큰 전송 실패는 로그만 남김)
+func (fs *FCMSender) SendToUser(ctx context.Context, userUUID string, notification *firebase.ChatNotification) error {
 	// 사용자의 FCM 토큰 조회
 	fcmTokens, err := fs.getUserFCMTokens(userUUID)
 	if err != nil {
@@ -38,12 +36,9 @@ func (fs *FCMSender) SendImmediateFCM(userUUID, message string) error {
 		return fmt.Errorf("사용자의 FCM 토큰이 없음")
 	}
 
-	// FCM 메시지 생성
-	fcmMessage := fs.createSimpleFCMMessage(message)
-
 	// 각 FCM 토큰으로 전송
 	for _, token := range fcmTokens {
-		if err := fs.sendSingleFCM(ctx, fcmMessage, token); err != nil {
+		if err := fs.sendSingleFCM(ctx, notification, token); err != nil {
 			log.Printf("⚠️ 개별 FCM 전송 실패 - 토큰: %s, 에러: %v", token, err)
 			continue
 		}
@@ -52,43 +47,11 @@ func (fs *FCMSender) SendImmediateFCM(userUUID, message string) error {
 	return nil
 }
 
-// SendImmediateFCMNotification 즉시 FCM 알람 전송 (패키지 레벨 함수)
-func SendImmediateFCMNotification(userUUID, message, chatbotUUID string) error {
-	// Firebase 클라이언트 생성을 위한 기본 설정
-	// TODO: 실제 환경에서는 설정을 전달받아서 생성하도록 수정 필요
-	config := &config.Config{
-		Firebase: config.FirebaseConfig{
-			ProjectID:           "sermo-project", // 기본값
-			PrivateKeyID:        "",
-			PrivateKey:          "",
-			ClientEmail:         "",
-			ClientID:            "",
-			AuthURI:             "",
-			TokenURI:            "",
-			AuthProviderCertURL: "",
-			ClientCertURL:       "",
-			UniverseDomain:      "",
-		},
-	}
-
-	firebaseClient, err := firebase.NewClient(config)
-	if err != nil {
-		return fmt.Errorf("Firebase 클라이언트 생성 실패: %w", err)
-	}
-	defer firebaseClient.Close()
-
-	// FCMSender 생성
-	fcmSender := NewFCMSender(firebaseClient)
-
-	// FCM 전송
-	return fcmSender.SendImmediateFCM(userUUID, message)
-}
-
 // getUserFCMTokens 사용자의 FCM 토큰들 조회
 func (fs *FCMSender) getUserFCMTokens(userUUID string) ([]string, error) {
-	var fcmTokens []models.FCMToken
-	if err := database.DB.Where("user_uuid = ?", userUUID).Find(&fcmTokens).Error; err != nil {
-		return nil, fmt.Errorf("FCM 토큰 조회 실패: %w", err)
+	fcmTokens, err := fs.tokens.ListByUser(userUUID)
+	if err != nil {
+		return nil, err
 	}
 
 	var tokens []string
@@ -101,8 +64,8 @@ func (fs *FCMSender) getUserFCMTokens(userUUID string) ([]string, error) {
 	return tokens, nil
 }
 
-// createSimpleFCMMessage 간단한 FCM 메시지 생성
-func (fs *FCMSender) createSimpleFCMMessage(message string) *firebase.ChatNotification {
+// newSimpleNotification 채팅봇 정보 없이 보내는 간단한 알림 생성
+func newSimpleNotification(message string) *firebase.ChatNotification {
 	return firebase.NewChatNotification(
 		"Sermo",
 		"",
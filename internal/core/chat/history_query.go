@@ -0,0 +1,142 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sermo-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultHistoryLimit 히스토리 한 페이지 기본 메시지 수
+	DefaultHistoryLimit = 50
+	// MaxHistoryLimit 히스토리 한 페이지 최대 메시지 수
+	MaxHistoryLimit = 100
+)
+
+// ErrCursorNotFound 커서로 지정한 메시지가 없거나 조회 대상 대화에 속하지 않는 경우
+var ErrCursorNotFound = errors.New("cursor message not found")
+
+// HistoryQuery 대화 히스토리 조회 조건
+// Before/After는 메시지 UUID 커서로, 둘 다 비어 있으면 가장 최근 메시지부터 조회한다.
+type HistoryQuery struct {
+	UserUUID    string
+	ChatbotUUID string // 비어 있으면 사용자의 모든 채팅봇 대상 (검색)
+	SessionID   string
+	From        *time.Time // 이 시각 이후 (포함)
+	To          *time.Time // 이 시각 이전 (미포함)
+	Search      string     // 본문 전문 검색어 (Postgres tsvector)
+	Before      string     // 이 메시지보다 이전 메시지 조회
+	After       string     // 이 메시지보다 이후 메시지 조회
+	Limit       int
+}
+
+// HistoryPage 히스토리 조회 결과 (메시지는 항상 오래된 순)
+type HistoryPage struct {
+	Messages      []models.ChatMessage
+	Total         int64 // 커서와 무관하게 조건에 맞는 전체 메시지 수
+	HasMoreBefore bool  // 첫 메시지보다 이전 메시지가 더 있는지
+	HasMoreAfter  bool  // 마지막 메시지보다 이후 메시지가 더 있는지
+}
+
+// QueryHistory 커서 기반 대화 히스토리 조회
+// (created_at, uuid) 순서로 정렬해 같은 시각의 메시지도 빠지거나 중복되지 않게 페이지를 나눈다.
+func (s *MessageService) QueryHistory(q HistoryQuery) (*HistoryPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = DefaultHistoryLimit
+	}
+	if q.Limit > MaxHistoryLimit {
+		q.Limit = MaxHistoryLimit
+	}
+
+	var total int64
+	if err := s.filterHistory(s.db.Model(&models.ChatMessage{}), q).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	query := s.filterHistory(s.db.Model(&models.ChatMessage{}), q)
+
+	// 커서 메시지 위치 조회 (같은 조건의 대화에 속한 메시지만 허용)
+	cursorUUID, newerFirst := q.Before, true
+	if q.After != "" {
+		cursorUUID, newerFirst = q.After, false
+	}
+	if cursorUUID != "" {
+		var cursor models.ChatMessage
+		err := s.db.Where("uuid = ? AND user_uuid = ?", cursorUUID, q.UserUUID).First(&cursor).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && q.ChatbotUUID != "" && cursor.ChatbotUUID != q.ChatbotUUID) {
+			return nil, ErrCursorNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch cursor message: %w", err)
+		}
+
+		if newerFirst {
+			query = query.Where("(created_at, uuid) < (?, ?)", cursor.CreatedAt, cursor.UUID)
+		} else {
+			query = query.Where("(created_at, uuid) > (?, ?)", cursor.CreatedAt, cursor.UUID)
+		}
+	}
+
+	// 커서에서 멀어지는 방향으로 한 개 더 조회해 다음 페이지 존재 여부 판단
+	order := "created_at ASC, uuid ASC"
+	if newerFirst {
+		order = "created_at DESC, uuid DESC"
+	}
+
+	var messages []models.ChatMessage
+	if err := query.Order(order).Limit(q.Limit + 1).Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch chat history: %w", err)
+	}
+
+	hasMore := len(messages) > q.Limit
+	if hasMore {
+		messages = messages[:q.Limit]
+	}
+	if newerFirst {
+		reverseMessages(messages)
+	}
+
+	page := &HistoryPage{Messages: messages, Total: total}
+	switch {
+	case q.Before != "":
+		page.HasMoreBefore, page.HasMoreAfter = hasMore, true
+	case q.After != "":
+		page.HasMoreBefore, page.HasMoreAfter = true, hasMore
+	default:
+		page.HasMoreBefore = hasMore
+	}
+	return page, nil
+}
+
+// filterHistory 커서를 제외한 조회 조건 적용
+func (s *MessageService) filterHistory(query *gorm.DB, q HistoryQuery) *gorm.DB {
+	query = query.Where("user_uuid = ?", q.UserUUID)
+	if q.ChatbotUUID != "" {
+		query = query.Where("chatbot_uuid = ?", q.ChatbotUUID)
+	}
+	if q.SessionID != "" {
+		query = query.Where("session_id = ?", q.SessionID)
+	}
+	if q.From != nil {
+		query = query.Where("created_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		query = query.Where("created_at < ?", *q.To)
+	}
+	if q.Search != "" {
+		// 한국어/영어가 섞여 있어 형태소 분석 없이 'simple' 사전 사용 (content_tsv와 같은 사전이어야 인덱스를 탐)
+		query = query.Where("content_tsv @@ plainto_tsquery('simple', ?)", q.Search)
+	}
+	return query
+}
+
+// reverseMessages 메시지 순서 뒤집기
+func reverseMessages(messages []models.ChatMessage) {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+}
@@ -0,0 +1,195 @@
+package chat_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/models"
+	"sermo-be/internal/repository"
+	"sermo-be/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+const (
+	historyUser     = "00000000-0000-4000-8000-000000000001"
+	historyBot      = "00000000-0000-4000-8000-0000000000b1"
+	historyOtherBot = "00000000-0000-4000-8000-0000000000b2"
+)
+
+// seedHistory 같은 created_at을 가진 메시지가 섞인 대화를 저장하고 (created_at, uuid) 순서로 반환
+func seedHistory(t *testing.T) (*chat.MessageService, []models.ChatMessage, models.ChatMessage, models.ChatMessage) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	service := chat.NewMessageService(repository.NewGormRepositories(db).Messages, db)
+
+	base := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	newMessage := func(userUUID, chatbotUUID string, at time.Time) models.ChatMessage {
+		return models.ChatMessage{
+			UUID:        uuid.New(),
+			SessionID:   "session-1",
+			UserUUID:    userUUID,
+			ChatbotUUID: chatbotUUID,
+			MessageType: models.MessageTypeUser,
+			Content:     "message",
+			CreatedAt:   at,
+		}
+	}
+
+	// 0초 1개, 1초 3개, 2초 2개, 3초 1개
+	var conversation []models.ChatMessage
+	for i, count := range []int{1, 3, 2, 1} {
+		for j := 0; j < count; j++ {
+			conversation = append(conversation, newMessage(historyUser, historyBot, base.Add(time.Duration(i)*time.Second)))
+		}
+	}
+	otherBot := newMessage(historyUser, historyOtherBot, base.Add(time.Second))
+	otherUser := newMessage("00000000-0000-4000-8000-000000000002", historyBot, base.Add(time.Second))
+
+	for _, message := range append(append([]models.ChatMessage{}, conversation...), otherBot, otherUser) {
+		message := message
+		if err := db.Create(&message).Error; err != nil {
+			t.Fatalf("seed message: %v", err)
+		}
+	}
+
+	sort.Slice(conversation, func(i, j int) bool {
+		if !conversation[i].CreatedAt.Equal(conversation[j].CreatedAt) {
+			return conversation[i].CreatedAt.Before(conversation[j].CreatedAt)
+		}
+		return conversation[i].UUID.String() < conversation[j].UUID.String()
+	})
+	return service, conversation, otherBot, otherUser
+}
+
+func messageIDs(messages []models.ChatMessage) []string {
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.UUID.String()
+	}
+	return ids
+}
+
+func assertMessages(t *testing.T, got, want []models.ChatMessage) {
+	t.Helper()
+	gotIDs, wantIDs := messageIDs(got), messageIDs(want)
+	if len(gotIDs) != len(wantIDs) {
+		t.Fatalf("messages = %v, want %v", gotIDs, wantIDs)
+	}
+	for i := range gotIDs {
+		if gotIDs[i] != wantIDs[i] {
+			t.Fatalf("messages = %v, want %v", gotIDs, wantIDs)
+		}
+	}
+}
+
+func TestQueryHistoryPages(t *testing.T) {
+	service, conversation, _, _ := seedHistory(t)
+	id := func(i int) string { return conversation[i].UUID.String() }
+
+	tests := []struct {
+		name           string
+		before, after  string
+		limit          int
+		want           []models.ChatMessage
+		wantMoreBefore bool
+		wantMoreAfter  bool
+	}{
+		{name: "latest page", limit: 2, want: conversation[5:7], wantMoreBefore: true},
+		{name: "whole conversation", limit: 10, want: conversation},
+		{name: "default limit", want: conversation},
+		{name: "before cursor inside a tie", before: id(2), limit: 2, want: conversation[0:2], wantMoreAfter: true},
+		{name: "before cursor with more", before: id(5), limit: 2, want: conversation[3:5], wantMoreBefore: true, wantMoreAfter: true},
+		{name: "before first message", before: id(0), limit: 2, want: nil, wantMoreAfter: true},
+		{name: "after cursor inside a tie", after: id(1), limit: 3, want: conversation[2:5], wantMoreBefore: true, wantMoreAfter: true},
+		{name: "after cursor reaching the end", after: id(4), limit: 2, want: conversation[5:7], wantMoreBefore: true},
+		{name: "after last message", after: id(6), limit: 2, want: nil, wantMoreBefore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.QueryHistory(chat.HistoryQuery{
+				UserUUID:    historyUser,
+				ChatbotUUID: historyBot,
+				Before:      tt.before,
+				After:       tt.after,
+				Limit:       tt.limit,
+			})
+			if err != nil {
+				t.Fatalf("QueryHistory: %v", err)
+			}
+
+			assertMessages(t, page.Messages, tt.want)
+			if page.HasMoreBefore != tt.wantMoreBefore || page.HasMoreAfter != tt.wantMoreAfter {
+				t.Errorf("HasMoreBefore/After = %v/%v, want %v/%v", page.HasMoreBefore, page.HasMoreAfter, tt.wantMoreBefore, tt.wantMoreAfter)
+			}
+			if page.Total != int64(len(conversation)) {
+				t.Errorf("Total = %d, want %d regardless of the cursor", page.Total, len(conversation))
+			}
+		})
+	}
+}
+
+func TestQueryHistoryWalksEveryMessageOnce(t *testing.T) {
+	service, conversation, _, _ := seedHistory(t)
+	query := chat.HistoryQuery{UserUUID: historyUser, ChatbotUUID: historyBot, Limit: 2}
+
+	// 최신 페이지부터 Before 커서로 거슬러 올라가기
+	var backward []models.ChatMessage
+	for q := query; ; {
+		page, err := service.QueryHistory(q)
+		if err != nil {
+			t.Fatalf("QueryHistory: %v", err)
+		}
+		backward = append(append([]models.ChatMessage{}, page.Messages...), backward...)
+		if !page.HasMoreBefore {
+			break
+		}
+		q.Before = page.Messages[0].UUID.String()
+	}
+	assertMessages(t, backward, conversation)
+
+	// 첫 메시지부터 After 커서로 내려가기
+	forward := []models.ChatMessage{conversation[0]}
+	for q := query; ; {
+		q.After = forward[len(forward)-1].UUID.String()
+		page, err := service.QueryHistory(q)
+		if err != nil {
+			t.Fatalf("QueryHistory: %v", err)
+		}
+		forward = append(forward, page.Messages...)
+		if !page.HasMoreAfter {
+			break
+		}
+	}
+	assertMessages(t, forward, conversation)
+}
+
+func TestQueryHistoryRejectsForeignCursor(t *testing.T) {
+	service, _, otherBot, otherUser := seedHistory(t)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"message of another chatbot", otherBot.UUID.String()},
+		{"message of another user", otherUser.UUID.String()},
+		{"unknown message", uuid.NewString()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, q := range []chat.HistoryQuery{
+				{UserUUID: historyUser, ChatbotUUID: historyBot, Before: tt.cursor},
+				{UserUUID: historyUser, ChatbotUUID: historyBot, After: tt.cursor},
+			} {
+				if _, err := service.QueryHistory(q); !errors.Is(err, chat.ErrCursorNotFound) {
+					t.Fatalf("QueryHistory = %v, want ErrCursorNotFound", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"sermo-be/internal/core/usage"
+	"sermo-be/internal/models"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/prompt"
+)
+
+const (
+	// memoryHistoryFetchLimit 봇 응답 생성 시 조회하는 요약되지 않은 최근 메시지 최대 수 (이 중 토큰 예산만큼만 사용)
+	memoryHistoryFetchLimit = 100
+	// memorySummarizeBatch 한 번의 요약에 넣는 최대 메시지 수 (밀린 메시지는 다음 요약에서 처리)
+	memorySummarizeBatch = 100
+	// memorySummarizeTimeout 요약 생성 제한 시간
+	memorySummarizeTimeout = 60 * time.Second
+)
+
+// MemoryPolicy 대화 기억(누적 요약)과 히스토리 선택 정책
+type MemoryPolicy struct {
+	HistoryTokenBudget int // 프롬프트에 넣는 최근 대화의 최대 토큰 수 (추정치)
+	SummarizeAfter     int // 요약되지 않은 메시지가 이 수에 도달하면 요약
+	KeepRecent         int // 요약할 때 원문으로 남겨두는 최근 메시지 수
+}
+
+// DefaultMemoryPolicy 기본 정책 (서버 시작 시 설정값으로 교체)
+var DefaultMemoryPolicy = MemoryPolicy{
+	HistoryTokenBudget: 1500,
+	SummarizeAfter:     40,
+	KeepRecent:         20,
+}
+
+// MemoryService 사용자-채팅봇 대화의 누적 요약을 관리하는 서비스
+type MemoryService struct {
+	messages repository.ChatMessageRepository
+	memories repository.ConversationMemoryRepository
+	chatbots repository.ChatbotRepository
+	usage    UsageTracker
+	running  sync.Map // 요약 중인 사용자-채팅봇 (같은 대화를 동시에 요약하지 않도록)
+}
+
+// NewMemoryService 새로운 MemoryService 인스턴스 생성
+func NewMemoryService(repos *repository.Repositories, tracker UsageTracker) *MemoryService {
+	return &MemoryService{
+		messages: repos.Messages,
+		memories: repos.Memories,
+		chatbots: repos.Chatbots,
+		usage:    tracker,
+	}
+}
+
+// GetMemory 대화 요약 조회 (아직 요약이 없으면 nil)
+func (ms *MemoryService) GetMemory(userUUID, chatbotUUID string) (*models.ConversationMemory, error) {
+	memory, err := ms.memories.Find(userUUID, chatbotUUID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conversation memory: %w", err)
+	}
+	return memory, nil
+}
+
+// GetRecentHistory 요약에 반영되지 않은 최근 메시지 조회 (오래된 순, 최대 limit개)
+func (ms *MemoryService) GetRecentHistory(memory *models.ConversationMemory, userUUID, chatbotUUID string, limit int) ([]models.ChatMessage, error) {
+	return ms.messages.Recent(userUUID, chatbotUUID, repository.MemoryCursor(memory), limit)
+}
+
+// Summarize 요약되지 않은 메시지가 정책의 기준만큼 쌓였으면 오래된 메시지를 기존 요약과 합쳐 저장
+// 같은 대화를 이미 요약하는 중이거나 요약 모델이 없으면 바로 반환한다.
+func (ms *MemoryService) Summarize(ctx context.Context, model llm.LLM, userUUID, chatbotUUID string, policy MemoryPolicy) error {
+	if model == nil {
+		return nil
+	}
+
+	key := pairKey(userUUID, chatbotUUID)
+	if _, busy := ms.running.LoadOrStore(key, struct{}{}); busy {
+		return nil
+	}
+	defer ms.running.Delete(key)
+
+	memory, err := ms.GetMemory(userUUID, chatbotUUID)
+	if err != nil {
+		return err
+	}
+
+	pending, err := ms.messages.Count(userUUID, chatbotUUID, repository.MemoryCursor(memory))
+	if err != nil {
+		return fmt.Errorf("failed to count unsummarized messages: %w", err)
+	}
+	if pending < int64(policy.SummarizeAfter) {
+		return nil
+	}
+
+	// 최근 KeepRecent개를 제외한 오래된 메시지부터 요약
+	batch := int(pending) - policy.KeepRecent
+	if batch > memorySummarizeBatch {
+		batch = memorySummarizeBatch
+	}
+
+	messages, err := ms.messages.Oldest(userUUID, chatbotUUID, repository.MemoryCursor(memory), batch)
+	if err != nil {
+		return fmt.Errorf("failed to fetch messages to summarize: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	chatbot, err := ms.chatbots.FindByUUID(chatbotUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch chatbot: %w", err)
+	}
+
+	previous := ""
+	if memory != nil {
+		previous = memory.Summary
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, memorySummarizeTimeout)
+	defer cancel()
+
+	response, err := model.ChatCompletion(ctx, []llm.ChatMessage{
+		{Role: "system", Content: prompt.ConversationSummarySystemPrompt},
+		{Role: "user", Content: prompt.BuildConversationSummaryPrompt(chatbot.Name, previous, messages)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+	ms.usage.RecordChat(userUUID, usage.FeatureSummary, model, response)
+
+	summary := strings.TrimSpace(response.Message.Content)
+	if summary == "" {
+		return fmt.Errorf("failed to summarize conversation: empty summary")
+	}
+
+	last := messages[len(messages)-1]
+	if err := ms.saveMemory(userUUID, chatbotUUID, summary, last, len(messages)); err != nil {
+		return err
+	}
+
+	log.Printf("대화 요약 저장 - 사용자: %s, 챗봇: %s, 요약한 메시지: %d", userUUID, chatbotUUID, len(messages))
+	return nil
+}
+
+// saveMemory 누적 요약 저장 (요약 위치는 앞으로만 이동하므로 다른 인스턴스가 먼저 저장한 최신 요약을 덮어쓰지 않음)
+func (ms *MemoryService) saveMemory(userUUID, chatbotUUID, summary string, last models.ChatMessage, count int) error {
+	return ms.memories.Advance(&models.ConversationMemory{
+		UserUUID:            userUUID,
+		ChatbotUUID:         chatbotUUID,
+		Summary:             summary,
+		SummarizedUntilAt:   last.CreatedAt,
+		SummarizedUntilUUID: last.UUID,
+		SummarizedCount:     count,
+	})
+}
+
+// SelectHistory 토큰 예산 안에서 최신 메시지부터 골라 오래된 순으로 반환
+// 가장 최근 메시지는 예산을 넘더라도 항상 포함한다.
+func SelectHistory(history []models.ChatMessage, budget int) []models.ChatMessage {
+	used := 0
+	start := len(history)
+	for i := len(history) - 1; i >= 0; i-- {
+		tokens := EstimateTokens(history[i].Content)
+		if start < len(history) && used+tokens > budget {
+			break
+		}
+		used += tokens
+		start = i
+	}
+	return history[start:]
+}
+
+// trimPendingUserMessages 마지막 봇 응답 이후의 사용자 메시지 제외
+// 이번 응답 대상인 사용자 메시지는 합쳐서 따로 전달하므로 히스토리에 중복으로 넣지 않는다.
+func trimPendingUserMessages(history []models.ChatMessage) []models.ChatMessage {
+	end := len(history)
+	for end > 0 && history[end-1].MessageType == models.MessageTypeUser {
+		end--
+	}
+	return history[:end]
+}
+
+// EstimateTokens 텍스트의 토큰 수 추정
+// 영문 등 ASCII는 4자당 1토큰, 한글 등 그 외 문자는 1자당 1토큰으로 계산하고 메시지 구분용 토큰을 더한다.
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other + 4
+}
+
+// pairKey 사용자-채팅봇 키
+func pairKey(userUUID, chatbotUUID string) string {
+	return userUUID + ":" + chatbotUUID
+}
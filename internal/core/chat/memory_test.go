@@ -0,0 +1,70 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"sermo-be/internal/models"
+)
+
+func historyOf(types ...models.MessageType) []models.ChatMessage {
+	history := make([]models.ChatMessage, len(types))
+	for i, messageType := range types {
+		history[i] = models.ChatMessage{MessageType: messageType, Content: strings.Repeat("a", 36)} // 13토큰
+	}
+	return history
+}
+
+func TestSelectHistoryKeepsNewestWithinBudget(t *testing.T) {
+	history := historyOf(models.MessageTypeUser, models.MessageTypeChatbot, models.MessageTypeUser, models.MessageTypeChatbot)
+	for i := range history {
+		history[i].Content += string(rune('0' + i))
+	}
+
+	selected := SelectHistory(history, 30)
+	if len(selected) != 2 {
+		t.Fatalf("선택된 메시지 수 = %d, 기대값 2", len(selected))
+	}
+	if selected[0].Content != history[2].Content || selected[1].Content != history[3].Content {
+		t.Fatalf("최신 메시지가 오래된 순으로 선택되지 않음: %+v", selected)
+	}
+
+	if got := SelectHistory(history, 1000); len(got) != len(history) {
+		t.Fatalf("예산이 충분하면 전체를 선택해야 함: %d", len(got))
+	}
+}
+
+func TestSelectHistoryAlwaysIncludesLatest(t *testing.T) {
+	history := historyOf(models.MessageTypeUser, models.MessageTypeChatbot)
+
+	selected := SelectHistory(history, 1)
+	if len(selected) != 1 || selected[0].MessageType != models.MessageTypeChatbot {
+		t.Fatalf("예산을 넘어도 가장 최근 메시지는 포함해야 함: %+v", selected)
+	}
+
+	if got := SelectHistory(nil, 100); len(got) != 0 {
+		t.Fatalf("빈 히스토리에서 메시지가 선택됨: %+v", got)
+	}
+}
+
+func TestTrimPendingUserMessages(t *testing.T) {
+	history := historyOf(models.MessageTypeUser, models.MessageTypeChatbot, models.MessageTypeUser, models.MessageTypeUser)
+
+	trimmed := trimPendingUserMessages(history)
+	if len(trimmed) != 2 || trimmed[1].MessageType != models.MessageTypeChatbot {
+		t.Fatalf("마지막 봇 응답 이후 사용자 메시지가 제외되지 않음: %+v", trimmed)
+	}
+
+	if got := trimPendingUserMessages(historyOf(models.MessageTypeUser)); len(got) != 0 {
+		t.Fatalf("봇 응답이 없으면 모두 제외해야 함: %+v", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("hello world!"); got != 3+4 {
+		t.Errorf("영문 토큰 추정 = %d, 기대값 7", got)
+	}
+	if got := EstimateTokens("안녕하세요"); got != 5+4 {
+		t.Errorf("한글 토큰 추정 = %d, 기대값 9", got)
+	}
+}
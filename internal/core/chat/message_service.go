@@ -4,18 +4,23 @@ import (
 	"fmt"
 
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
+	"sermo-be/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 // MessageService 채팅 메시지 관련 비즈니스 로직을 담당하는 서비스
-type MessageService struct{}
+type MessageService struct {
+	messages repository.ChatMessageRepository
+	db       *gorm.DB // 대화 목록, 읽음 처리, 히스토리 검색 (Postgres 전용 쿼리)
+}
 
 // NewMessageService 새로운 MessageService 인스턴스 생성
-func NewMessageService() *MessageService {
-	return &MessageService{}
+func NewMessageService(messages repository.ChatMessageRepository, db *gorm.DB) *MessageService {
+	return &MessageService{messages: messages, db: db}
 }
 
-// CreateUserMessage 사용자 메시지를 생성하고 DB에 저장
+// CreateUserMessage 사용자 메시지를 생성하고 저장
 func (s *MessageService) CreateUserMessage(sessionID, userUUID, chatbotUUID, userMessage string) (*models.ChatMessage, error) {
 	// 사용자 메시지 생성 및 저장
 	userChatMessage := models.NewChatMessage(
@@ -26,28 +31,16 @@ func (s *MessageService) CreateUserMessage(sessionID, userUUID, chatbotUUID, use
 		userMessage,
 	)
 
-	if err := database.DB.Create(userChatMessage).Error; err != nil {
+	if err := s.messages.Create(userChatMessage); err != nil {
 		return nil, fmt.Errorf("failed to save user message: %w", err)
 	}
 
 	return userChatMessage, nil
 }
 
-// GetChatHistory 사용자와 채팅봇의 대화 히스토리 조회
+// GetChatHistory 사용자와 채팅봇의 최근 대화 히스토리 조회 (최근 limit개를 오래된 순으로 반환, limit이 0이면 전체)
 func (s *MessageService) GetChatHistory(userUUID, chatbotUUID string, limit int) ([]models.ChatMessage, error) {
-	var messages []models.ChatMessage
-
-	query := database.DB.Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID)
-
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-
-	if err := query.Order("created_at ASC").Find(&messages).Error; err != nil {
-		return nil, fmt.Errorf("failed to fetch chat history: %w", err)
-	}
-
-	return messages, nil
+	return s.messages.Recent(userUUID, chatbotUUID, nil, limit)
 }
 
 // CreateBotMessage 봇 메시지 생성 및 저장
@@ -60,7 +53,7 @@ func (s *MessageService) CreateBotMessage(sessionID, userUUID, chatbotUUID, cont
 		content,
 	)
 
-	if err := database.DB.Create(botMessage).Error; err != nil {
+	if err := s.messages.Create(botMessage); err != nil {
 		return nil, fmt.Errorf("failed to save bot message: %w", err)
 	}
 
@@ -69,21 +62,5 @@ func (s *MessageService) CreateBotMessage(sessionID, userUUID, chatbotUUID, cont
 
 // GetChatHistoryCount 사용자와 채팅봇의 총 메시지 수 조회
 func (s *MessageService) GetChatHistoryCount(userUUID, chatbotUUID string) (int64, error) {
-	var count int64
-
-	if err := database.DB.Model(&models.ChatMessage{}).
-		Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID).
-		Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("failed to count messages: %w", err)
-	}
-
-	return count, nil
-}
-
-// 전역 MessageService 인스턴스
-var globalMessageService = NewMessageService()
-
-// GetMessageService 전역 MessageService 반환
-func GetMessageService() *MessageService {
-	return globalMessageService
+	return s.messages.Count(userUUID, chatbotUUID, nil)
 }
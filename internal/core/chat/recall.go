@@ -0,0 +1,137 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/models"
+	"sermo-be/internal/repository"
+)
+
+const (
+	// recallTopK 현재 메시지와 관련된 지난 내용을 찾을 때 조회하는 최대 수
+	recallTopK = 5
+	// recallMinScore 관련 있다고 판단하는 최소 코사인 유사도
+	recallMinScore = 0.35
+	// recallTimeout 임베딩 생성 및 검색 제한 시간
+	recallTimeout = 10 * time.Second
+	// minIndexRunes 이보다 짧은 사용자 메시지는 기억할 내용이 거의 없어 임베딩하지 않음 ("ㅇㅇ", "ok" 등)
+	minIndexRunes = 8
+)
+
+// recallMemories 현재 메시지와 관련된 지난 사용자 메시지와 사용자 상태 정보 검색
+// 관련된 상태 정보 중 아직 유효한 것이 있으면 가장 관련 있는 하나를 함께 반환한다. 임베딩 모델이나 검색 서비스가 없으면 검색하지 않는다.
+func (ag *AnswerGenerator) recallMemories(ctx context.Context, userUUID, chatbotUUID, currentMessage string, embedder recall.Embedder) (*models.UserStatus, []recall.Match, error) {
+	if embedder == nil || ag.recall == nil {
+		return nil, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, recallTimeout)
+	defer cancel()
+
+	matches, err := ag.recall.Recall(ctx, recall.Tracked(embedder, ag.usage, userUUID), userUUID, chatbotUUID, currentMessage, recallTopK, recallMinScore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var userStatus *models.UserStatus
+	var messages []recall.Match
+	for _, match := range matches {
+		switch match.SourceType {
+		case models.EmbeddingSourceMessage:
+			messages = append(messages, match)
+		case models.EmbeddingSourceUserStatus:
+			if userStatus != nil {
+				continue
+			}
+			// 결과는 관련 있는 순이므로 처음 찾은 유효한 상태 정보가 가장 관련 있음
+			userStatus, err = ag.activeUserStatus(match.SourceUUID.String())
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return userStatus, messages, nil
+}
+
+// activeUserStatus 아직 유효한 사용자 상태 정보 조회 (만료되었거나 비활성이면 nil)
+func (ag *AnswerGenerator) activeUserStatus(statusUUID string) (*models.UserStatus, error) {
+	userStatus, err := ag.statuses.FindActive(statusUUID, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("사용자 상태 정보 조회 실패: %w", err)
+	}
+	return userStatus, nil
+}
+
+// indexUserMessages 사용자 메시지를 임베딩해 저장 (짧은 메시지 제외, 임베딩 모델이나 검색 서비스가 없으면 저장하지 않음)
+func indexUserMessages(ctx context.Context, recaller *recall.Service, embedder recall.Embedder, messages []models.ChatMessage) error {
+	if embedder == nil || recaller == nil {
+		return nil
+	}
+
+	var items []recall.Item
+	for _, msg := range messages {
+		if msg.MessageType != models.MessageTypeUser || utf8.RuneCountInString(msg.Content) < minIndexRunes {
+			continue
+		}
+		items = append(items, recall.Item{
+			UserUUID:    msg.UserUUID,
+			ChatbotUUID: msg.ChatbotUUID,
+			SourceType:  models.EmbeddingSourceMessage,
+			SourceUUID:  msg.UUID.String(),
+			Content:     msg.Content,
+			CreatedAt:   msg.CreatedAt,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, recallTimeout)
+	defer cancel()
+	return recaller.Index(ctx, embedder, items)
+}
+
+// indexUserStatus 사용자 상태 정보를 임베딩해 저장 (임베딩 모델이나 검색 서비스가 없으면 저장하지 않음)
+func indexUserStatus(ctx context.Context, recaller *recall.Service, embedder recall.Embedder, userStatus *models.UserStatus) error {
+	if embedder == nil || recaller == nil {
+		return nil
+	}
+
+	content := userStatus.Event
+	if userStatus.Context != "" {
+		content += ": " + userStatus.Context
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, recallTimeout)
+	defer cancel()
+	return recaller.Index(ctx, embedder, []recall.Item{{
+		UserUUID:    userStatus.UserUUID,
+		ChatbotUUID: userStatus.ChatbotUUID,
+		SourceType:  models.EmbeddingSourceUserStatus,
+		SourceUUID:  userStatus.UUID.String(),
+		Content:     content,
+		CreatedAt:   userStatus.CreatedAt,
+	}})
+}
+
+// recalledSnippets 검색된 지난 메시지 중 이미 프롬프트에 들어가는 최근 대화를 제외하고 프롬프트용 문장으로 변환
+func recalledSnippets(matches []recall.Match, history []models.ChatMessage) []string {
+	inHistory := make(map[string]struct{}, len(history))
+	for _, msg := range history {
+		inHistory[msg.UUID.String()] = struct{}{}
+	}
+
+	var snippets []string
+	for _, match := range matches {
+		if _, ok := inHistory[match.SourceUUID.String()]; ok {
+			continue
+		}
+		snippets = append(snippets, fmt.Sprintf("[%s] %s", match.CreatedAt.Format("2006-01-02"), match.Content))
+	}
+	return snippets
+}
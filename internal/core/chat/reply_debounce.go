@@ -0,0 +1,194 @@
+package chat
+
+import (
+	"sync"
+	"time"
+
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/models"
+)
+
+// ReplyPolicy 봇 응답 대기(debounce) 정책
+// 마지막 메시지 후 BaseDelay만큼 기다리고, 입력 중 이벤트를 받으면 TypingExtension만큼 연장하되
+// 첫 메시지부터 MaxWait을 넘기지 않는다.
+type ReplyPolicy struct {
+	BaseDelay       time.Duration
+	TypingExtension time.Duration
+	MaxWait         time.Duration
+}
+
+// DefaultReplyPolicy 채팅봇별 설정이 없을 때 사용하는 기본 정책 (서버 시작 시 설정값으로 교체)
+var DefaultReplyPolicy = ReplyPolicy{
+	BaseDelay:       4 * time.Second,
+	TypingExtension: 5 * time.Second,
+	MaxWait:         15 * time.Second,
+}
+
+// ForChatbot 채팅봇별 설정으로 기본 정책 덮어쓰기
+func (p ReplyPolicy) ForChatbot(chatbot *models.Chatbot) ReplyPolicy {
+	if chatbot == nil {
+		return p
+	}
+	if chatbot.ReplyDelayMs != nil && *chatbot.ReplyDelayMs > 0 {
+		p.BaseDelay = time.Duration(*chatbot.ReplyDelayMs) * time.Millisecond
+	}
+	if chatbot.TypingExtensionMs != nil && *chatbot.TypingExtensionMs >= 0 {
+		p.TypingExtension = time.Duration(*chatbot.TypingExtensionMs) * time.Millisecond
+	}
+	if chatbot.MaxReplyWaitMs != nil && *chatbot.MaxReplyWaitMs > 0 {
+		p.MaxWait = time.Duration(*chatbot.MaxReplyWaitMs) * time.Millisecond
+	}
+	if p.MaxWait < p.BaseDelay {
+		p.MaxWait = p.BaseDelay
+	}
+	return p
+}
+
+// Clock 현재 시각과 타이머 추상화 (테스트에서 가짜 시계로 교체)
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer Clock.AfterFunc가 반환하는 타이머
+type Timer interface {
+	Stop() bool
+}
+
+// realClock 실제 시계
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// ReplyDebouncer 사용자 메시지를 모아 정책에 따라 한 번에 응답하도록 타이머 관리
+// 예정 시각이 되거나 Flush가 호출되면 fire를 호출한다 (타이머 고루틴에서 호출될 수 있음).
+type ReplyDebouncer struct {
+	policy ReplyPolicy
+	clock  Clock
+	fire   func()
+
+	mu    sync.Mutex
+	timer Timer
+	state middleware.ReplyState
+}
+
+// NewReplyDebouncer 새로운 ReplyDebouncer 생성 (clock이 nil이면 실제 시계 사용)
+func NewReplyDebouncer(policy ReplyPolicy, clock Clock, fire func()) *ReplyDebouncer {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &ReplyDebouncer{
+		policy: policy,
+		clock:  clock,
+		fire:   fire,
+	}
+}
+
+// Policy 적용 중인 정책
+func (d *ReplyDebouncer) Policy() ReplyPolicy {
+	return d.policy
+}
+
+// Message 사용자 메시지 수신: 기본 대기 시간 후로 응답 예정 시각을 다시 잡는다
+func (d *ReplyDebouncer) Message() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	if !d.state.Pending {
+		d.state.Pending = true
+		d.state.FirstMessageAt = now
+	}
+	d.state.Typing = false
+	d.scheduleLocked(now.Add(d.policy.BaseDelay))
+}
+
+// Typing 입력 중 이벤트 수신: 대기 중인 메시지가 있으면 응답 예정 시각을 연장한다
+// 이미 더 늦은 예정 시각이 잡혀 있으면 당기지 않는다.
+func (d *ReplyDebouncer) Typing() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.state.Pending {
+		return
+	}
+
+	dueAt := d.clock.Now().Add(d.policy.TypingExtension)
+	if !dueAt.After(d.state.DueAt) {
+		return
+	}
+	d.state.Typing = true
+	d.scheduleLocked(dueAt)
+}
+
+// Flush 대기 중인 메시지가 있으면 즉시 응답
+func (d *ReplyDebouncer) Flush() {
+	d.mu.Lock()
+	if !d.state.Pending {
+		d.mu.Unlock()
+		return
+	}
+	d.resetLocked()
+	d.mu.Unlock()
+
+	d.fire()
+}
+
+// Stop 타이머를 취소하고 대기 상태 초기화 (세션 종료 시)
+func (d *ReplyDebouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.resetLocked()
+}
+
+// State 현재 대기 상태
+func (d *ReplyDebouncer) State() middleware.ReplyState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.state
+}
+
+// scheduleLocked 최대 대기 시간을 넘지 않도록 예정 시각을 잡고 타이머 재설정 (d.mu를 잡은 상태에서 호출)
+func (d *ReplyDebouncer) scheduleLocked(dueAt time.Time) {
+	if limit := d.state.FirstMessageAt.Add(d.policy.MaxWait); dueAt.After(limit) {
+		dueAt = limit
+	}
+	d.state.DueAt = dueAt
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	delay := dueAt.Sub(d.clock.Now())
+	if delay < 0 {
+		delay = 0
+	}
+	d.timer = d.clock.AfterFunc(delay, d.expire)
+}
+
+// expire 타이머 만료: 예정 시각이 지났으면 응답
+func (d *ReplyDebouncer) expire() {
+	d.mu.Lock()
+	// 만료 직전에 재설정된 타이머가 아닌지 확인
+	if !d.state.Pending || d.clock.Now().Before(d.state.DueAt) {
+		d.mu.Unlock()
+		return
+	}
+	d.resetLocked()
+	d.mu.Unlock()
+
+	d.fire()
+}
+
+// resetLocked 타이머 취소 및 상태 초기화 (d.mu를 잡은 상태에서 호출)
+func (d *ReplyDebouncer) resetLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.state = middleware.ReplyState{}
+}
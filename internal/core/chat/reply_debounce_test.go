@@ -0,0 +1,226 @@
+package chat
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+)
+
+// fakeClock 수동으로 시간을 진행시키는 테스트용 시계
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock   *fakeClock
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := !t.stopped
+	t.stopped = true
+	return wasActive
+}
+
+// Advance 시간을 d만큼 진행시키고 만료된 타이머 실행
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []*fakeTimer
+	for _, timer := range c.timers {
+		if !timer.stopped && !timer.at.After(c.now) {
+			timer.stopped = true
+			due = append(due, timer)
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+var testPolicy = ReplyPolicy{
+	BaseDelay:       4 * time.Second,
+	TypingExtension: 5 * time.Second,
+	MaxWait:         15 * time.Second,
+}
+
+func newTestDebouncer(policy ReplyPolicy) (*ReplyDebouncer, *fakeClock, *int) {
+	clock := newFakeClock()
+	fired := 0
+	return NewReplyDebouncer(policy, clock, func() { fired++ }), clock, &fired
+}
+
+func TestDebouncerFiresAfterBaseDelay(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Message()
+	clock.Advance(3 * time.Second)
+	if *fired != 0 {
+		t.Fatal("fired before base delay")
+	}
+
+	// 새 메시지가 오면 기본 대기 시간부터 다시 기다림
+	d.Message()
+	clock.Advance(3 * time.Second)
+	if *fired != 0 {
+		t.Fatal("second message did not reset the delay")
+	}
+
+	clock.Advance(time.Second)
+	if *fired != 1 {
+		t.Fatalf("expected one reply, got %d", *fired)
+	}
+	if d.State().Pending {
+		t.Error("state still pending after reply")
+	}
+}
+
+func TestDebouncerTypingExtendsDelay(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Message()
+	clock.Advance(3 * time.Second)
+	d.Typing()
+
+	state := d.State()
+	if !state.Typing || !state.DueAt.Equal(clock.Now().Add(5*time.Second)) {
+		t.Fatalf("unexpected state after typing: %+v", state)
+	}
+
+	clock.Advance(4 * time.Second)
+	if *fired != 0 {
+		t.Fatal("fired before typing extension elapsed")
+	}
+	clock.Advance(time.Second)
+	if *fired != 1 {
+		t.Fatalf("expected one reply, got %d", *fired)
+	}
+}
+
+func TestDebouncerTypingWithoutPendingMessageIsIgnored(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Typing()
+	if d.State().Pending {
+		t.Fatal("typing alone must not schedule a reply")
+	}
+	clock.Advance(time.Minute)
+	if *fired != 0 {
+		t.Fatal("fired without any message")
+	}
+}
+
+func TestDebouncerCapsAtMaxWait(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Message()
+	for i := 0; i < 10; i++ {
+		clock.Advance(2 * time.Second)
+		if *fired != 0 {
+			break
+		}
+		d.Typing()
+	}
+
+	if *fired != 1 {
+		t.Fatalf("expected reply once max wait was reached, got %d", *fired)
+	}
+}
+
+func TestDebouncerMaxWaitAppliesToDueAt(t *testing.T) {
+	d, clock, _ := newTestDebouncer(testPolicy)
+
+	d.Message()
+	for i := 0; i < 4; i++ {
+		clock.Advance(3 * time.Second)
+		d.Typing()
+	}
+
+	// 12초 시점의 입력 중 이벤트는 17초까지 연장하려 하지만 첫 메시지 + 15초로 제한됨
+	want := clock.Now().Add(3 * time.Second)
+	if got := d.State().DueAt; !got.Equal(want) {
+		t.Errorf("expected due at %s (first message + max wait), got %s", want, got)
+	}
+}
+
+func TestDebouncerFlushRepliesImmediately(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Flush()
+	if *fired != 0 {
+		t.Fatal("flush without pending message must not reply")
+	}
+
+	d.Message()
+	d.Flush()
+	if *fired != 1 {
+		t.Fatalf("expected immediate reply, got %d", *fired)
+	}
+
+	// 취소된 타이머가 다시 응답하지 않아야 함
+	clock.Advance(time.Minute)
+	if *fired != 1 {
+		t.Fatalf("stale timer fired again, got %d", *fired)
+	}
+}
+
+func TestDebouncerStopCancelsPendingReply(t *testing.T) {
+	d, clock, fired := newTestDebouncer(testPolicy)
+
+	d.Message()
+	d.Stop()
+	clock.Advance(time.Minute)
+	if *fired != 0 {
+		t.Fatal("fired after stop")
+	}
+}
+
+func TestReplyPolicyForChatbot(t *testing.T) {
+	delay, extension, maxWait := 1000, 0, 500
+	policy := testPolicy.ForChatbot(&models.Chatbot{
+		ReplyDelayMs:      &delay,
+		TypingExtensionMs: &extension,
+		MaxReplyWaitMs:    &maxWait,
+	})
+
+	if policy.BaseDelay != time.Second || policy.TypingExtension != 0 {
+		t.Errorf("overrides not applied: %+v", policy)
+	}
+	if policy.MaxWait != time.Second {
+		t.Errorf("max wait must not be below base delay, got %s", policy.MaxWait)
+	}
+
+	if got := testPolicy.ForChatbot(&models.Chatbot{}); got != testPolicy {
+		t.Errorf("chatbot without overrides changed policy: %+v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/status"
+	"sermo-be/internal/core/usage"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/firebase"
+	"sermo-be/pkg/llm"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// UsageTracker 언어 모델 사용량 기록과 사용 한도 확인 (usage.UsageService가 구현)
+type UsageTracker interface {
+	RecordChat(userUUID, feature string, model llm.LLM, response *llm.ChatResponse)
+	RecordEmbedding(userUUID string, response *llm.EmbeddingResponse)
+	CheckQuota(userUUID string, now time.Time, resources ...usage.Resource) error
+}
+
+// PushSender 사용자 기기로 푸시 알림 전송 (FCMSender가 구현)
+type PushSender interface {
+	SendToUser(ctx context.Context, userUUID string, notification *firebase.ChatNotification) error
+}
+
+// Services 채팅 파이프라인 서비스 묶음
+// main에서 저장소와 외부 의존성을 주입해 한 번 만들고, 핸들러는 GetServices로 요청 컨텍스트에서 꺼내 쓴다.
+type Services struct {
+	Messages *MessageService
+	Memory   *MemoryService
+	Bot      *BotGoroutine
+	Alarms   *AlarmMessageGenerator
+
+	push PushSender
+}
+
+// NewServices 새로운 Services 생성
+// db는 대화 목록, 히스토리 검색처럼 저장소로 옮기지 않은 조회에만 쓰이며, push가 nil이면 알람을 저장만 하고 보내지 않는다.
+func NewServices(repos *repository.Repositories, db *gorm.DB, recaller *recall.Service, tracker UsageTracker, push PushSender) *Services {
+	messages := NewMessageService(repos.Messages, db)
+	memory := NewMemoryService(repos, tracker)
+
+	return &Services{
+		Messages: messages,
+		Memory:   memory,
+		Bot: NewBotGoroutine(
+			messages,
+			NewAnswerGenerator(messages, memory, repos, recaller, tracker),
+			NewStatusGenerator(status.NewStatusService(repos.Statuses), recaller, tracker),
+			repos.Chatbots,
+			tracker,
+		),
+		Alarms: NewAlarmMessageGenerator(repos, tracker),
+		push:   push,
+	}
+}
+
+// ServicesMiddleware 채팅 서비스를 요청 컨텍스트에 주입하는 미들웨어
+func ServicesMiddleware(services *Services) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("chat_services", services)
+		return c.Next()
+	}
+}
+
+// GetServices 요청 컨텍스트에서 채팅 서비스 가져오기
+func GetServices(c *fiber.Ctx) *Services {
+	if services, ok := c.Locals("chat_services").(*Services); ok {
+		return services
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/usage"
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/models"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/llmtest"
+)
+
+// fakeUsage 사용량을 메모리에 기록하고 quotaErr를 한도 확인 결과로 돌려주는 UsageTracker
+type fakeUsage struct {
+	mu       sync.Mutex
+	features []string
+	quotaErr error
+}
+
+func (u *fakeUsage) RecordChat(userUUID, feature string, model llm.LLM, response *llm.ChatResponse) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.features = append(u.features, feature)
+}
+
+func (u *fakeUsage) RecordEmbedding(userUUID string, response *llm.EmbeddingResponse) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.features = append(u.features, usage.FeatureEmbedding)
+}
+
+func (u *fakeUsage) CheckQuota(userUUID string, now time.Time, resources ...usage.Resource) error {
+	return u.quotaErr
+}
+
+func (u *fakeUsage) recorded() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]string(nil), u.features...)
+}
+
+// newTestServices 메모리 저장소와 가짜 사용량 기록으로 만든 채팅 서비스
+func newTestServices(t *testing.T) (*Services, *repository.Repositories, *fakeUsage) {
+	t.Helper()
+
+	repos := repository.NewMemoryRepositories()
+	tracker := &fakeUsage{}
+	return NewServices(repos, nil, recall.NewService(recall.NewMemoryStore()), tracker, nil), repos, tracker
+}
+
+// readBotEvent 세션 채널에서 eventType 이벤트가 올 때까지 대기 (다른 이벤트는 건너뜀)
+func readBotEvent(t *testing.T, session *middleware.SSESession, eventType string) BotMessage {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case frame := <-session.Channel:
+			var message BotMessage
+			data := strings.TrimSuffix(strings.TrimPrefix(frame, "data: "), "\n\n")
+			if err := json.Unmarshal([]byte(data), &message); err != nil {
+				t.Fatalf("unmarshal %q: %v", frame, err)
+			}
+			if message.Type == eventType {
+				return message
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", eventType)
+			return BotMessage{}
+		}
+	}
+}
+
+func TestBotGoroutineRepliesWithInjectedServices(t *testing.T) {
+	services, repos, tracker := newTestServices(t)
+	sm := middleware.NewSSEManager(middleware.DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	chatbot := models.NewChatbot("Luna", "", json.RawMessage(`[]`), "female", "A cheerful friend.", "user-1")
+	if err := repos.Chatbots.Create(chatbot); err != nil {
+		t.Fatalf("Create chatbot: %v", err)
+	}
+	session, err := sm.CreateSession("user-1", chatbot.UUID.String())
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	// 초기 응답 → 검증 순서로 응답
+	model := llmtest.New("Hi! How was your day?", "Hey! How was your day today?")
+	router := llm.NewRouter().Route(llm.FeatureChat, model)
+	services.Bot.StartBotGoroutine(session, router)
+
+	if _, err := services.Messages.CreateUserMessage(session.SessionID, "user-1", session.ChatbotUUID, "hello"); err != nil {
+		t.Fatalf("CreateUserMessage: %v", err)
+	}
+	session.BotChannel <- `data: {"type":"user","content":"hello","session_id":"` + session.SessionID + `"}`
+	session.BotChannel <- `data: {"type":"flush","session_id":"` + session.SessionID + `"}`
+
+	reply := readBotEvent(t, session, "bot")
+	if reply.Content != "Hey! How was your day today?" {
+		t.Errorf("reply = %q", reply.Content)
+	}
+
+	history, _ := services.Messages.GetChatHistory("user-1", session.ChatbotUUID, 0)
+	if len(history) != 2 || history[1].MessageType != models.MessageTypeChatbot || history[1].Content != reply.Content {
+		t.Errorf("history = %+v", history)
+	}
+	if got := strings.Join(tracker.recorded(), ","); got != usage.FeatureAnswer+","+usage.FeatureValidation {
+		t.Errorf("recorded usage = %s", got)
+	}
+}
+
+func TestBotGoroutineRecordsEmbeddingUsage(t *testing.T) {
+	services, repos, tracker := newTestServices(t)
+	sm := middleware.NewSSEManager(middleware.DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	chatbot := models.NewChatbot("Luna", "", json.RawMessage(`[]`), "female", "A cheerful friend.", "user-1")
+	if err := repos.Chatbots.Create(chatbot); err != nil {
+		t.Fatalf("Create chatbot: %v", err)
+	}
+	session, err := sm.CreateSession("user-1", chatbot.UUID.String())
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	router := llm.NewRouter().
+		Route(llm.FeatureChat, llmtest.New("Good luck!", "Good luck tomorrow!")).
+		Route(llm.FeatureEmbedding, &llmtest.Fake{})
+	services.Bot.StartBotGoroutine(session, router)
+
+	const content = "I have a math exam tomorrow"
+	if _, err := services.Messages.CreateUserMessage(session.SessionID, "user-1", session.ChatbotUUID, content); err != nil {
+		t.Fatalf("CreateUserMessage: %v", err)
+	}
+	session.BotChannel <- `data: {"type":"user","content":"` + content + `","session_id":"` + session.SessionID + `"}`
+	session.BotChannel <- `data: {"type":"flush","session_id":"` + session.SessionID + `"}`
+	readBotEvent(t, session, "bot")
+
+	// 응답 전 지난 대화 검색과 응답 후 메시지 저장(백그라운드) 모두 임베딩 사용량으로 기록
+	want := strings.Join([]string{usage.FeatureEmbedding, usage.FeatureAnswer, usage.FeatureValidation, usage.FeatureEmbedding}, ",")
+	deadline := time.Now().Add(2 * time.Second)
+	for strings.Join(tracker.recorded(), ",") != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("recorded usage = %v, want %s", tracker.recorded(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBotGoroutineReportsQuotaExceeded(t *testing.T) {
+	services, _, tracker := newTestServices(t)
+	tracker.quotaErr = &usage.QuotaExceededError{}
+	session := middleware.NewSSESession("user-1", "bot-1")
+
+	services.Bot.generateAIResponse(session.Context(), session, []string{"hello"}, nil)
+
+	select {
+	case frame := <-session.Channel:
+		if !strings.Contains(frame, `"type":"bot_error"`) || !strings.Contains(frame, `"code":"`+botErrorQuotaExceeded+`"`) {
+			t.Errorf("frame = %q", frame)
+		}
+	default:
+		t.Fatal("expected a bot_error event")
+	}
+}
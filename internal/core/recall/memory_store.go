@@ -0,0 +1,70 @@
+package recall
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"sermo-be/internal/models"
+)
+
+// MemoryStore 프로세스 메모리 저장소 (pgvector 없이 테스트할 때 사용)
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]models.MemoryEmbedding // source_type:source_uuid → 임베딩
+}
+
+// NewMemoryStore 새로운 MemoryStore 생성
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[string]models.MemoryEmbedding)}
+}
+
+// Save 임베딩 저장 (원본별로 하나만 유지)
+func (s *MemoryStore) Save(ctx context.Context, items []models.MemoryEmbedding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range items {
+		s.items[item.SourceType+":"+item.SourceUUID.String()] = item
+	}
+	return nil
+}
+
+// Search 코사인 유사도 순으로 조회
+func (s *MemoryStore) Search(ctx context.Context, userUUID, chatbotUUID string, query []float32, k int) ([]Match, error) {
+	s.mu.RLock()
+	var matches []Match
+	for _, item := range s.items {
+		if item.UserUUID == userUUID && item.ChatbotUUID == chatbotUUID {
+			matches = append(matches, Match{MemoryEmbedding: item, Score: cosineSimilarity(query, item.Embedding)})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// cosineSimilarity 두 벡터의 코사인 유사도 (차원이 다르거나 영벡터면 0)
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,88 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+
+	"sermo-be/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PgStore Postgres pgvector 저장소
+type PgStore struct {
+	db *gorm.DB
+}
+
+// NewPgStore 새로운 PgStore 생성
+func NewPgStore(db *gorm.DB) *PgStore {
+	return &PgStore{db: db}
+}
+
+// Save 임베딩 저장 (원본별로 하나만 유지)
+func (s *PgStore) Save(ctx context.Context, items []models.MemoryEmbedding) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source_type"}, {Name: "source_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "embedding"}),
+	}).Create(&items).Error
+	if err != nil {
+		return fmt.Errorf("failed to save memory embeddings: %w", err)
+	}
+	return nil
+}
+
+// Search 코사인 거리(<=>) 순으로 조회
+// 대화 인덱스로 거른 임베딩만 거리를 계산해 정렬하므로 다른 대화의 임베딩 수와 관계없이 k개까지 돌려준다.
+func (s *PgStore) Search(ctx context.Context, userUUID, chatbotUUID string, query []float32, k int) ([]Match, error) {
+	var rows []struct {
+		models.MemoryEmbedding
+		Score float64
+	}
+
+	vector := models.Vector(query)
+	err := s.db.WithContext(ctx).Model(&models.MemoryEmbedding{}).
+		Select("uuid, user_uuid, chatbot_uuid, source_type, source_uuid, content, created_at, 1 - (embedding <=> ?) AS score", vector).
+		Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "embedding <=> ?", Vars: []interface{}{vector}}}).
+		Limit(k).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memory embeddings: %w", err)
+	}
+
+	matches := make([]Match, len(rows))
+	for i, row := range rows {
+		matches[i] = Match{MemoryEmbedding: row.MemoryEmbedding, Score: row.Score}
+	}
+	return matches, nil
+}
+
+// Dimensions memory_embeddings.embedding 컬럼에 선언된 벡터 차원 조회 (pgvector는 차원을 typmod에 저장)
+func (s *PgStore) Dimensions(ctx context.Context) (int, error) {
+	var dimensions []int
+	err := s.db.WithContext(ctx).Raw(`SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = to_regclass('memory_embeddings') AND attname = 'embedding' AND NOT attisdropped`).
+		Scan(&dimensions).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedding column dimensions: %w", err)
+	}
+	if len(dimensions) == 0 || dimensions[0] <= 0 {
+		return 0, fmt.Errorf("memory_embeddings.embedding column not found (run migrations first)")
+	}
+	return dimensions[0], nil
+}
+
+// parseUUID 원본 UUID 파싱
+func parseUUID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid source uuid %q: %w", value, err)
+	}
+	return id, nil
+}
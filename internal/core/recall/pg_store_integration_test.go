@@ -0,0 +1,75 @@
+//go:build integration
+
+package recall_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/models"
+	"sermo-be/internal/testutil"
+
+	"github.com/google/uuid"
+)
+
+// unitVector i번째 축 방향의 1536차원 벡터 (나머지 축에 noise를 섞음)
+func unitVector(i int, noise float32) models.Vector {
+	v := make(models.Vector, 1536)
+	for j := range v {
+		v[j] = noise
+	}
+	v[i] = 1
+	return v
+}
+
+func TestPgStoreSearchFiltersBeforeRanking(t *testing.T) {
+	store := recall.NewPgStore(testutil.NewPostgres(t))
+	ctx := context.Background()
+	const user, chatbot = "00000000-0000-4000-8000-000000000001", "00000000-0000-4000-8000-0000000000b1"
+
+	embedding := func(userUUID string, v models.Vector) models.MemoryEmbedding {
+		return models.MemoryEmbedding{
+			UUID:        uuid.New(),
+			UserUUID:    userUUID,
+			ChatbotUUID: chatbot,
+			SourceType:  models.EmbeddingSourceMessage,
+			SourceUUID:  uuid.New(),
+			Content:     "memory",
+			Embedding:   v,
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	// 다른 사용자의 임베딩이 질의와 더 가까워도 대화의 임베딩은 모두 찾아야 함
+	var items []models.MemoryEmbedding
+	for i := 0; i < 500; i++ {
+		items = append(items, embedding(uuid.NewString(), unitVector(0, 0.001)))
+	}
+	ours := []models.MemoryEmbedding{
+		embedding(user, unitVector(0, 0.5)),
+		embedding(user, unitVector(1, 0.1)),
+		embedding(user, unitVector(2, 0.1)),
+	}
+	items = append(items, ours...)
+	if err := store.Save(ctx, items); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	matches, err := store.Search(ctx, user, chatbot, unitVector(0, 0), 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != len(ours) {
+		t.Fatalf("matches = %d, want all %d embeddings of the conversation", len(matches), len(ours))
+	}
+	if matches[0].UUID != ours[0].UUID {
+		t.Errorf("closest match = %s, want %s", matches[0].UUID, ours[0].UUID)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Errorf("matches not ordered by score: %v > %v", matches[i].Score, matches[i-1].Score)
+		}
+	}
+}
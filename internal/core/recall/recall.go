@@ -0,0 +1,167 @@
+package recall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/pkg/llm"
+
+	"github.com/google/uuid"
+)
+
+// ErrDimensionMismatch 임베딩 모델이 반환하는 벡터 차원이 저장소 컬럼 차원과 다름
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+// Embedder 텍스트를 임베딩 벡터로 변환 (llm.LLM 구현체가 만족)
+type Embedder interface {
+	Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error)
+}
+
+// UsageRecorder 임베딩 토큰 사용량 기록 (usage.UsageService가 구현)
+type UsageRecorder interface {
+	RecordEmbedding(userUUID string, response *llm.EmbeddingResponse)
+}
+
+// trackedEmbedder 성공한 임베딩 호출의 사용량을 기록하는 Embedder
+type trackedEmbedder struct {
+	embedder Embedder
+	recorder UsageRecorder
+	userUUID string
+}
+
+// Tracked 임베딩할 때마다 사용량을 userUUID로 기록하는 Embedder (embedder가 nil이면 nil을 반환해 검색하지 않음을 유지)
+func Tracked(embedder Embedder, recorder UsageRecorder, userUUID string) Embedder {
+	if embedder == nil || recorder == nil {
+		return embedder
+	}
+	return trackedEmbedder{embedder: embedder, recorder: recorder, userUUID: userUUID}
+}
+
+// Embed 임베딩 후 사용량 기록
+func (e trackedEmbedder) Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error) {
+	response, err := e.embedder.Embed(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	e.recorder.RecordEmbedding(e.userUUID, response)
+	return response, nil
+}
+
+// Store 임베딩 저장소
+type Store interface {
+	// Save 임베딩 저장 (같은 원본이 이미 있으면 내용과 벡터를 갱신)
+	Save(ctx context.Context, items []models.MemoryEmbedding) error
+	// Search 사용자-채팅봇 대화에서 query와 가까운 순으로 최대 k개 조회
+	Search(ctx context.Context, userUUID, chatbotUUID string, query []float32, k int) ([]Match, error)
+}
+
+// Match 검색 결과 (Score는 코사인 유사도, 1에 가까울수록 관련 있음)
+type Match struct {
+	models.MemoryEmbedding
+	Score float64
+}
+
+// Item 임베딩할 원본
+type Item struct {
+	UserUUID    string
+	ChatbotUUID string
+	SourceType  string
+	SourceUUID  string
+	Content     string
+	CreatedAt   time.Time
+}
+
+// Service 사용자 메시지/상태 정보를 임베딩해 저장하고 현재 메시지와 관련된 내용을 찾는 서비스
+type Service struct {
+	store Store
+}
+
+// NewService 새로운 Service 인스턴스 생성
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// Index 원본을 한 번에 임베딩해 저장
+func (s *Service) Index(ctx context.Context, embedder Embedder, items []Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	inputs := make([]string, len(items))
+	for i, item := range items {
+		inputs[i] = item.Content
+	}
+
+	response, err := embedder.Embed(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to embed memories: %w", err)
+	}
+	vectors := response.Vectors
+	if len(vectors) != len(items) {
+		return fmt.Errorf("embedding returned %d vectors, want %d", len(vectors), len(items))
+	}
+
+	embeddings := make([]models.MemoryEmbedding, len(items))
+	for i, item := range items {
+		sourceUUID, err := parseUUID(item.SourceUUID)
+		if err != nil {
+			return err
+		}
+		embeddings[i] = models.MemoryEmbedding{
+			UUID:        uuid.New(),
+			UserUUID:    item.UserUUID,
+			ChatbotUUID: item.ChatbotUUID,
+			SourceType:  item.SourceType,
+			SourceUUID:  sourceUUID,
+			Content:     item.Content,
+			Embedding:   vectors[i],
+			CreatedAt:   item.CreatedAt,
+		}
+	}
+
+	return s.store.Save(ctx, embeddings)
+}
+
+// Recall query와 관련된 지난 내용을 minScore 이상인 것만 가까운 순으로 최대 k개 조회
+func (s *Service) Recall(ctx context.Context, embedder Embedder, userUUID, chatbotUUID, query string, k int, minScore float64) ([]Match, error) {
+	response, err := embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(response.Vectors) != 1 {
+		return nil, fmt.Errorf("embedding returned %d vectors, want 1", len(response.Vectors))
+	}
+
+	matches, err := s.store.Search(ctx, userUUID, chatbotUUID, response.Vectors[0], k)
+	if err != nil {
+		return nil, err
+	}
+
+	relevant := matches[:0]
+	for _, match := range matches {
+		if match.Score >= minScore {
+			relevant = append(relevant, match)
+		}
+	}
+	return relevant, nil
+}
+
+// CheckDimensions 임베딩 모델이 want 차원 벡터를 반환하는지 확인 (서버 시작 시 한 번 호출)
+// 차원이 다르면 저장과 검색이 모두 실패하므로 설정 실수를 기동 시점에 드러낸다.
+func CheckDimensions(ctx context.Context, embedder Embedder, want int) error {
+	response, err := embedder.Embed(ctx, []string{"dimension check"})
+	if err != nil {
+		return fmt.Errorf("failed to embed dimension probe: %w", err)
+	}
+	vectors := response.Vectors
+	if len(vectors) != 1 {
+		return fmt.Errorf("embedding probe returned %d vectors, want 1", len(vectors))
+	}
+	if got := len(vectors[0]); got != want {
+		return fmt.Errorf("%w: model returns %d dimensions, store expects %d", ErrDimensionMismatch, got, want)
+	}
+	return nil
+}
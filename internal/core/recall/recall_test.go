@@ -0,0 +1,185 @@
+package recall
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/pkg/llm"
+
+	"github.com/google/uuid"
+)
+
+// keywordEmbedder 단어 포함 여부로 벡터를 만드는 가짜 임베더
+type keywordEmbedder struct {
+	keywords []string
+	err      error
+}
+
+func (e keywordEmbedder) Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		vector := make([]float32, len(e.keywords)+1)
+		vector[len(e.keywords)] = 0.1 // 영벡터 방지
+		for j, keyword := range e.keywords {
+			if strings.Contains(input, keyword) {
+				vector[j] = 1
+			}
+		}
+		vectors[i] = vector
+	}
+	return &llm.EmbeddingResponse{
+		Vectors: vectors,
+		Usage:   llm.Usage{PromptTokens: len(inputs), TotalTokens: len(inputs)},
+		Model:   "keyword",
+	}, nil
+}
+
+func item(userUUID, sourceType, content string) Item {
+	return Item{
+		UserUUID:    userUUID,
+		ChatbotUUID: "bot",
+		SourceType:  sourceType,
+		SourceUUID:  uuid.NewString(),
+		Content:     content,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestRecallReturnsRelevantMemoriesInOrder(t *testing.T) {
+	service := NewService(NewMemoryStore())
+	embedder := keywordEmbedder{keywords: []string{"exam", "math", "pizza"}}
+	ctx := context.Background()
+
+	err := service.Index(ctx, embedder, []Item{
+		item("user", models.EmbeddingSourceMessage, "I have a math exam tomorrow"),
+		item("user", models.EmbeddingSourceUserStatus, "exam: studying all week"),
+		item("user", models.EmbeddingSourceMessage, "I ate pizza"),
+		item("other", models.EmbeddingSourceMessage, "my math exam was hard"),
+	})
+	if err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+
+	matches, err := service.Recall(ctx, embedder, "user", "bot", "how did the math exam go?", 5, 0.5)
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("관련된 기억 수 = %d, 기대값 2: %+v", len(matches), matches)
+	}
+	if matches[0].Content != "I have a math exam tomorrow" || matches[0].Score < matches[1].Score {
+		t.Fatalf("가장 관련 있는 기억이 먼저 오지 않음: %+v", matches)
+	}
+	for _, match := range matches {
+		if match.UserUUID != "user" {
+			t.Fatalf("다른 사용자의 기억이 조회됨: %+v", match)
+		}
+	}
+}
+
+func TestIndexReplacesSameSource(t *testing.T) {
+	store := NewMemoryStore()
+	service := NewService(store)
+	embedder := keywordEmbedder{keywords: []string{"exam"}}
+	ctx := context.Background()
+
+	original := item("user", models.EmbeddingSourceUserStatus, "exam")
+	updated := original
+	updated.Content = "exam postponed"
+
+	if err := service.Index(ctx, embedder, []Item{original}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := service.Index(ctx, embedder, []Item{updated}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+
+	matches, _ := store.Search(ctx, "user", "bot", []float32{1, 0}, 10)
+	if len(matches) != 1 || matches[0].Content != "exam postponed" {
+		t.Fatalf("같은 원본은 하나만 남아야 함: %+v", matches)
+	}
+}
+
+func TestIndexAndRecallPropagateEmbedderErrors(t *testing.T) {
+	service := NewService(NewMemoryStore())
+	embedder := keywordEmbedder{err: errors.New("rate limited")}
+	ctx := context.Background()
+
+	if err := service.Index(ctx, embedder, []Item{item("user", models.EmbeddingSourceMessage, "hi there")}); err == nil {
+		t.Fatal("Index: 임베딩 실패가 반환되지 않음")
+	}
+	if _, err := service.Recall(ctx, embedder, "user", "bot", "hi", 5, 0); err == nil {
+		t.Fatal("Recall: 임베딩 실패가 반환되지 않음")
+	}
+	if err := service.Index(ctx, embedder, nil); err != nil {
+		t.Fatalf("빈 목록은 임베딩하지 않아야 함: %v", err)
+	}
+}
+
+func TestCheckDimensions(t *testing.T) {
+	embedder := keywordEmbedder{keywords: []string{"exam", "trip"}} // 3차원
+	ctx := context.Background()
+
+	if err := CheckDimensions(ctx, embedder, 3); err != nil {
+		t.Fatalf("차원이 같으면 통과해야 함: %v", err)
+	}
+	if err := CheckDimensions(ctx, embedder, 1536); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("차원이 다르면 ErrDimensionMismatch여야 함: %v", err)
+	}
+
+	err := CheckDimensions(ctx, keywordEmbedder{err: errors.New("rate limited")}, 3)
+	if err == nil || errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("임베딩 실패는 차원 불일치와 구분되어야 함: %v", err)
+	}
+}
+
+// usageLog 기록된 임베딩 사용량 (사용자별)
+type usageLog struct {
+	users  []string
+	tokens int
+}
+
+func (u *usageLog) RecordEmbedding(userUUID string, response *llm.EmbeddingResponse) {
+	u.users = append(u.users, userUUID)
+	u.tokens += response.Usage.TotalTokens
+}
+
+func TestTrackedRecordsEmbeddingUsage(t *testing.T) {
+	service := NewService(NewMemoryStore())
+	recorder := &usageLog{}
+	embedder := Tracked(keywordEmbedder{keywords: []string{"exam", "trip"}}, recorder, "user")
+	ctx := context.Background()
+
+	items := []Item{item("user", models.EmbeddingSourceMessage, "math exam"), item("user", models.EmbeddingSourceMessage, "trip to busan")}
+	if err := service.Index(ctx, embedder, items); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if _, err := service.Recall(ctx, embedder, "user", "bot", "exam", 5, 0); err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if err := CheckDimensions(ctx, Tracked(keywordEmbedder{keywords: []string{"exam", "trip"}}, recorder, "system"), 3); err != nil {
+		t.Fatalf("CheckDimensions: %v", err)
+	}
+	if got := strings.Join(recorder.users, ","); got != "user,user,system" || recorder.tokens != 4 {
+		t.Errorf("recorded = %s (%d tokens), want index, recall and probe", got, recorder.tokens)
+	}
+
+	// 실패한 호출과 임베딩 모델이 없는 경우는 기록하지 않음
+	failing := Tracked(keywordEmbedder{err: errors.New("rate limited")}, recorder, "user")
+	if _, err := service.Recall(ctx, failing, "user", "bot", "exam", 5, 0); err == nil {
+		t.Fatal("Recall: 임베딩 실패가 반환되지 않음")
+	}
+	if len(recorder.users) != 3 {
+		t.Errorf("failed call recorded: %v", recorder.users)
+	}
+	if Tracked(nil, recorder, "user") != nil {
+		t.Error("Tracked(nil) should stay nil so recall is skipped")
+	}
+}
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryRegistry 프로세스 내부 세션 레지스트리 (단일 인스턴스 배포용)
+type MemoryRegistry struct {
+	sessions map[string]Info   // sessionID → 세션 정보
+	owners   map[string]string // userUUID:chatbotUUID → sessionID
+	mutex    sync.RWMutex
+}
+
+// NewMemoryRegistry 새로운 메모리 레지스트리 생성
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		sessions: make(map[string]Info),
+		owners:   make(map[string]string),
+	}
+}
+
+// Claim 세션 등록
+func (r *MemoryRegistry) Claim(ctx context.Context, info Info) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := pairKey(info.UserUUID, info.ChatbotUUID)
+	if _, exists := r.owners[key]; exists {
+		return ErrSessionExists
+	}
+
+	r.owners[key] = info.SessionID
+	r.sessions[info.SessionID] = info
+	return nil
+}
+
+// Release 세션 등록 해제
+func (r *MemoryRegistry) Release(ctx context.Context, info Info) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := pairKey(info.UserUUID, info.ChatbotUUID)
+	if r.owners[key] == info.SessionID {
+		delete(r.owners, key)
+	}
+	delete(r.sessions, info.SessionID)
+	return nil
+}
+
+// Get 세션 ID로 조회
+func (r *MemoryRegistry) Get(ctx context.Context, sessionID string) (*Info, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	info, exists := r.sessions[sessionID]
+	if !exists {
+		return nil, ErrSessionNotFound
+	}
+	return &info, nil
+}
+
+// Find 사용자-채팅봇 쌍으로 조회
+func (r *MemoryRegistry) Find(ctx context.Context, userUUID, chatbotUUID string) (*Info, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	sessionID, exists := r.owners[pairKey(userUUID, chatbotUUID)]
+	if !exists {
+		return nil, ErrSessionNotFound
+	}
+
+	info := r.sessions[sessionID]
+	return &info, nil
+}
+
+// Refresh 메모리 레지스트리는 만료가 없으므로 아무것도 하지 않음
+func (r *MemoryRegistry) Refresh(ctx context.Context, infos []Info) error {
+	return nil
+}
+
+// CountUser 사용자의 세션 수
+func (r *MemoryRegistry) CountUser(ctx context.Context, userUUID string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, info := range r.sessions {
+		if info.UserUUID == userUUID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MemoryBus 프로세스 내부 메시지 버스
+// 같은 버스를 공유하는 SSEManager끼리 인스턴스 간 라우팅을 흉내 낼 수 있다 (테스트용).
+type MemoryBus struct {
+	handlers map[string]func(Envelope)
+	mutex    sync.RWMutex
+}
+
+// NewMemoryBus 새로운 메모리 버스 생성
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		handlers: make(map[string]func(Envelope)),
+	}
+}
+
+// Publish 구독 중인 인스턴스 핸들러를 동기적으로 호출
+func (b *MemoryBus) Publish(ctx context.Context, instanceID string, envelope Envelope) error {
+	b.mutex.RLock()
+	handler, exists := b.handlers[instanceID]
+	b.mutex.RUnlock()
+
+	if !exists {
+		return ErrNoSubscriber
+	}
+
+	handler(envelope)
+	return nil
+}
+
+// Subscribe 인스턴스 핸들러 등록 (ctx가 끝나면 해제)
+func (b *MemoryBus) Subscribe(ctx context.Context, instanceID string, handler func(Envelope)) error {
+	b.mutex.Lock()
+	b.handlers[instanceID] = handler
+	b.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mutex.Lock()
+		delete(b.handlers, instanceID)
+		b.mutex.Unlock()
+	}()
+
+	return nil
+}
+
+// Close 모든 구독 해제
+func (b *MemoryBus) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.handlers = make(map[string]func(Envelope))
+	return nil
+}
+
+func pairKey(userUUID, chatbotUUID string) string {
+	return userUUID + ":" + chatbotUUID
+}
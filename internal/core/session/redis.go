@@ -0,0 +1,244 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix     = "sermo:chat:"
+	redisChannelPrefix = "sermo:chat:instance:"
+
+	// DefaultRedisSessionTTL 갱신되지 않은 세션 등록이 만료되는 시간
+	// 소유 인스턴스가 비정상 종료되어도 이 시간이 지나면 같은 사용자가 다시 채팅을 시작할 수 있다.
+	DefaultRedisSessionTTL = 90 * time.Second
+)
+
+// releaseScript 소유자 키가 해당 세션을 가리킬 때만 삭제 (다른 세션이 이미 점유했으면 유지)
+// 세션 정보와 사용자별 세션 목록의 항목은 항상 삭제한다.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("DEL", KEYS[1])
+end
+redis.call("DEL", KEYS[2])
+redis.call("ZREM", KEYS[3], ARGV[1])
+return 1
+`)
+
+// RedisRegistry Redis 기반 세션 레지스트리 (다중 인스턴스 배포용)
+type RedisRegistry struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisRegistry 새로운 Redis 레지스트리 생성 (ttl이 0이면 기본값 사용)
+func NewRedisRegistry(client *redis.Client, ttl time.Duration) *RedisRegistry {
+	if ttl <= 0 {
+		ttl = DefaultRedisSessionTTL
+	}
+	return &RedisRegistry{client: client, ttl: ttl}
+}
+
+// Claim 소유자 키를 SET NX로 선점한 뒤 세션 정보 저장
+func (r *RedisRegistry) Claim(ctx context.Context, info Info) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session info: %v", err)
+	}
+
+	ownerKey := r.ownerKey(info.UserUUID, info.ChatbotUUID)
+	claimed, err := r.client.SetNX(ctx, ownerKey, info.SessionID, r.ttl).Result()
+	if err != nil {
+		return fmt.Errorf("failed to claim session: %v", err)
+	}
+	if !claimed {
+		return ErrSessionExists
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, r.sessionKey(info.SessionID), data, r.ttl)
+	r.addUserSession(ctx, pipe, info)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.client.Del(ctx, ownerKey)
+		return fmt.Errorf("failed to store session info: %v", err)
+	}
+	return nil
+}
+
+// Release 세션 등록 해제
+func (r *RedisRegistry) Release(ctx context.Context, info Info) error {
+	keys := []string{r.ownerKey(info.UserUUID, info.ChatbotUUID), r.sessionKey(info.SessionID), r.userKey(info.UserUUID)}
+	if err := releaseScript.Run(ctx, r.client, keys, info.SessionID).Err(); err != nil {
+		return fmt.Errorf("failed to release session: %v", err)
+	}
+	return nil
+}
+
+// Get 세션 ID로 조회
+func (r *RedisRegistry) Get(ctx context.Context, sessionID string) (*Info, error) {
+	data, err := r.client.Get(ctx, r.sessionKey(sessionID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %v", err)
+	}
+
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session info: %v", err)
+	}
+	return &info, nil
+}
+
+// Find 사용자-채팅봇 쌍으로 조회
+func (r *RedisRegistry) Find(ctx context.Context, userUUID, chatbotUUID string) (*Info, error) {
+	sessionID, err := r.client.Get(ctx, r.ownerKey(userUUID, chatbotUUID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %v", err)
+	}
+	return r.Get(ctx, sessionID)
+}
+
+// Refresh 세션 키들의 TTL 연장
+func (r *RedisRegistry) Refresh(ctx context.Context, infos []Info) error {
+	if len(infos) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for _, info := range infos {
+		pipe.Expire(ctx, r.ownerKey(info.UserUUID, info.ChatbotUUID), r.ttl)
+		pipe.Expire(ctx, r.sessionKey(info.SessionID), r.ttl)
+		r.addUserSession(ctx, pipe, info)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to refresh sessions: %v", err)
+	}
+	return nil
+}
+
+// CountUser 사용자별 세션 목록에서 만료되지 않은 세션 수 조회
+// 소유 인스턴스가 비정상 종료되어 해제되지 않은 세션은 갱신이 끊긴 뒤 ttl이 지나면 세지 않는다.
+func (r *RedisRegistry) CountUser(ctx context.Context, userUUID string) (int, error) {
+	key := r.userKey(userUUID)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", now)
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count user sessions: %v", err)
+	}
+	return int(count.Val()), nil
+}
+
+// addUserSession 사용자별 세션 목록에 만료 시각(점수)과 함께 세션 추가 또는 갱신
+func (r *RedisRegistry) addUserSession(ctx context.Context, pipe redis.Pipeliner, info Info) {
+	key := r.userKey(info.UserUUID)
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(time.Now().Add(r.ttl).UnixMilli()), Member: info.SessionID})
+	pipe.Expire(ctx, key, r.ttl)
+}
+
+func (r *RedisRegistry) ownerKey(userUUID, chatbotUUID string) string {
+	return redisKeyPrefix + "owner:" + pairKey(userUUID, chatbotUUID)
+}
+
+func (r *RedisRegistry) sessionKey(sessionID string) string {
+	return redisKeyPrefix + "session:" + sessionID
+}
+
+func (r *RedisRegistry) userKey(userUUID string) string {
+	return redisKeyPrefix + "user:" + userUUID
+}
+
+// RedisBus Redis pub/sub 기반 메시지 버스 (인스턴스마다 전용 채널 사용)
+// Redis 클라이언트는 레지스트리와 공유하므로 생성한 쪽(main)에서 닫는다.
+type RedisBus struct {
+	client        *redis.Client
+	subscriptions []*redis.PubSub
+	mutex         sync.Mutex
+}
+
+// NewRedisBus 새로운 Redis 버스 생성
+func NewRedisBus(client *redis.Client) *RedisBus {
+	return &RedisBus{client: client}
+}
+
+// Publish 인스턴스 채널로 메시지 발행
+func (b *RedisBus) Publish(ctx context.Context, instanceID string, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %v", err)
+	}
+
+	receivers, err := b.client.Publish(ctx, redisChannelPrefix+instanceID, data).Result()
+	if err != nil {
+		return fmt.Errorf("failed to publish envelope: %v", err)
+	}
+	if receivers == 0 {
+		return ErrNoSubscriber
+	}
+	return nil
+}
+
+// Subscribe 인스턴스 채널 구독 (구독 확인 후 반환하고 수신은 백그라운드에서 처리)
+func (b *RedisBus) Subscribe(ctx context.Context, instanceID string, handler func(Envelope)) error {
+	pubsub := b.client.Subscribe(ctx, redisChannelPrefix+instanceID)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe: %v", err)
+	}
+
+	b.mutex.Lock()
+	b.subscriptions = append(b.subscriptions, pubsub)
+	b.mutex.Unlock()
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var envelope Envelope
+				if err := json.Unmarshal([]byte(message.Payload), &envelope); err != nil {
+					log.Printf("세션 버스 메시지 파싱 실패: %v", err)
+					continue
+				}
+				handler(envelope)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close 모든 구독 해제
+func (b *RedisBus) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, pubsub := range b.subscriptions {
+		pubsub.Close()
+	}
+	b.subscriptions = nil
+	return nil
+}
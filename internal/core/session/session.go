@@ -0,0 +1,69 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrSessionExists 같은 사용자-채팅봇 쌍에 이미 활성 세션이 있음
+	ErrSessionExists = errors.New("active session already exists for this user and chatbot")
+	// ErrSessionNotFound 등록된 세션이 없음
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrNoSubscriber 세션을 소유한 인스턴스가 메시지를 수신하고 있지 않음
+	ErrNoSubscriber = errors.New("session owner is not reachable")
+)
+
+// Info 레지스트리에 저장되는 세션 정보
+// SSE 스트림을 가진 인스턴스(InstanceID)가 세션의 소유자다.
+type Info struct {
+	SessionID   string    `json:"session_id"`
+	UserUUID    string    `json:"user_uuid"`
+	ChatbotUUID string    `json:"chatbot_uuid"`
+	InstanceID  string    `json:"instance_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Kind 인스턴스 간 전달되는 메시지 종류
+type Kind string
+
+const (
+	KindMessage     Kind = "message"      // 세션 채널로 전달할 SSE 프레임
+	KindUserMessage Kind = "user_message" // 사용자 메시지 SSE 프레임 (세션 활동으로 기록)
+	KindStop        Kind = "stop"         // 세션 종료 요청
+)
+
+// Envelope 인스턴스 간 전달 단위
+type Envelope struct {
+	Kind      Kind   `json:"kind"`
+	SessionID string `json:"session_id"`
+	Data      string `json:"data,omitempty"`
+}
+
+// Registry 세션 소유권 저장소
+// 어느 인스턴스에서 요청을 받더라도 사용자-채팅봇 쌍으로 세션과 소유 인스턴스를 찾을 수 있게 한다.
+type Registry interface {
+	// Claim 사용자-채팅봇 쌍에 세션 등록 (이미 있으면 ErrSessionExists)
+	Claim(ctx context.Context, info Info) error
+	// Release 세션 등록 해제 (없으면 무시)
+	Release(ctx context.Context, info Info) error
+	// Get 세션 ID로 조회 (없으면 ErrSessionNotFound)
+	Get(ctx context.Context, sessionID string) (*Info, error)
+	// Find 사용자-채팅봇 쌍으로 조회 (없으면 ErrSessionNotFound)
+	Find(ctx context.Context, userUUID, chatbotUUID string) (*Info, error)
+	// Refresh 소유 중인 세션의 만료 시간 연장 (인스턴스가 죽으면 등록이 자연히 만료되도록)
+	Refresh(ctx context.Context, infos []Info) error
+	// CountUser 사용자가 모든 인스턴스에 걸쳐 가진 세션 수 (사용자별 동시 세션 제한용)
+	CountUser(ctx context.Context, userUUID string) (int, error)
+}
+
+// Bus 인스턴스 간 메시지 버스
+type Bus interface {
+	// Publish 특정 인스턴스로 메시지 전송 (수신자가 없으면 ErrNoSubscriber)
+	Publish(ctx context.Context, instanceID string, envelope Envelope) error
+	// Subscribe 인스턴스 앞으로 온 메시지 수신 시작 (ctx가 끝나면 수신 중단)
+	Subscribe(ctx context.Context, instanceID string, handler func(Envelope)) error
+	// Close 버스 자원 정리
+	Close() error
+}
@@ -0,0 +1,169 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type backend struct {
+	registry Registry
+	bus      Bus
+}
+
+func backends(t *testing.T) map[string]backend {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]backend{
+		"memory": {registry: NewMemoryRegistry(), bus: NewMemoryBus()},
+		"redis":  {registry: NewRedisRegistry(client, time.Minute), bus: NewRedisBus(client)},
+	}
+}
+
+func TestRegistryClaimFindRelease(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			info := Info{SessionID: "s1", UserUUID: "u1", ChatbotUUID: "c1", InstanceID: "pod-a"}
+
+			if err := b.registry.Claim(ctx, info); err != nil {
+				t.Fatalf("Claim: %v", err)
+			}
+
+			duplicate := info
+			duplicate.SessionID = "s2"
+			if err := b.registry.Claim(ctx, duplicate); !errors.Is(err, ErrSessionExists) {
+				t.Fatalf("expected ErrSessionExists, got %v", err)
+			}
+
+			found, err := b.registry.Find(ctx, "u1", "c1")
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found.SessionID != "s1" || found.InstanceID != "pod-a" {
+				t.Errorf("unexpected session: %+v", found)
+			}
+
+			if err := b.registry.Release(ctx, info); err != nil {
+				t.Fatalf("Release: %v", err)
+			}
+			if _, err := b.registry.Get(ctx, "s1"); !errors.Is(err, ErrSessionNotFound) {
+				t.Errorf("expected ErrSessionNotFound after release, got %v", err)
+			}
+			if err := b.registry.Claim(ctx, duplicate); err != nil {
+				t.Errorf("Claim after release: %v", err)
+			}
+		})
+	}
+}
+
+func TestRegistryReleaseKeepsNewerOwner(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			old := Info{SessionID: "old", UserUUID: "u1", ChatbotUUID: "c1", InstanceID: "pod-a"}
+			newer := Info{SessionID: "new", UserUUID: "u1", ChatbotUUID: "c1", InstanceID: "pod-b"}
+
+			b.registry.Claim(ctx, old)
+			b.registry.Release(ctx, old)
+			b.registry.Claim(ctx, newer)
+
+			// 늦게 도착한 이전 세션의 해제 요청이 새 세션 등록을 지우면 안 된다
+			b.registry.Release(ctx, old)
+
+			found, err := b.registry.Find(ctx, "u1", "c1")
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if found.SessionID != "new" {
+				t.Errorf("expected new session to remain, got %s", found.SessionID)
+			}
+		})
+	}
+}
+
+func TestRegistryCountUser(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			phone := Info{SessionID: "s1", UserUUID: "u1", ChatbotUUID: "c1", InstanceID: "pod-a"}
+			laptop := Info{SessionID: "s2", UserUUID: "u1", ChatbotUUID: "c2", InstanceID: "pod-b"}
+			other := Info{SessionID: "s3", UserUUID: "u2", ChatbotUUID: "c1", InstanceID: "pod-a"}
+			for _, info := range []Info{phone, laptop, other} {
+				if err := b.registry.Claim(ctx, info); err != nil {
+					t.Fatalf("Claim: %v", err)
+				}
+			}
+
+			if count, err := b.registry.CountUser(ctx, "u1"); err != nil || count != 2 {
+				t.Fatalf("CountUser = %d, %v; want 2", count, err)
+			}
+
+			b.registry.Release(ctx, phone)
+			if count, err := b.registry.CountUser(ctx, "u1"); err != nil || count != 1 {
+				t.Fatalf("CountUser after release = %d, %v; want 1", count, err)
+			}
+			if count, err := b.registry.CountUser(ctx, "nobody"); err != nil || count != 0 {
+				t.Fatalf("CountUser for unknown user = %d, %v; want 0", count, err)
+			}
+		})
+	}
+}
+
+func TestRedisCountUserSkipsExpiredSessions(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	registry := NewRedisRegistry(client, 50*time.Millisecond)
+	ctx := context.Background()
+	if err := registry.Claim(ctx, Info{SessionID: "s1", UserUUID: "u1", ChatbotUUID: "c1", InstanceID: "pod-a"}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	// 소유 인스턴스가 죽어 갱신이 끊긴 세션은 ttl이 지나면 세지 않음
+	time.Sleep(100 * time.Millisecond)
+	if count, err := registry.CountUser(ctx, "u1"); err != nil || count != 0 {
+		t.Fatalf("CountUser = %d, %v; want 0", count, err)
+	}
+}
+
+func TestBusDeliversToSubscribedInstance(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			received := make(chan Envelope, 1)
+			if err := b.bus.Subscribe(ctx, "pod-a", func(e Envelope) { received <- e }); err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+
+			sent := Envelope{Kind: KindMessage, SessionID: "s1", Data: "data: {}\n\n"}
+			if err := b.bus.Publish(ctx, "pod-a", sent); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+
+			select {
+			case got := <-received:
+				if got != sent {
+					t.Errorf("got %+v, want %+v", got, sent)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("envelope not delivered")
+			}
+
+			if err := b.bus.Publish(ctx, "pod-missing", sent); !errors.Is(err, ErrNoSubscriber) {
+				t.Errorf("expected ErrNoSubscriber, got %v", err)
+			}
+		})
+	}
+}
@@ -1,13 +1,13 @@
 package status
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
+	"sermo-be/internal/repository"
+	"sermo-be/pkg/llm"
 )
 
 // StatusExtractionResult 상태 추출 결과
@@ -18,36 +18,52 @@ type StatusExtractionResult struct {
 	Context    string    `json:"context,omitempty"`
 }
 
+// StatusExtractionFormat 상태 추출 응답 형식 (StatusExtractionResult와 같은 필드, 저장할 정보가 없으면 null)
+var StatusExtractionFormat = llm.JSONSchema("status_extraction", `{
+	"type": "object",
+	"properties": {
+		"needs_save": {"type": "boolean"},
+		"event": {"type": ["string", "null"]},
+		"valid_until": {"type": ["string", "null"], "description": "RFC 3339 date-time, e.g. 2025-01-02T15:04:05Z"},
+		"context": {"type": ["string", "null"]}
+	},
+	"required": ["needs_save", "event", "valid_until", "context"],
+	"additionalProperties": false
+}`)
+
 // StatusService 사용자 상태 정보 관리 서비스
-type StatusService struct{}
+type StatusService struct {
+	statuses repository.UserStatusRepository
+}
+
+// NewStatusService 새로운 StatusService 인스턴스 생성
+func NewStatusService(statuses repository.UserStatusRepository) *StatusService {
+	return &StatusService{statuses: statuses}
+}
 
 // SaveUserStatus 사용자 상태 정보 저장
-func (s *StatusService) SaveUserStatus(userUUID, chatbotUUID, event string, validUntil time.Time, context string) error {
+func (s *StatusService) SaveUserStatus(userUUID, chatbotUUID, event string, validUntil time.Time, context string) (*models.UserStatus, error) {
 	userStatus := models.NewUserStatus(userUUID, chatbotUUID, event, validUntil, context)
 
-	if err := database.DB.Create(userStatus).Error; err != nil {
-		return fmt.Errorf("사용자 상태 정보 저장 실패: %w", err)
+	if err := s.statuses.Create(userStatus); err != nil {
+		return nil, fmt.Errorf("사용자 상태 정보 저장 실패: %w", err)
 	}
 
 	log.Printf("사용자 상태 정보 저장 완료 - 사용자: %s, 이벤트: %s, 유효시간: %s",
 		userUUID, event, validUntil.Format("2006-01-02 15:04:05"))
 
-	return nil
+	return userStatus, nil
 }
 
 // ParseStatusExtractionResult 상태 추출 결과 파싱
+// 저장이 필요하다고 응답했지만 이벤트나 유효 시간이 없으면 에러를 반환한다.
 func (s *StatusService) ParseStatusExtractionResult(response string) (*StatusExtractionResult, error) {
 	var result StatusExtractionResult
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
+	if err := llm.DecodeJSON(response, &result); err != nil {
 		return nil, fmt.Errorf("상태 추출 결과 파싱 실패: %w", err)
 	}
+	if result.NeedsSave && (result.Event == "" || result.ValidUntil.IsZero()) {
+		return nil, fmt.Errorf("상태 추출 결과에 event 또는 valid_until이 없음")
+	}
 	return &result, nil
 }
-
-// GetStatusService 전역 StatusService 반환
-func GetStatusService() *StatusService {
-	return globalStatusService
-}
-
-// 전역 StatusService 인스턴스
-var globalStatusService = &StatusService{}
@@ -0,0 +1,26 @@
+package status
+
+import "testing"
+
+func TestParseStatusExtractionResult(t *testing.T) {
+	s := &StatusService{}
+
+	result, err := s.ParseStatusExtractionResult(`{"needs_save": true, "event": "시험", "valid_until": "2025-01-02T23:59:59Z", "context": "내일 시험"}`)
+	if err != nil {
+		t.Fatalf("ParseStatusExtractionResult: %v", err)
+	}
+	if !result.NeedsSave || result.Event != "시험" || result.ValidUntil.Day() != 2 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// 스키마의 null 필드
+	result, err = s.ParseStatusExtractionResult(`{"needs_save": false, "event": null, "valid_until": null, "context": null}`)
+	if err != nil || result.NeedsSave {
+		t.Errorf("no status = %+v, %v", result, err)
+	}
+
+	// 저장이 필요하다면서 유효 시간이 없으면 에러
+	if _, err := s.ParseStatusExtractionResult(`{"needs_save": true, "event": "시험", "valid_until": null, "context": null}`); err == nil {
+		t.Error("expected error without valid_until")
+	}
+}
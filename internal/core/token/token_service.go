@@ -0,0 +1,189 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/pkg/jwt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidRefreshToken 존재하지 않거나 만료된 리프레시 토큰
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused 이미 사용(회전)된 리프레시 토큰 재사용 감지
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// TokenPair 액세스 토큰과 리프레시 토큰 묶음
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+	SessionID        string
+}
+
+// TokenService 액세스/리프레시 토큰 발급, 회전, 폐기를 담당하는 서비스
+// 서버 시작 시 한 번 만들고, 핸들러와 인증 미들웨어는 middleware.GetTokenService로 주입받아 사용한다.
+type TokenService struct {
+	db *gorm.DB
+}
+
+// NewTokenService 새로운 TokenService 인스턴스 생성
+func NewTokenService(db *gorm.DB) *TokenService {
+	return &TokenService{db: db}
+}
+
+// IssueTokens 로그인 시 새로운 토큰 계열(세션)을 만들고 토큰 발급
+func (s *TokenService) IssueTokens(userUUID, deviceInfo string) (*TokenPair, error) {
+	familyID := uuid.New().String()
+
+	var pair *TokenPair
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		pair, _, err = s.createTokenPair(tx, userUUID, familyID, deviceInfo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// RotateTokens 리프레시 토큰을 검증하고 새 토큰으로 교체
+// 이미 회전된 토큰이 다시 사용되면 탈취로 간주하고 계열 전체를 폐기한다
+func (s *TokenService) RotateTokens(refreshToken, deviceInfo string) (*TokenPair, error) {
+	tokenHash := jwt.HashRefreshToken(refreshToken)
+
+	var pair *TokenPair
+	var reused *models.RefreshToken
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			First(&current).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if current.Revoked {
+			reused = &current
+			return ErrRefreshTokenReused
+		}
+
+		if current.IsExpired() {
+			return ErrInvalidRefreshToken
+		}
+
+		if deviceInfo == "" {
+			deviceInfo = current.DeviceInfo
+		}
+
+		newPair, newToken, err := s.createTokenPair(tx, current.UserUUID, current.FamilyID, deviceInfo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&current).Updates(map[string]interface{}{
+			"revoked":        true,
+			"revoked_at":     now,
+			"replaced_by_id": newToken.UUID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to revoke rotated refresh token: %w", err)
+		}
+
+		pair = newPair
+		return nil
+	})
+
+	// 재사용이 감지되면 트랜잭션 밖에서 계열 전체 폐기
+	if errors.Is(err, ErrRefreshTokenReused) && reused != nil {
+		log.Printf("⚠️ 리프레시 토큰 재사용 감지 - 사용자: %s, 계열: %s", reused.UserUUID, reused.FamilyID)
+		if revokeErr := s.RevokeFamily(reused.FamilyID); revokeErr != nil {
+			log.Printf("❌ 토큰 계열 폐기 실패 - 계열: %s, 에러: %v", reused.FamilyID, revokeErr)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// RevokeByRefreshToken 리프레시 토큰이 속한 계열(세션) 전체 폐기 (로그아웃)
+func (s *TokenService) RevokeByRefreshToken(refreshToken string) error {
+	var current models.RefreshToken
+	if err := s.db.Where("token_hash = ?", jwt.HashRefreshToken(refreshToken)).First(&current).Error; err != nil {
+		return ErrInvalidRefreshToken
+	}
+
+	return s.RevokeFamily(current.FamilyID)
+}
+
+// RevokeFamily 토큰 계열의 모든 리프레시 토큰 폐기
+func (s *TokenService) RevokeFamily(familyID string) error {
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked = ?", familyID, false).
+		Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+// IsSessionActive 세션(토큰 계열)에 폐기되지 않은 리프레시 토큰이 남아있는지 확인
+func (s *TokenService) IsSessionActive(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+
+	var count int64
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked = ? AND expires_at > ?", sessionID, false, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// createTokenPair 리프레시 토큰 저장 후 액세스 토큰과 함께 반환
+func (s *TokenService) createTokenPair(tx *gorm.DB, userUUID, familyID, deviceInfo string) (*TokenPair, *models.RefreshToken, error) {
+	refreshToken, refreshHash, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(jwt.RefreshTokenTTL)
+
+	record := models.NewRefreshToken(userUUID, familyID, refreshHash, deviceInfo, refreshExpiresAt)
+	if err := tx.Create(record).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	accessToken, err := jwt.GenerateToken(userUUID, familyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresAt:  now.Add(jwt.AccessTokenTTL),
+		RefreshExpiresAt: refreshExpiresAt,
+		SessionID:        familyID,
+	}, record, nil
+}
@@ -0,0 +1,177 @@
+package token_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/models"
+	"sermo-be/internal/testutil"
+	"sermo-be/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+const userUUID = "00000000-0000-4000-8000-000000000001"
+
+func login(t *testing.T, svc *token.TokenService) *token.TokenPair {
+	t.Helper()
+	pair, err := svc.IssueTokens(userUUID, "test-device")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	return pair
+}
+
+func refreshRecord(t *testing.T, db *gorm.DB, refreshToken string) models.RefreshToken {
+	t.Helper()
+	var record models.RefreshToken
+	if err := db.Where("token_hash = ?", jwt.HashRefreshToken(refreshToken)).First(&record).Error; err != nil {
+		t.Fatalf("load refresh token: %v", err)
+	}
+	return record
+}
+
+func assertSessionActive(t *testing.T, svc *token.TokenService, sessionID string, want bool) {
+	t.Helper()
+	active, err := svc.IsSessionActive(sessionID)
+	if err != nil {
+		t.Fatalf("IsSessionActive: %v", err)
+	}
+	if active != want {
+		t.Fatalf("session %s active = %v, want %v", sessionID, active, want)
+	}
+}
+
+func TestTokenService(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, svc *token.TokenService, db *gorm.DB)
+	}{
+		{
+			name: "login issues an active session",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				pair := login(t, svc)
+
+				claims, err := jwt.ValidateToken(pair.AccessToken)
+				if err != nil {
+					t.Fatalf("ValidateToken: %v", err)
+				}
+				if claims.UUID != userUUID || claims.SessionID != pair.SessionID {
+					t.Fatalf("claims = %+v, want user %s session %s", claims, userUUID, pair.SessionID)
+				}
+				if record := refreshRecord(t, db, pair.RefreshToken); record.DeviceInfo != "test-device" || record.Revoked {
+					t.Fatalf("refresh token record = %+v", record)
+				}
+				assertSessionActive(t, svc, pair.SessionID, true)
+			},
+		},
+		{
+			name: "rotation issues a new pair and revokes the old token",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				first := login(t, svc)
+
+				second, err := svc.RotateTokens(first.RefreshToken, "")
+				if err != nil {
+					t.Fatalf("RotateTokens: %v", err)
+				}
+				if second.RefreshToken == first.RefreshToken {
+					t.Fatal("rotation returned the same refresh token")
+				}
+				if second.SessionID != first.SessionID {
+					t.Fatalf("session = %s, want %s (rotation keeps the family)", second.SessionID, first.SessionID)
+				}
+
+				old := refreshRecord(t, db, first.RefreshToken)
+				next := refreshRecord(t, db, second.RefreshToken)
+				if !old.Revoked || old.RevokedAt == nil || old.ReplacedByID == nil || *old.ReplacedByID != next.UUID {
+					t.Fatalf("old token = %+v, want revoked and replaced by %s", old, next.UUID)
+				}
+				if next.Revoked || next.DeviceInfo != "test-device" {
+					t.Fatalf("new token = %+v, want active with the previous device info", next)
+				}
+				assertSessionActive(t, svc, first.SessionID, true)
+
+				if _, err := svc.RotateTokens(second.RefreshToken, "other-device"); err != nil {
+					t.Fatalf("rotating the new token: %v", err)
+				}
+			},
+		},
+		{
+			name: "reusing a rotated token revokes the whole family",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				first := login(t, svc)
+				second, err := svc.RotateTokens(first.RefreshToken, "")
+				if err != nil {
+					t.Fatalf("RotateTokens: %v", err)
+				}
+
+				if _, err := svc.RotateTokens(first.RefreshToken, ""); !errors.Is(err, token.ErrRefreshTokenReused) {
+					t.Fatalf("reuse error = %v, want ErrRefreshTokenReused", err)
+				}
+				if record := refreshRecord(t, db, second.RefreshToken); !record.Revoked {
+					t.Fatal("the latest token of the family should be revoked after reuse")
+				}
+				assertSessionActive(t, svc, first.SessionID, false)
+
+				if _, err := svc.RotateTokens(second.RefreshToken, ""); !errors.Is(err, token.ErrRefreshTokenReused) {
+					t.Fatalf("rotating a token of the revoked family = %v, want ErrRefreshTokenReused", err)
+				}
+			},
+		},
+		{
+			name: "logout revokes only that session",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				phone := login(t, svc)
+				laptop := login(t, svc)
+
+				if err := svc.RevokeByRefreshToken(phone.RefreshToken); err != nil {
+					t.Fatalf("RevokeByRefreshToken: %v", err)
+				}
+				assertSessionActive(t, svc, phone.SessionID, false)
+				assertSessionActive(t, svc, laptop.SessionID, true)
+
+				if _, err := svc.RotateTokens(phone.RefreshToken, ""); !errors.Is(err, token.ErrRefreshTokenReused) {
+					t.Fatalf("refresh after logout = %v, want ErrRefreshTokenReused", err)
+				}
+			},
+		},
+		{
+			name: "unknown refresh token",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				if _, err := svc.RotateTokens("unknown", ""); !errors.Is(err, token.ErrInvalidRefreshToken) {
+					t.Fatalf("RotateTokens = %v, want ErrInvalidRefreshToken", err)
+				}
+				if err := svc.RevokeByRefreshToken("unknown"); !errors.Is(err, token.ErrInvalidRefreshToken) {
+					t.Fatalf("RevokeByRefreshToken = %v, want ErrInvalidRefreshToken", err)
+				}
+				assertSessionActive(t, svc, "", false)
+			},
+		},
+		{
+			name: "expired refresh token",
+			run: func(t *testing.T, svc *token.TokenService, db *gorm.DB) {
+				pair := login(t, svc)
+				if err := db.Model(&models.RefreshToken{}).
+					Where("family_id = ?", pair.SessionID).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+					t.Fatalf("expire token: %v", err)
+				}
+
+				if _, err := svc.RotateTokens(pair.RefreshToken, ""); !errors.Is(err, token.ErrInvalidRefreshToken) {
+					t.Fatalf("RotateTokens = %v, want ErrInvalidRefreshToken", err)
+				}
+				assertSessionActive(t, svc, pair.SessionID, false)
+			},
+		},
+	}
+
+	testutil.ConfigureJWT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.NewDB(t)
+			tt.run(t, token.NewTokenService(db), db)
+		})
+	}
+}
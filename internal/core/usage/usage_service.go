@@ -0,0 +1,304 @@
+package usage
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/pkg/llm"
+
+	"gorm.io/gorm"
+)
+
+// 사용량 기록 기능 (어떤 작업에 쓰였는지)
+const (
+	FeatureAnswer      = "answer"       // 봇 초기 응답 생성
+	FeatureValidation  = "validation"   // 봇 응답 검증 및 재조정
+	FeatureStatus      = "status"       // 사용자 상태 정보 추출
+	FeatureSummary     = "summary"      // 캐릭터/대화 요약
+	FeatureAlarm       = "alarm"        // 채팅 종료 후 알람 메시지 생성
+	FeatureBookmark    = "bookmark"     // 북마크 단어/문장 뜻 생성
+	FeatureImagePrompt = "image_prompt" // 이미지 외형 특징 추출
+	FeatureImage       = "image"        // Gemini 이미지 생성
+	FeatureEmbedding   = "embedding"    // 기억 검색/저장과 기동 시 차원 확인용 임베딩
+)
+
+// SystemUserUUID 사용자 요청이 아닌 서버 작업의 사용량을 기록하는 사용자 (기동 시 임베딩 차원 확인 등)
+const SystemUserUUID = "system"
+
+// Resource 한도를 적용하는 사용량 종류
+type Resource string
+
+const (
+	ResourceTokens Resource = "tokens" // 언어 모델 토큰 (프롬프트 + 완성)
+	ResourceImages Resource = "images" // 생성된 이미지 수
+)
+
+// Quota 사용자별 일/월 사용 한도 (0이면 무제한)
+type Quota struct {
+	DailyTokens   int64
+	MonthlyTokens int64
+	DailyImages   int64
+	MonthlyImages int64
+}
+
+// limit 기간과 사용량 종류에 해당하는 한도
+func (q Quota) limit(period Period, resource Resource) int64 {
+	switch {
+	case period == PeriodDaily && resource == ResourceTokens:
+		return q.DailyTokens
+	case period == PeriodDaily && resource == ResourceImages:
+		return q.DailyImages
+	case period == PeriodMonthly && resource == ResourceTokens:
+		return q.MonthlyTokens
+	case period == PeriodMonthly && resource == ResourceImages:
+		return q.MonthlyImages
+	}
+	return 0
+}
+
+// Period 한도를 계산하는 기간 (UTC 기준)
+type Period string
+
+const (
+	PeriodDaily   Period = "daily"
+	PeriodMonthly Period = "monthly"
+)
+
+// Bounds 기간의 시작과 다음 기간 시작 (한도가 초기화되는 시각)
+func (p Period) Bounds(now time.Time) (start, reset time.Time) {
+	now = now.UTC()
+	if p == PeriodMonthly {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Totals 기간 동안의 사용량 합계
+type Totals struct {
+	Tokens int64 `json:"tokens"`
+	Images int64 `json:"images"`
+}
+
+func (t Totals) of(resource Resource) int64 {
+	if resource == ResourceImages {
+		return t.Images
+	}
+	return t.Tokens
+}
+
+// QuotaExceededError 사용 한도 초과
+type QuotaExceededError struct {
+	Period   Period
+	Resource Resource
+	Limit    int64
+	Used     int64
+	ResetAt  time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s %s quota exceeded (%d/%d), resets at %s",
+		e.Period, e.Resource, e.Used, e.Limit, e.ResetAt.Format(time.RFC3339))
+}
+
+// UsageService AI 사용량 기록과 한도 확인 서비스
+// 서버 시작 시 DB와 설정의 사용자별 한도로 한 번 만들고, 핸들러는 middleware.GetUsageService로,
+// 채팅 파이프라인은 chat.UsageTracker로 주입받아 사용한다.
+type UsageService struct {
+	db    *gorm.DB
+	quota Quota
+}
+
+// NewUsageService 새로운 UsageService 인스턴스 생성
+func NewUsageService(db *gorm.DB, quota Quota) *UsageService {
+	return &UsageService{db: db, quota: quota}
+}
+
+// Record 사용량 기록 (실패해도 호출한 작업은 계속되도록 로그만 남김)
+func (s *UsageService) Record(record *models.UsageRecord) {
+	if record.UserUUID == "" {
+		return
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		log.Printf("사용량 기록 실패 - 사용자: %s, 기능: %s, 에러: %v", record.UserUUID, record.Feature, err)
+	}
+}
+
+// RecordChat 언어 모델 응답의 토큰 사용량 기록 (응답에 모델 이름이 없으면 model의 기본 모델)
+func (s *UsageService) RecordChat(userUUID, feature string, model llm.LLM, response *llm.ChatResponse) {
+	if response == nil {
+		return
+	}
+
+	modelName := response.Model
+	if modelName == "" && model != nil {
+		modelName = model.GetModel()
+	}
+
+	s.Record(&models.UsageRecord{
+		UserUUID:         userUUID,
+		Feature:          feature,
+		Model:            modelName,
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+		TotalTokens:      response.Usage.TotalTokens,
+	})
+}
+
+// RecordEmbedding 임베딩 응답의 토큰 사용량 기록
+func (s *UsageService) RecordEmbedding(userUUID string, response *llm.EmbeddingResponse) {
+	if response == nil {
+		return
+	}
+
+	s.Record(&models.UsageRecord{
+		UserUUID:     userUUID,
+		Feature:      FeatureEmbedding,
+		Model:        response.Model,
+		PromptTokens: response.Usage.PromptTokens,
+		TotalTokens:  response.Usage.TotalTokens,
+	})
+}
+
+// Totals since 이후 사용자의 사용량 합계
+func (s *UsageService) Totals(userUUID string, since time.Time) (Totals, error) {
+	var totals Totals
+	err := s.db.Model(&models.UsageRecord{}).
+		Select("COALESCE(SUM(total_tokens), 0) AS tokens, COALESCE(SUM(images), 0) AS images").
+		Where("user_uuid = ? AND created_at >= ?", userUUID, since).
+		Scan(&totals).Error
+	if err != nil {
+		return Totals{}, fmt.Errorf("failed to sum usage: %w", err)
+	}
+	return totals, nil
+}
+
+// CheckQuota 사용자의 일/월 사용량이 resources 한도 안인지 확인 (초과하면 *QuotaExceededError)
+// 한도가 설정되지 않은 기간은 조회하지 않는다.
+func (s *UsageService) CheckQuota(userUUID string, now time.Time, resources ...Resource) error {
+	quota := s.quota
+	for _, period := range []Period{PeriodDaily, PeriodMonthly} {
+		limited := false
+		for _, resource := range resources {
+			if quota.limit(period, resource) > 0 {
+				limited = true
+			}
+		}
+		if !limited {
+			continue
+		}
+
+		start, _ := period.Bounds(now)
+		totals, err := s.Totals(userUUID, start)
+		if err != nil {
+			return err
+		}
+		if err := checkLimits(quota, period, totals, now, resources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLimits 기간 사용량이 한도에 이른 첫 번째 사용량 종류를 에러로 반환
+func checkLimits(quota Quota, period Period, totals Totals, now time.Time, resources []Resource) error {
+	for _, resource := range resources {
+		limit := quota.limit(period, resource)
+		if limit <= 0 {
+			continue
+		}
+		if used := totals.of(resource); used >= limit {
+			_, reset := period.Bounds(now)
+			return &QuotaExceededError{Period: period, Resource: resource, Limit: limit, Used: used, ResetAt: reset}
+		}
+	}
+	return nil
+}
+
+// PeriodUsage 기간별 사용량과 한도 (한도가 0이면 무제한)
+type PeriodUsage struct {
+	Period     Period    `json:"period"`
+	Since      time.Time `json:"since"`
+	ResetAt    time.Time `json:"reset_at"`
+	Used       Totals    `json:"used"`
+	TokenLimit int64     `json:"token_limit"`
+	ImageLimit int64     `json:"image_limit"`
+	TokensLeft *int64    `json:"tokens_left,omitempty"` // 한도가 있을 때만
+	ImagesLeft *int64    `json:"images_left,omitempty"`
+}
+
+// FeatureUsage 기능/모델별 사용량
+type FeatureUsage struct {
+	Feature          string `json:"feature"`
+	Model            string `json:"model"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+	Images           int64  `json:"images"`
+}
+
+// Report 사용자의 일/월 사용량과 이번 달 기능별 내역
+type Report struct {
+	Daily    PeriodUsage    `json:"daily"`
+	Monthly  PeriodUsage    `json:"monthly"`
+	Features []FeatureUsage `json:"features"`
+}
+
+// Report 사용자의 사용량 보고서
+func (s *UsageService) Report(userUUID string, now time.Time) (*Report, error) {
+	report := &Report{Features: []FeatureUsage{}}
+
+	for _, period := range []Period{PeriodDaily, PeriodMonthly} {
+		start, reset := period.Bounds(now)
+		totals, err := s.Totals(userUUID, start)
+		if err != nil {
+			return nil, err
+		}
+
+		usage := newPeriodUsage(period, start, reset, totals, s.quota)
+		if period == PeriodDaily {
+			report.Daily = usage
+		} else {
+			report.Monthly = usage
+		}
+	}
+
+	err := s.db.Model(&models.UsageRecord{}).
+		Select("feature, model, COUNT(*) AS requests, "+
+			"COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(completion_tokens), 0) AS completion_tokens, "+
+			"COALESCE(SUM(total_tokens), 0) AS total_tokens, COALESCE(SUM(images), 0) AS images").
+		Where("user_uuid = ? AND created_at >= ?", userUUID, report.Monthly.Since).
+		Group("feature, model").
+		Order("total_tokens DESC, feature, model").
+		Scan(&report.Features).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to group usage: %w", err)
+	}
+
+	return report, nil
+}
+
+// newPeriodUsage 기간 사용량에 한도와 남은 양 채우기
+func newPeriodUsage(period Period, start, reset time.Time, totals Totals, quota Quota) PeriodUsage {
+	usage := PeriodUsage{
+		Period:     period,
+		Since:      start,
+		ResetAt:    reset,
+		Used:       totals,
+		TokenLimit: quota.limit(period, ResourceTokens),
+		ImageLimit: quota.limit(period, ResourceImages),
+	}
+	if usage.TokenLimit > 0 {
+		left := max(usage.TokenLimit-totals.Tokens, 0)
+		usage.TokensLeft = &left
+	}
+	if usage.ImageLimit > 0 {
+		left := max(usage.ImageLimit-totals.Images, 0)
+		usage.ImagesLeft = &left
+	}
+	return usage
+}
@@ -0,0 +1,56 @@
+package usage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	now := time.Date(2026, 1, 31, 23, 30, 0, 0, time.FixedZone("KST", 9*60*60)) // UTC 2026-01-31 14:30
+
+	start, reset := PeriodDaily.Bounds(now)
+	if !start.Equal(time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)) || !reset.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily = %v ~ %v", start, reset)
+	}
+
+	start, reset = PeriodMonthly.Bounds(now)
+	if !start.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !reset.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("monthly = %v ~ %v", start, reset)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	quota := Quota{DailyTokens: 1000, DailyImages: 3}
+
+	if err := checkLimits(quota, PeriodDaily, Totals{Tokens: 999, Images: 5}, now, []Resource{ResourceTokens}); err != nil {
+		t.Errorf("under token limit: %v", err)
+	}
+
+	err := checkLimits(quota, PeriodDaily, Totals{Tokens: 10, Images: 3}, now, []Resource{ResourceTokens, ResourceImages})
+	var exceeded *QuotaExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("err = %v, want *QuotaExceededError", err)
+	}
+	if exceeded.Resource != ResourceImages || exceeded.Used != 3 || exceeded.Limit != 3 ||
+		!exceeded.ResetAt.Equal(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("exceeded = %+v", exceeded)
+	}
+
+	// 한도가 0이면 무제한
+	if err := checkLimits(quota, PeriodMonthly, Totals{Tokens: 1 << 40}, now, []Resource{ResourceTokens}); err != nil {
+		t.Errorf("unlimited monthly: %v", err)
+	}
+}
+
+func TestNewPeriodUsage(t *testing.T) {
+	usage := newPeriodUsage(PeriodMonthly, time.Time{}, time.Time{}, Totals{Tokens: 1500, Images: 1}, Quota{MonthlyTokens: 1000})
+
+	if usage.TokensLeft == nil || *usage.TokensLeft != 0 {
+		t.Errorf("tokens left = %v, want 0", usage.TokensLeft)
+	}
+	if usage.ImagesLeft != nil || usage.ImageLimit != 0 {
+		t.Errorf("images without limit = %+v", usage)
+	}
+}
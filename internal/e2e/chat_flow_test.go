@@ -0,0 +1,176 @@
+package e2e
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/internal/testutil"
+	"sermo-be/pkg/llm/llmtest"
+)
+
+func TestChatFlowSchedulesAlarm(t *testing.T) {
+	script := testutil.DefaultScript()
+	server := testutil.NewServer(t, script.LLM())
+
+	userUUID, token := server.SignUp(t, "alice", "password123")
+
+	// 채팅봇 프로필 사진은 가짜 오브젝트 저장소에 업로드
+	imageID := server.UploadImage(t, token, "luna.png", []byte("\x89PNG fake image"))
+	keys := server.Objects.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("stored objects = %v, want the uploaded image", keys)
+	}
+	if data, _ := server.Objects.Object(keys[0]); string(data) != "\x89PNG fake image" {
+		t.Errorf("stored image = %q", data)
+	}
+
+	var created struct {
+		ChatbotID string `json:"chatbot_id"`
+	}
+	server.JSON(t, http.MethodPost, "/chatbot/", token, map[string]interface{}{
+		"name":     "Luna",
+		"image_id": imageID,
+		"hashtags": []string{"friendly"},
+		"gender":   "female",
+		"details":  "A cheerful friend who loves to chat.",
+	}, http.StatusCreated, &created)
+	chatbotUUID := created.ChatbotID
+
+	stream := server.OpenStream(t, "/chat/start?chatbot_uuid="+chatbotUUID, token)
+
+	message := map[string]string{"chatbot_uuid": chatbotUUID, "message": "I have a math exam tomorrow"}
+	server.JSON(t, http.MethodPost, "/chat/send", token, message, http.StatusOK, nil)
+	if echo := stream.Next(t, "user"); echo.Field("content") != message["message"] {
+		t.Errorf("user echo = %+v", echo.Data)
+	}
+
+	// 응답 대기 시간 없이 바로 봇 응답 요청
+	server.JSON(t, http.MethodPost, "/chat/flush", token, map[string]string{"chatbot_uuid": chatbotUUID}, http.StatusOK, nil)
+	if reply := stream.Next(t, "bot"); reply.Field("content") != script.Reply {
+		t.Errorf("bot reply = %+v, want %q", reply.Data, script.Reply)
+	}
+
+	// 상태 정보는 봇 응답과 별도로 백그라운드에서 저장됨
+	testutil.Eventually(t, "user status", func() bool {
+		statuses, err := server.Repos.Statuses.ListValid(userUUID, chatbotUUID, time.Now())
+		return err == nil && len(statuses) == 1
+	})
+
+	server.JSON(t, http.MethodPost, "/chat/stop", token, map[string]string{"chatbot_uuid": chatbotUUID}, http.StatusOK, nil)
+
+	sent := server.Push.WaitSent(t, 1)
+	if sent[0].UserUUID != userUUID || sent[0].Notification.ChatMessage != script.EnhancedAlarm {
+		t.Errorf("push = %+v %+v", sent[0], sent[0].Notification)
+	}
+
+	var alarms []models.AlarmSchedule
+	if err := server.DB.Where("user_uuid = ?", userUUID).Find(&alarms).Error; err != nil {
+		t.Fatalf("find alarms: %v", err)
+	}
+	if len(alarms) != 1 || alarms[0].ChatbotUUID != chatbotUUID || alarms[0].Message != script.EnhancedAlarm || alarms[0].Sended {
+		t.Fatalf("alarms = %+v", alarms)
+	}
+	if !alarms[0].SendTime.After(time.Now()) {
+		t.Errorf("alarm send time = %v, want a time before the exam", alarms[0].SendTime)
+	}
+
+	var history []models.ChatMessage
+	if err := server.DB.Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID).Order("created_at").Find(&history).Error; err != nil {
+		t.Fatalf("find history: %v", err)
+	}
+	if len(history) != 2 || history[0].MessageType != models.MessageTypeUser || history[1].Content != script.Reply {
+		t.Errorf("history = %+v", history)
+	}
+}
+
+func TestChatFlowReportsLLMFailure(t *testing.T) {
+	server := testutil.NewServer(t, &llmtest.Fake{Err: errors.New("provider down")})
+	_, token := server.SignUp(t, "bob", "password123")
+
+	var created struct {
+		ChatbotID string `json:"chatbot_id"`
+	}
+	server.JSON(t, http.MethodPost, "/chatbot/", token, map[string]interface{}{
+		"name":     "Luna",
+		"image_id": "no-image",
+		"gender":   "unspecified",
+	}, http.StatusCreated, &created)
+
+	stream := server.OpenStream(t, "/chat/start?chatbot_uuid="+created.ChatbotID, token)
+	server.JSON(t, http.MethodPost, "/chat/send", token, map[string]string{"chatbot_uuid": created.ChatbotID, "message": "hello"}, http.StatusOK, nil)
+	server.JSON(t, http.MethodPost, "/chat/flush", token, map[string]string{"chatbot_uuid": created.ChatbotID}, http.StatusOK, nil)
+
+	if event := stream.Next(t, "bot_error"); event.Field("code") != "llm_unavailable" {
+		t.Errorf("bot_error = %+v", event.Data)
+	}
+
+	// 실패한 응답은 히스토리에 남지 않음
+	var count int64
+	server.DB.Model(&models.ChatMessage{}).Where("message_type = ?", models.MessageTypeChatbot).Count(&count)
+	if count != 0 {
+		t.Errorf("bot messages = %d, want none", count)
+	}
+}
+
+func TestChatReportsReplyState(t *testing.T) {
+	script := testutil.DefaultScript()
+	server := testutil.NewServer(t, script.LLM())
+	_, token := server.SignUp(t, "dave", "password123")
+
+	var created struct {
+		ChatbotID string `json:"chatbot_id"`
+	}
+	server.JSON(t, http.MethodPost, "/chatbot/", token, map[string]interface{}{
+		"name":                "Luna",
+		"image_id":            "no-image",
+		"gender":              "female",
+		"reply_delay_ms":      5000,
+		"typing_extension_ms": 8000,
+		"max_reply_wait_ms":   20000,
+	}, http.StatusCreated, &created)
+	chatbotUUID := created.ChatbotID
+
+	stream := server.OpenStream(t, "/chat/start?chatbot_uuid="+chatbotUUID, token)
+	dueAt := func(event testutil.Event) time.Time {
+		t.Helper()
+		parsed, err := time.Parse(time.RFC3339Nano, event.Field("due_at"))
+		if err != nil {
+			t.Fatalf("due_at of %+v: %v", event.Data, err)
+		}
+		return parsed
+	}
+
+	// 메시지를 보내면 기본 대기 시간 뒤로 응답 예정
+	server.JSON(t, http.MethodPost, "/chat/send", token, map[string]string{"chatbot_uuid": chatbotUUID, "message": "hello"}, http.StatusOK, nil)
+	waiting := stream.Next(t, "reply_state")
+	if waiting.Data["pending"] != true || waiting.Data["typing"] != false || waiting.Field("session_id") == "" {
+		t.Fatalf("reply_state after message = %+v", waiting.Data)
+	}
+	firstAt, err := time.Parse(time.RFC3339Nano, waiting.Field("first_message_at"))
+	if err != nil {
+		t.Fatalf("first_message_at: %v", err)
+	}
+	if delay := dueAt(waiting).Sub(firstAt); delay != 5*time.Second {
+		t.Errorf("due_at - first_message_at = %v, want the chatbot reply delay", delay)
+	}
+
+	// 입력 중 이벤트는 응답 예정 시각을 미룸
+	server.JSON(t, http.MethodPost, "/chat/onkeyboard", token, map[string]string{"chatbot_uuid": chatbotUUID}, http.StatusOK, nil)
+	typing := stream.Next(t, "reply_state")
+	if typing.Data["pending"] != true || typing.Data["typing"] != true || !dueAt(typing).After(dueAt(waiting)) {
+		t.Fatalf("reply_state after onkeyboard = %+v, want a later due_at than %s", typing.Data, waiting.Field("due_at"))
+	}
+
+	// 즉시 응답하면 대기 상태가 끝나고 봇 응답이 옴
+	server.JSON(t, http.MethodPost, "/chat/flush", token, map[string]string{"chatbot_uuid": chatbotUUID}, http.StatusOK, nil)
+	done := stream.Next(t, "reply_state")
+	if done.Data["pending"] != false || done.Field("due_at") != "" {
+		t.Fatalf("reply_state after flush = %+v", done.Data)
+	}
+	if reply := stream.Next(t, "bot"); reply.Field("content") != script.Reply {
+		t.Errorf("bot reply = %+v", reply.Data)
+	}
+}
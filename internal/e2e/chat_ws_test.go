@@ -0,0 +1,145 @@
+package e2e
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sermo-be/internal/testutil"
+
+	"github.com/fasthttp/websocket"
+)
+
+// createChatbot 응답 대기 시간을 짧게 설정한 채팅봇 생성 (flush 없이도 바로 응답)
+func createChatbot(t *testing.T, server *testutil.Server, token string) string {
+	t.Helper()
+
+	var created struct {
+		ChatbotID string `json:"chatbot_id"`
+	}
+	server.JSON(t, http.MethodPost, "/chatbot/", token, map[string]interface{}{
+		"name":           "Luna",
+		"image_id":       "no-image",
+		"gender":         "female",
+		"reply_delay_ms": 50,
+	}, http.StatusCreated, &created)
+	return created.ChatbotID
+}
+
+// sessionStreams 사용자-채팅봇 세션에 연결된 스트림 수 (세션이 없으면 -1)
+func sessionStreams(t *testing.T, server *testutil.Server, userUUID, chatbotUUID string) int {
+	t.Helper()
+
+	info, err := server.SSE.FindSession(userUUID, chatbotUUID)
+	if err != nil {
+		t.Fatalf("FindSession: %v", err)
+	}
+	if info == nil {
+		return -1
+	}
+	session, ok := server.SSE.GetSession(info.SessionID)
+	if !ok {
+		return -1
+	}
+	return session.StreamCount()
+}
+
+func TestChatWebSocketConversation(t *testing.T) {
+	script := testutil.DefaultScript()
+	server := testutil.NewServer(t, script.LLM())
+	userUUID, token := server.SignUp(t, "alice", "password123")
+	chatbotUUID := createChatbot(t, server, token)
+
+	ws := server.OpenWebSocket(t, "/chat/ws?chatbot_uuid="+chatbotUUID, token)
+	testutil.Eventually(t, "websocket session", func() bool {
+		return sessionStreams(t, server, userUUID, chatbotUUID) == 1
+	})
+
+	ws.Send(t, map[string]string{"type": "user", "content": "I have a math exam tomorrow"})
+	echo := ws.Next(t, "user")
+	if echo.Field("content") != "I have a math exam tomorrow" || echo.Field("session_id") == "" {
+		t.Errorf("user echo = %+v", echo.Data)
+	}
+	if reply := ws.Next(t, "bot"); reply.Field("content") != script.Reply {
+		t.Errorf("bot reply = %+v, want %q", reply.Data, script.Reply)
+	}
+
+	// 상태 정보가 저장된 뒤 종료해야 알람이 만들어짐
+	testutil.Eventually(t, "user status", func() bool {
+		statuses, err := server.Repos.Statuses.ListValid(userUUID, chatbotUUID, time.Now())
+		return err == nil && len(statuses) == 1
+	})
+
+	// stop은 /chat/stop과 같이 세션을 끝내고 종료 처리(알람 생성)를 시작
+	ws.Send(t, map[string]string{"type": "stop"})
+	var closeErr *websocket.CloseError
+	if err := ws.WaitClosed(t); !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure || closeErr.Text != "session stopped" {
+		t.Errorf("close = %v, want a normal closure", err)
+	}
+
+	sent := server.Push.WaitSent(t, 1)
+	if sent[0].UserUUID != userUUID || sent[0].Notification.ChatMessage != script.EnhancedAlarm {
+		t.Errorf("push = %+v %+v", sent[0], sent[0].Notification)
+	}
+	if streams := sessionStreams(t, server, userUUID, chatbotUUID); streams != -1 {
+		t.Errorf("session still registered with %d streams after stop", streams)
+	}
+	if status, _ := server.Do(t, http.MethodPost, "/chat/send", token, map[string]string{"chatbot_uuid": chatbotUUID, "message": "hi"}); status == http.StatusOK {
+		t.Error("sending to a stopped session should fail")
+	}
+}
+
+func TestChatWebSocketRelaysOnKeyboard(t *testing.T) {
+	server := testutil.NewServer(t, nil)
+	userUUID, token := server.SignUp(t, "bob", "password123")
+	chatbotUUID := createChatbot(t, server, token)
+
+	ws := server.OpenWebSocket(t, "/chat/ws?chatbot_uuid="+chatbotUUID, token)
+	testutil.Eventually(t, "websocket session", func() bool {
+		return sessionStreams(t, server, userUUID, chatbotUUID) == 1
+	})
+
+	// 같은 세션에 SSE로 함께 연결한 다른 기기에도 입력 중 이벤트가 전달됨
+	stream := server.OpenStream(t, "/chat/start?takeover=fanout&chatbot_uuid="+chatbotUUID, token)
+	ws.Send(t, map[string]string{"type": "onkeyboard"})
+
+	own := ws.Next(t, "onkeyboard")
+	other := stream.Next(t, "onkeyboard")
+	if own.Field("session_id") == "" || other.Field("session_id") != own.Field("session_id") {
+		t.Errorf("onkeyboard = %+v / %+v", own.Data, other.Data)
+	}
+}
+
+func TestChatWebSocketResumesAfterDisconnect(t *testing.T) {
+	script := testutil.DefaultScript()
+	server := testutil.NewServer(t, script.LLM())
+	userUUID, token := server.SignUp(t, "carol", "password123")
+	chatbotUUID := createChatbot(t, server, token)
+	path := "/chat/ws?chatbot_uuid=" + chatbotUUID
+
+	first := server.OpenWebSocket(t, path, token)
+	first.Send(t, map[string]string{"type": "user", "content": "hello"})
+	sessionID := first.Next(t, "user").Field("session_id")
+	first.Next(t, "bot")
+
+	// stop 없이 끊으면 세션은 재연결 유예 기간 동안 유지됨
+	first.Close()
+	testutil.Eventually(t, "detached session", func() bool {
+		return sessionStreams(t, server, userUUID, chatbotUUID) == 0
+	})
+	server.JSON(t, http.MethodPost, "/chat/send", token, map[string]string{"chatbot_uuid": chatbotUUID, "message": "are you there?"}, http.StatusOK, nil)
+
+	// 다시 연결하면 같은 세션에 붙고 끊긴 동안의 이벤트만 재전송됨
+	second := server.OpenWebSocket(t, path, token)
+	missed := second.Next(t, "user")
+	if missed.Field("content") != "are you there?" || missed.Field("session_id") != sessionID {
+		t.Errorf("replayed user event = %+v, want the message sent while away in session %s", missed.Data, sessionID)
+	}
+	if reply := second.Next(t, "bot"); reply.Field("content") != script.Reply {
+		t.Errorf("bot reply = %+v", reply.Data)
+	}
+	if streams := sessionStreams(t, server, userUUID, chatbotUUID); streams != 1 {
+		t.Errorf("streams = %d, want the resumed connection only", streams)
+	}
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"sermo-be/pkg/jwt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKS 공개키 목록 핸들러
+// @Summary JWKS 공개키 조회
+// @Description 다른 서비스가 액세스 토큰을 검증할 수 있도록 RS256/EdDSA 서명 키의 공개키를 JWK Set 형식으로 반환합니다. HS256 키는 노출되지 않습니다.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} jwt.JWKSet
+// @Router /.well-known/jwks.json [get]
+func JWKS(c *fiber.Ctx) error {
+	// 키 로테이션 반영을 위해 짧게 캐시
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(jwt.PublicJWKS())
+}
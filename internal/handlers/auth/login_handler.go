@@ -1,23 +1,27 @@
 package auth
 
 import (
+	"log"
 	"net/http"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/jwt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // DTO 정의
 type LoginRequest struct {
-	ID       string `json:"id"`
-	Password string `json:"password"`
+	ID         string `json:"id"`
+	Password   string `json:"password"`
+	DeviceInfo string `json:"device_info"` // 선택 (비워두면 User-Agent 사용)
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  struct {
+	Token        string `json:"token"`         // 액세스 토큰
+	RefreshToken string `json:"refresh_token"` // 리프레시 토큰 (1회용, 사용 시 교체됨)
+	ExpiresIn    int64  `json:"expires_in"`    // 액세스 토큰 만료까지 남은 초
+	User         struct {
 		UUID     string `json:"uuid"`
 		ID       string `json:"id"`
 		Username string `json:"username"`
@@ -26,7 +30,7 @@ type LoginResponse struct {
 
 // Login 로그인 핸들러
 // @Summary 로그인
-// @Description 사용자 인증 후 JWT 토큰을 반환합니다.
+// @Description 사용자 인증 후 액세스 토큰과 리프레시 토큰을 반환합니다.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -62,23 +66,36 @@ func Login(c *fiber.Ctx) error {
 		})
 	}
 
-	// 비밀번호 검증 (TODO: 실제로는 해시화된 비밀번호 비교)
-	if user.Password != req.Password {
+	// 비밀번호 검증
+	ok, needsRehash := user.CheckPassword(req.Password)
+	if !ok {
 		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Invalid credentials",
 		})
 	}
 
-	// JWT 토큰 생성
-	token, err := jwt.GenerateToken(user.UUID.String())
+	// 레거시 평문 비밀번호 또는 낮은 비용의 해시는 로그인 시 재해시하여 업그레이드
+	if needsRehash {
+		if err := user.SetPassword(req.Password); err != nil {
+			log.Printf("비밀번호 재해시 실패 - 사용자: %s, 에러: %v", user.UUID, err)
+		} else if err := db.Model(&user).Update("password", user.Password).Error; err != nil {
+			log.Printf("비밀번호 업그레이드 저장 실패 - 사용자: %s, 에러: %v", user.UUID, err)
+		}
+	}
+
+	// 액세스 토큰 + 리프레시 토큰 발급 (새 세션)
+	tokens, err := middleware.GetTokenService(c).IssueTokens(user.UUID.String(), deviceInfoFromRequest(c, req.DeviceInfo))
 	if err != nil {
+		log.Printf("토큰 발급 실패 - 사용자: %s, 에러: %v", user.UUID, err)
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate token",
 		})
 	}
 
 	response := LoginResponse{
-		Token: token,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int64(time.Until(tokens.AccessExpiresAt).Seconds()),
 		User: struct {
 			UUID     string `json:"uuid"`
 			ID       string `json:"id"`
@@ -92,3 +109,14 @@ func Login(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// deviceInfoFromRequest 요청에서 기기 정보 추출 (명시값 우선, 없으면 User-Agent)
+func deviceInfoFromRequest(c *fiber.Ctx, deviceInfo string) string {
+	if deviceInfo == "" {
+		deviceInfo = c.Get("User-Agent")
+	}
+	if len(deviceInfo) > 255 {
+		deviceInfo = deviceInfo[:255]
+	}
+	return deviceInfo
+}
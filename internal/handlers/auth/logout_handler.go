@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DTO 정의
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout 로그아웃 핸들러
+// @Summary 로그아웃
+// @Description 리프레시 토큰이 속한 세션을 폐기합니다. 해당 세션으로 발급된 액세스 토큰도 즉시 거부됩니다.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body LogoutRequest true "리프레시 토큰"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/logout [post]
+func Logout(c *fiber.Ctx) error {
+	var req LogoutRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.RefreshToken == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "refresh_token is required",
+		})
+	}
+
+	// 세션 폐기
+	if err := middleware.GetTokenService(c).RevokeByRefreshToken(req.RefreshToken); err != nil {
+		if errors.Is(err, token.ErrInvalidRefreshToken) {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid refresh token",
+			})
+		}
+
+		log.Printf("로그아웃 실패: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to logout",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Logged out successfully",
+	})
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DTO 정의
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	DeviceInfo   string `json:"device_info"` // 선택 (비워두면 기존 값 유지)
+}
+
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Refresh 토큰 갱신 핸들러
+// @Summary 토큰 갱신
+// @Description 리프레시 토큰으로 새 액세스 토큰과 리프레시 토큰을 발급합니다. 사용된 리프레시 토큰은 폐기되며, 폐기된 토큰이 다시 사용되면 해당 세션 전체가 폐기됩니다.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "리프레시 토큰"
+// @Success 200 {object} RefreshResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/refresh [post]
+func Refresh(c *fiber.Ctx) error {
+	var req RefreshRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.RefreshToken == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "refresh_token is required",
+		})
+	}
+
+	// 리프레시 토큰 회전
+	deviceInfo := req.DeviceInfo
+	if deviceInfo != "" {
+		deviceInfo = deviceInfoFromRequest(c, deviceInfo)
+	}
+
+	tokens, err := middleware.GetTokenService(c).RotateTokens(req.RefreshToken, deviceInfo)
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidRefreshToken) || errors.Is(err, token.ErrRefreshTokenReused) {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired refresh token",
+			})
+		}
+
+		log.Printf("토큰 갱신 실패: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh token",
+		})
+	}
+
+	response := RefreshResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int64(time.Until(tokens.AccessExpiresAt).Seconds()),
+	}
+
+	return c.JSON(response)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
+	"sermo-be/pkg/password"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -59,6 +60,12 @@ func SignUp(c *fiber.Ctx) error {
 		})
 	}
 
+	if len(req.Password) > password.MaxLength {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password must be at most 72 bytes",
+		})
+	}
+
 	// context에서 database 가져오기
 	db := middleware.GetDB(c)
 
@@ -70,8 +77,13 @@ func SignUp(c *fiber.Ctx) error {
 		})
 	}
 
-	// 새 사용자 생성
-	user := models.NewUser(req.ID, req.Nickname, req.Password)
+	// 새 사용자 생성 (비밀번호 해시)
+	user, err := models.NewUser(req.ID, req.Nickname, req.Password)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create user",
+		})
+	}
 
 	// 데이터베이스에 사용자 저장
 	if err := db.Create(user).Error; err != nil {
@@ -2,9 +2,10 @@ package bookmark
 
 import (
 	"net/http"
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/openai"
+	"sermo-be/pkg/llm"
 	"sermo-be/pkg/prompt"
 
 	"github.com/gofiber/fiber/v2"
@@ -24,7 +25,7 @@ type CreateSentenceBookmarkResponse struct {
 
 // CreateSentenceBookmark 문장 북마크 생성 (인증 필요)
 // @Summary 문장 북마크 생성
-// @Description 새로운 문장 북마크를 생성합니다. OpenAI를 사용하여 자동으로 한글 뜻을 추출합니다. 문장은 1-1000자까지 입력 가능합니다.
+// @Description 새로운 문장 북마크를 생성합니다. 언어 모델을 사용하여 자동으로 한글 뜻을 추출합니다. 문장은 1-1000자까지 입력 가능합니다.
 // @Tags Bookmark
 // @Accept json
 // @Produce json
@@ -33,7 +34,8 @@ type CreateSentenceBookmarkResponse struct {
 // @Success 201 {object} CreateSentenceBookmarkResponse "북마크 생성 성공"
 // @Failure 400 {object} map[string]interface{} "잘못된 요청 (문장 길이 제한 등)"
 // @Failure 401 {object} map[string]interface{} "인증 실패"
-// @Failure 500 {object} map[string]interface{} "OpenAI API 오류 또는 북마크 생성 실패"
+// @Failure 429 {object} map[string]interface{} "AI 사용 한도 초과"
+// @Failure 500 {object} map[string]interface{} "언어 모델 오류 또는 북마크 생성 실패"
 // @Router /bookmark/sentence [post]
 func CreateSentenceBookmark(c *fiber.Ctx) error {
 	// context에서 사용자 UUID 가져오기
@@ -65,30 +67,31 @@ func CreateSentenceBookmark(c *fiber.Ctx) error {
 		})
 	}
 
-	// OpenAI를 사용하여 한글 뜻 추출
-	openaiClient := middleware.GetOpenAIClient(c)
-	if openaiClient == nil {
+	// 언어 모델을 사용하여 한글 뜻 추출
+	model := middleware.GetLLM(c, llm.FeatureBookmark)
+	if model == nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "OpenAI service unavailable",
+			"error": "LLM service unavailable",
 		})
 	}
 
 	meaningPrompt := prompt.GetSentenceBookmarkMeaningPrompt()
 
-	// OpenAI 채팅 완성 API 호출
-	messages := []openai.ChatMessage{
+	// 채팅 완성 API 호출
+	messages := []llm.ChatMessage{
 		{
 			Role:    "user",
 			Content: meaningPrompt + "\n\n문장: " + req.Sentence,
 		},
 	}
 
-	chatResp, err := openaiClient.ChatCompletion(c.Context(), messages)
+	chatResp, err := model.ChatCompletion(c.UserContext(), messages)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate meaning using OpenAI",
+			"error": "Failed to generate meaning",
 		})
 	}
+	middleware.GetUsageService(c).RecordChat(userUUID.String(), usage.FeatureBookmark, model, chatResp)
 
 	meaning := chatResp.Message.Content
 
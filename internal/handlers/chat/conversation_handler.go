@@ -0,0 +1,157 @@
+package chat
+
+import (
+	"errors"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ConversationResponse 대화 목록 항목 응답 DTO
+type ConversationResponse struct {
+	ChatbotUUID string               `json:"chatbot_uuid"`
+	Name        string               `json:"name"`
+	ImageID     string               `json:"image_id"`
+	LastMessage *LastMessageResponse `json:"last_message"` // 대화가 없으면 null
+	UnreadCount int64                `json:"unread_count"`
+	Live        bool                 `json:"live"` // 채팅 세션이 열려 있는지
+}
+
+// LastMessageResponse 대화의 마지막 메시지 응답 DTO
+type LastMessageResponse struct {
+	UUID        string `json:"uuid"`
+	MessageType string `json:"message_type"`
+	Content     string `json:"content"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// MarkReadRequest 읽음 처리 요청 DTO
+type MarkReadRequest struct {
+	ChatbotUUID string `json:"chatbot_uuid" validate:"required"`
+	MessageUUID string `json:"message_uuid,omitempty"` // 비어 있으면 마지막 메시지까지 읽음 처리
+}
+
+// MarkReadResponse 읽음 처리 응답 DTO
+type MarkReadResponse struct {
+	LastReadMessageUUID string `json:"last_read_message_uuid,omitempty"`
+	LastReadAt          string `json:"last_read_at,omitempty"`
+	UnreadCount         int64  `json:"unread_count"`
+}
+
+// GetConversations 대화 목록 조회
+// @Summary 대화 목록 조회
+// @Description 사용자의 채팅봇별 마지막 메시지, 읽지 않은 채팅봇 메시지 수, 채팅 세션 연결 여부를 최근 대화 순으로 조회합니다
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} ConversationResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /chat/conversations [get]
+func GetConversations(c *fiber.Ctx) error {
+	// 사용자 UUID 가져오기
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	conversations, err := chat.GetServices(c).Messages.ListConversations(userUUID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	sseManager := middleware.GetSSEManager(c)
+
+	responses := make([]ConversationResponse, 0, len(conversations))
+	for _, conversation := range conversations {
+		response := ConversationResponse{
+			ChatbotUUID: conversation.ChatbotUUID,
+			Name:        conversation.Name,
+			ImageID:     conversation.ImageID,
+			UnreadCount: conversation.UnreadCount,
+		}
+
+		if conversation.LastMessageUUID != nil {
+			response.LastMessage = &LastMessageResponse{
+				UUID:        *conversation.LastMessageUUID,
+				MessageType: *conversation.LastMessageType,
+				Content:     *conversation.LastMessageContent,
+				CreatedAt:   conversation.LastMessageAt.Format(time.RFC3339),
+			}
+		}
+
+		// 다른 인스턴스가 가진 세션 포함 (레지스트리 조회 실패 시 연결 안 됨으로 표시)
+		if info, err := sseManager.FindSession(userUUID, conversation.ChatbotUUID); err == nil && info != nil {
+			response.Live = true
+		}
+
+		responses = append(responses, response)
+	}
+
+	return c.JSON(responses)
+}
+
+// MarkRead 읽음 처리
+// @Summary 읽음 처리
+// @Description 채팅봇과의 대화에서 읽은 위치를 지정한 메시지(생략 시 마지막 메시지)까지 이동합니다. 읽은 위치는 앞으로만 이동합니다.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MarkReadRequest true "읽음 처리 요청"
+// @Success 200 {object} MarkReadResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /chat/read [post]
+func MarkRead(c *fiber.Ctx) error {
+	// 사용자 UUID 가져오기
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	// 요청 파싱
+	var req MarkReadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	if req.ChatbotUUID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "chatbot_uuid is required"})
+	}
+	if _, err := uuid.Parse(req.ChatbotUUID); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid chatbot_uuid"})
+	}
+	if req.MessageUUID != "" {
+		if _, err := uuid.Parse(req.MessageUUID); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid message_uuid"})
+		}
+	}
+
+	messageService := chat.GetServices(c).Messages
+	cursor, err := messageService.MarkRead(userUUID, req.ChatbotUUID, req.MessageUUID)
+	if errors.Is(err, chat.ErrMessageNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "Message not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	unread, err := messageService.GetUnreadCount(userUUID, req.ChatbotUUID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := MarkReadResponse{UnreadCount: unread}
+	if cursor != nil {
+		response.LastReadMessageUUID = cursor.LastReadMessageUUID.String()
+		response.LastReadAt = cursor.LastReadAt.Format(time.RFC3339)
+	}
+	return c.JSON(response)
+}
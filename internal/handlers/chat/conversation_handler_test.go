@@ -0,0 +1,35 @@
+package chat
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMarkReadRejectsInvalidRequest(t *testing.T) {
+	app := fiber.New()
+	app.Post("/chat/read", func(c *fiber.Ctx) error {
+		c.Locals("user_uuid", "user")
+		return c.Next()
+	}, MarkRead)
+
+	cases := map[string]string{
+		"chatbot_uuid 없음":  `{}`,
+		"잘못된 chatbot_uuid": `{"chatbot_uuid":"bot"}`,
+		"잘못된 message_uuid": `{"chatbot_uuid":"0f8fad5b-d9cb-469f-a165-70867728950e","message_uuid":"msg"}`,
+	}
+	for name, body := range cases {
+		req := httptest.NewRequest("POST", "/chat/read", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: 요청 실패: %v", name, err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("%s: 상태 코드 = %d, 기대값 400", name, resp.StatusCode)
+		}
+	}
+}
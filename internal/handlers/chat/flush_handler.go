@@ -0,0 +1,75 @@
+package chat
+
+import (
+	"log"
+
+	"sermo-be/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FlushRequest 즉시 응답 요청 구조
+type FlushRequest struct {
+	ChatbotUUID string `json:"chatbot_uuid"`
+}
+
+// Flush 즉시 응답 요청
+// @Summary 즉시 응답 요청
+// @Description 응답 대기 시간을 기다리지 않고 지금까지 보낸 메시지로 바로 봇 응답을 생성하도록 요청합니다
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FlushRequest true "즉시 응답 요청"
+// @Success 200 {object} map[string]interface{} "이벤트 전송 성공"
+// @Failure 400 {object} map[string]interface{} "잘못된 요청"
+// @Failure 401 {object} map[string]interface{} "인증 실패"
+// @Failure 404 {object} map[string]interface{} "세션 없음"
+// @Failure 429 {object} map[string]interface{} "AI 사용 한도 초과"
+// @Failure 500 {object} map[string]interface{} "서버 오류"
+// @Router /chat/flush [post]
+func Flush(c *fiber.Ctx) error {
+	// 사용자 UUID 가져오기
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	// 요청 바디 파싱
+	var request FlushRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	// chatbot_uuid 검증
+	if request.ChatbotUUID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "chatbot_uuid is required"})
+	}
+
+	// SSE 매니저 가져오기
+	sseManager := middleware.GetSSEManager(c)
+
+	// 해당 사용자와 채팅봇의 활성 세션 찾기
+	session, err := sseManager.FindSession(userUUID, request.ChatbotUUID)
+	if err != nil {
+		return c.Status(503).JSON(fiber.Map{"error": err.Error()})
+	}
+	if session == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Active session not found"})
+	}
+
+	// flush 이벤트 생성 (SSE 형식)
+	sseMessage, err := newFlushFrame(session.SessionID)
+	if err != nil {
+		log.Printf("flush 이벤트 직렬화 실패: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create event"})
+	}
+
+	// 세션으로 이벤트 전송 (다른 인스턴스 소유 세션이면 버스로 전달)
+	if err := sseManager.SendMessage(session.SessionID, sseMessage); err != nil {
+		log.Printf("flush 이벤트 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to send event to session"})
+	}
+
+	return c.JSON(fiber.Map{"success": true, "message": "Event sent successfully"})
+}
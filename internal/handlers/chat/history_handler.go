@@ -1,30 +1,57 @@
 package chat
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"sermo-be/internal/core/chat"
 	"sermo-be/internal/middleware"
+	"sermo-be/internal/models"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
+// HistoryFilter 히스토리 조회와 검색에 공통으로 쓰는 필터와 커서
+type HistoryFilter struct {
+	SessionID string `json:"session_id,omitempty"`
+	From      string `json:"from,omitempty"`   // RFC3339, 이 시각 이후 (포함)
+	To        string `json:"to,omitempty"`     // RFC3339, 이 시각 이전 (미포함)
+	Before    string `json:"before,omitempty"` // 이 메시지 UUID보다 이전 메시지 조회
+	After     string `json:"after,omitempty"`  // 이 메시지 UUID보다 이후 메시지 조회
+	Limit     int    `json:"limit" validate:"min=1,max=100"`
+}
+
 // ChatHistoryRequest 채팅 히스토리 요청 DTO
 type ChatHistoryRequest struct {
 	ChatbotUUID string `json:"chatbot_uuid" validate:"required"`
-	Limit       int    `json:"limit" validate:"min=1,max=100"`
-	Offset      int    `json:"offset" validate:"min=0"`
+	HistoryFilter
+}
+
+// ChatSearchRequest 채팅 메시지 검색 요청 DTO
+type ChatSearchRequest struct {
+	ChatbotUUID string `json:"chatbot_uuid,omitempty"` // 비어 있으면 모든 채팅봇 대상
+	Query       string `json:"query" validate:"required"`
+	HistoryFilter
 }
 
-// ChatHistoryResponse 채팅 히스토리 응답 DTO
+// ChatHistoryResponse 채팅 히스토리 응답 DTO (메시지는 오래된 순)
 type ChatHistoryResponse struct {
-	Messages []ChatMessageResponse `json:"messages"`
-	Total    int64                 `json:"total"`
+	Messages      []ChatMessageResponse `json:"messages"`
+	Total         int64                 `json:"total"`
+	HasMoreBefore bool                  `json:"has_more_before"`
+	HasMoreAfter  bool                  `json:"has_more_after"`
+	BeforeCursor  string                `json:"before_cursor,omitempty"` // 이전 페이지 조회 시 before로 전달
+	AfterCursor   string                `json:"after_cursor,omitempty"`  // 이후 페이지 조회 시 after로 전달
 }
 
 // ChatMessageResponse 채팅 메시지 응답 DTO
 type ChatMessageResponse struct {
 	UUID        string `json:"uuid"`
+	SessionID   string `json:"session_id"`
+	ChatbotUUID string `json:"chatbot_uuid"`
 	MessageType string `json:"message_type"`
 	Content     string `json:"content"`
 	CreatedAt   string `json:"created_at"`
@@ -32,7 +59,7 @@ type ChatMessageResponse struct {
 
 // GetChatHistory 채팅 히스토리 조회
 // @Summary 채팅 히스토리 조회
-// @Description 특정 채팅봇과의 대화 히스토리를 조회합니다
+// @Description 특정 채팅봇과의 대화 히스토리를 조회합니다. 커서가 없으면 가장 최근 메시지부터 limit개를 반환하며, before/after에 메시지 UUID를 넣어 이전/이후 페이지를 조회합니다. session_id, from, to(RFC3339)로 범위를 좁힐 수 있습니다.
 // @Tags Chat
 // @Accept json
 // @Produce json
@@ -41,6 +68,7 @@ type ChatMessageResponse struct {
 // @Success 200 {object} ChatHistoryResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /chat/history [post]
 func GetChatHistory(c *fiber.Ctx) error {
@@ -56,39 +84,164 @@ func GetChatHistory(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
 	}
 
-	// 기본값 설정
-	if req.Limit == 0 {
-		req.Limit = 50
+	if req.ChatbotUUID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "chatbot_uuid is required"})
+	}
+	if _, err := uuid.Parse(req.ChatbotUUID); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid chatbot_uuid"})
 	}
 
-	// MessageService를 사용하여 대화 히스토리 조회
-	messageService := chat.GetMessageService()
-	messages, err := messageService.GetChatHistory(userUUID, req.ChatbotUUID, req.Limit)
+	query, err := req.HistoryFilter.toQuery(userUUID, req.ChatbotUUID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return respondHistory(c, query)
+}
+
+// SearchChatHistory 채팅 메시지 검색
+// @Summary 채팅 메시지 검색
+// @Description 대화 메시지 본문을 전문 검색합니다. chatbot_uuid를 생략하면 모든 채팅봇과의 대화에서 검색하며, 히스토리 조회와 같은 필터와 커서를 사용합니다.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChatSearchRequest true "검색 요청"
+// @Success 200 {object} ChatHistoryResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /chat/search [post]
+func SearchChatHistory(c *fiber.Ctx) error {
+	// 사용자 UUID 가져오기
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	// 요청 파싱
+	var req ChatSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "query is required"})
+	}
+	if req.ChatbotUUID != "" {
+		if _, err := uuid.Parse(req.ChatbotUUID); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid chatbot_uuid"})
+		}
 	}
 
-	// 총 메시지 수 조회
-	total, err := messageService.GetChatHistoryCount(userUUID, req.ChatbotUUID)
+	query, err := req.HistoryFilter.toQuery(userUUID, req.ChatbotUUID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	query.Search = req.Query
+
+	return respondHistory(c, query)
+}
+
+// toQuery 요청 필터 검증 후 조회 조건으로 변환
+func (f HistoryFilter) toQuery(userUUID, chatbotUUID string) (chat.HistoryQuery, error) {
+	query := chat.HistoryQuery{
+		UserUUID:    userUUID,
+		ChatbotUUID: chatbotUUID,
+		SessionID:   f.SessionID,
+		Limit:       f.Limit,
+	}
+
+	// 기본값 설정
+	if query.Limit == 0 {
+		query.Limit = chat.DefaultHistoryLimit
+	}
+	if query.Limit < 0 || query.Limit > chat.MaxHistoryLimit {
+		return query, fmt.Errorf("limit must be between 1 and %d", chat.MaxHistoryLimit)
+	}
+
+	if f.Before != "" && f.After != "" {
+		return query, errors.New("before and after cannot be used together")
+	}
+	for name, cursor := range map[string]string{"before": f.Before, "after": f.After} {
+		if cursor == "" {
+			continue
+		}
+		if _, err := uuid.Parse(cursor); err != nil {
+			return query, fmt.Errorf("invalid %s cursor", name)
+		}
+	}
+	query.Before, query.After = f.Before, f.After
+
+	for name, value := range map[string]string{"from": f.From, "to": f.To} {
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+		}
+		if name == "from" {
+			query.From = &t
+		} else {
+			query.To = &t
+		}
 	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, errors.New("from must be earlier than to")
+	}
+
+	return query, nil
+}
 
-	// 응답 변환
-	var messageResponses []ChatMessageResponse
-	for _, msg := range messages {
-		messageResponses = append(messageResponses, ChatMessageResponse{
-			UUID:        msg.UUID.String(),
-			MessageType: string(msg.MessageType),
-			Content:     msg.Content,
-			CreatedAt:   msg.CreatedAt.Format(time.RFC3339),
-		})
+// respondHistory 조회 조건으로 메시지를 조회해 응답
+func respondHistory(c *fiber.Ctx, query chat.HistoryQuery) error {
+	page, err := chat.GetServices(c).Messages.QueryHistory(query)
+	if errors.Is(err, chat.ErrCursorNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "Cursor message not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	return c.JSON(newHistoryResponse(page))
+}
+
+// newHistoryResponse 조회 결과를 응답 DTO로 변환
+func newHistoryResponse(page *chat.HistoryPage) ChatHistoryResponse {
 	response := ChatHistoryResponse{
-		Messages: messageResponses,
-		Total:    total,
+		Messages:      make([]ChatMessageResponse, 0, len(page.Messages)),
+		Total:         page.Total,
+		HasMoreBefore: page.HasMoreBefore,
+		HasMoreAfter:  page.HasMoreAfter,
 	}
 
-	return c.JSON(response)
+	for _, msg := range page.Messages {
+		response.Messages = append(response.Messages, newMessageResponse(msg))
+	}
+
+	if n := len(page.Messages); n > 0 {
+		if page.HasMoreBefore {
+			response.BeforeCursor = page.Messages[0].UUID.String()
+		}
+		if page.HasMoreAfter {
+			response.AfterCursor = page.Messages[n-1].UUID.String()
+		}
+	}
+
+	return response
+}
+
+// newMessageResponse 메시지 응답 변환
+func newMessageResponse(msg models.ChatMessage) ChatMessageResponse {
+	return ChatMessageResponse{
+		UUID:        msg.UUID.String(),
+		SessionID:   msg.SessionID,
+		ChatbotUUID: msg.ChatbotUUID,
+		MessageType: string(msg.MessageType),
+		Content:     msg.Content,
+		CreatedAt:   msg.CreatedAt.Format(time.RFC3339),
+	}
 }
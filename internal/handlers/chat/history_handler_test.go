@@ -0,0 +1,54 @@
+package chat
+
+import (
+	"testing"
+
+	"sermo-be/internal/core/chat"
+)
+
+func TestHistoryFilterToQuery(t *testing.T) {
+	cursor := "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+	query, err := HistoryFilter{}.toQuery("user", "bot")
+	if err != nil {
+		t.Fatalf("빈 필터 변환 실패: %v", err)
+	}
+	if query.Limit != chat.DefaultHistoryLimit || query.From != nil || query.To != nil {
+		t.Fatalf("기본값이 적용되지 않음: %+v", query)
+	}
+
+	query, err = HistoryFilter{
+		SessionID: "session",
+		From:      "2026-01-01T00:00:00Z",
+		To:        "2026-01-02T00:00:00+09:00",
+		Before:    cursor,
+		Limit:     20,
+	}.toQuery("user", "bot")
+	if err != nil {
+		t.Fatalf("필터 변환 실패: %v", err)
+	}
+	if query.SessionID != "session" || query.Before != cursor || query.Limit != 20 {
+		t.Fatalf("필터가 그대로 전달되지 않음: %+v", query)
+	}
+	if query.From == nil || query.To == nil || !query.From.Before(*query.To) {
+		t.Fatalf("기간이 파싱되지 않음: %+v", query)
+	}
+}
+
+func TestHistoryFilterToQueryRejectsInvalid(t *testing.T) {
+	cursor := "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+	cases := map[string]HistoryFilter{
+		"limit 초과":      {Limit: chat.MaxHistoryLimit + 1},
+		"음수 limit":      {Limit: -1},
+		"before와 after": {Before: cursor, After: cursor},
+		"잘못된 커서":        {After: "not-a-uuid"},
+		"잘못된 시각":        {From: "2026-01-01"},
+		"from이 to보다 늦음": {From: "2026-01-02T00:00:00Z", To: "2026-01-01T00:00:00Z"},
+	}
+	for name, filter := range cases {
+		if _, err := filter.toQuery("user", "bot"); err == nil {
+			t.Errorf("%s: 에러가 반환되지 않음", name)
+		}
+	}
+}
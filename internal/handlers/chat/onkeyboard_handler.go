@@ -1,9 +1,7 @@
 package chat
 
 import (
-	"encoding/json"
 	"log"
-	"time"
 
 	"sermo-be/internal/middleware"
 
@@ -47,38 +45,30 @@ func OnKeyboard(c *fiber.Ctx) error {
 	}
 
 	// SSE 매니저 가져오기
-	sseManager := middleware.GetSSEManager()
+	sseManager := middleware.GetSSEManager(c)
 
 	// 해당 사용자와 채팅봇의 활성 세션 찾기
-	session := sseManager.FindSessionByUserAndChatbot(userUUID, request.ChatbotUUID)
+	session, err := sseManager.FindSession(userUUID, request.ChatbotUUID)
+	if err != nil {
+		return c.Status(503).JSON(fiber.Map{"error": err.Error()})
+	}
 	if session == nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Active session not found"})
 	}
 
-	// onkeyboard 이벤트 생성
-	onKeyboardEvent := map[string]interface{}{
-		"type":       "onkeyboard",
-		"session_id": session.SessionID,
-		"timestamp":  time.Now().Format(time.RFC3339),
-	}
-
-	// JSON으로 직렬화
-	eventData, err := json.Marshal(onKeyboardEvent)
+	// onkeyboard 이벤트 생성 (SSE 형식)
+	sseMessage, err := newOnKeyboardFrame(session.SessionID)
 	if err != nil {
 		log.Printf("onkeyboard 이벤트 직렬화 실패: %v", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create event"})
 	}
 
-	// SSE 형식으로 변환
-	sseMessage := "data: " + string(eventData) + "\n\n"
-
-	// 세션 채널로 이벤트 전송
-	select {
-	case session.Channel <- sseMessage:
-		log.Printf("onkeyboard 이벤트 전송 성공 - 세션: %s", session.SessionID)
-		return c.JSON(fiber.Map{"success": true, "message": "Event sent successfully"})
-	default:
-		log.Printf("세션 채널이 가득 참 - 세션: %s", session.SessionID)
-		return c.Status(500).JSON(fiber.Map{"error": "Session channel is full"})
+	// 세션으로 이벤트 전송 (다른 인스턴스 소유 세션이면 버스로 전달)
+	if err := sseManager.SendMessage(session.SessionID, sseMessage); err != nil {
+		log.Printf("onkeyboard 이벤트 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to send event to session"})
 	}
+
+	log.Printf("onkeyboard 이벤트 전송 성공 - 세션: %s", session.SessionID)
+	return c.JSON(fiber.Map{"success": true, "message": "Event sent successfully"})
 }
@@ -0,0 +1,101 @@
+package chat
+
+import (
+	"bufio"
+	"log"
+	"time"
+
+	"sermo-be/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// queueKeepaliveInterval 순번이 바뀌지 않아도 queued 이벤트를 다시 보내는 주기 (연결 끊김 감지용)
+const queueKeepaliveInterval = 15 * time.Second
+
+// QueuedEvent 대기열 순번 이벤트
+type QueuedEvent struct {
+	Type        string `json:"type"`         // queued
+	Position    int    `json:"position"`     // 대기 순번 (1이면 다음 차례)
+	QueueLength int    `json:"queue_length"` // 전체 대기 인원
+	Timestamp   string `json:"timestamp"`
+}
+
+// writeFrame SSE 프레임 하나를 쓰고 바로 전송
+func writeFrame(w *bufio.Writer, event interface{}) error {
+	frame, err := newSSEFrame(event)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(frame)); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// waitInQueue 자리가 날 때까지 queued 이벤트로 순번을 알리며 대기한 뒤 세션 생성
+// 대기 시간 초과, 연결 끊김, 세션 생성 실패 시에는 대기열에서 빠지고 nil을 반환한다.
+func waitInQueue(w *bufio.Writer, sseManager *middleware.SSEManager, ticket *middleware.QueueTicket) *middleware.SSESession {
+	timeout := time.NewTimer(sseManager.Limits().QueueTimeout)
+	defer timeout.Stop()
+
+	keepalive := time.NewTicker(queueKeepaliveInterval)
+	defer keepalive.Stop()
+
+	lastPosition := -1
+	for {
+		position, admitted := sseManager.QueuePosition(ticket)
+		if admitted {
+			session, err := sseManager.CreateQueuedSession(ticket)
+			if err != nil {
+				log.Printf("대기열 입장 후 세션 생성 실패 - 사용자: %s, 에러: %v", ticket.UserUUID, err)
+				writeFrame(w, fiber.Map{"type": "error", "error": err.Error()})
+				return nil
+			}
+			log.Printf("대기열 입장 - 세션: %s, 대기 시간: %s", session.SessionID, time.Since(ticket.EnqueuedAt).Round(time.Second))
+			return session
+		}
+		if position == 0 {
+			// 서버 종료 등으로 대기열에서 제외됨
+			writeFrame(w, fiber.Map{"type": "error", "error": middleware.ErrSessionsFull.Error()})
+			return nil
+		}
+
+		if position != lastPosition {
+			lastPosition = position
+			if err := writeQueuedEvent(w, sseManager, position); err != nil {
+				log.Printf("대기열 이벤트 전송 실패 - 사용자: %s, 에러: %v", ticket.UserUUID, err)
+				sseManager.LeaveQueue(ticket)
+				return nil
+			}
+		}
+
+		select {
+		case <-ticket.Notify():
+			// 순번 변경 또는 입장 가능
+
+		case <-keepalive.C:
+			if err := writeQueuedEvent(w, sseManager, position); err != nil {
+				log.Printf("대기열 이벤트 전송 실패 - 사용자: %s, 에러: %v", ticket.UserUUID, err)
+				sseManager.LeaveQueue(ticket)
+				return nil
+			}
+
+		case <-timeout.C:
+			log.Printf("대기열 대기 시간 초과 - 사용자: %s", ticket.UserUUID)
+			sseManager.LeaveQueue(ticket)
+			writeFrame(w, fiber.Map{"type": "queue_timeout", "timestamp": time.Now().Format(time.RFC3339)})
+			return nil
+		}
+	}
+}
+
+// writeQueuedEvent 현재 순번으로 queued 이벤트 전송
+func writeQueuedEvent(w *bufio.Writer, sseManager *middleware.SSEManager, position int) error {
+	return writeFrame(w, QueuedEvent{
+		Type:        "queued",
+		Position:    position,
+		QueueLength: sseManager.QueueLength(),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	})
+}
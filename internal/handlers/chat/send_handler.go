@@ -1,8 +1,6 @@
 package chat
 
 import (
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"sermo-be/internal/core/chat"
@@ -44,6 +42,7 @@ type SSEMessage struct {
 // @Success 200 {object} SendMessageResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /chat/send [post]
 func SendMessage(c *fiber.Ctx) error {
@@ -60,41 +59,30 @@ func SendMessage(c *fiber.Ctx) error {
 	}
 
 	// SSE 매니저 가져오기
-	sseManager := middleware.GetSSEManager()
+	sseManager := middleware.GetSSEManager(c)
 
-	// 사용자의 활성 세션 찾기
-	userSessions := sseManager.GetUserSessions(userUUID)
-	var targetSession *middleware.SSESession
-
-	for _, session := range userSessions {
-		if session.ChatbotUUID == req.ChatbotUUID {
-			targetSession = session
-			break
-		}
+	// 사용자의 활성 세션 찾기 (다른 인스턴스가 SSE 스트림을 가진 세션 포함)
+	targetSession, err := sseManager.FindSession(userUUID, req.ChatbotUUID)
+	if err != nil {
+		return c.Status(503).JSON(fiber.Map{"error": err.Error()})
 	}
-
 	if targetSession == nil {
 		return c.Status(400).JSON(fiber.Map{"error": "No active session found"})
 	}
 
 	// 1. 먼저 사용자 메시지를 SSE로 전송
-	currentTime := time.Now()
-	userSSEMessage := SSEMessage{
-		Type:      "user",
-		Content:   req.Message,
-		Timestamp: currentTime.Format(time.RFC3339),
-		SessionID: targetSession.SessionID,
+	userMessageData, err := newUserFrame(targetSession.SessionID, req.Message)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create user message"})
 	}
-	userSSEData, _ := json.Marshal(userSSEMessage)
-	userMessageData := fmt.Sprintf("data: %s\n\n", string(userSSEData))
 
 	// SSE 세션에 사용자 메시지 전송
-	if err := sseManager.SendMessage(targetSession.SessionID, userMessageData); err != nil {
+	if err := sseManager.SendUserMessage(targetSession.SessionID, userMessageData); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to send user message via SSE"})
 	}
 
 	// 2. SSE 전송 성공 시에만 DB에 저장
-	messageService := chat.GetMessageService()
+	messageService := chat.GetServices(c).Messages
 	userChatMessage, err := messageService.CreateUserMessage(
 		targetSession.SessionID,
 		userUUID,
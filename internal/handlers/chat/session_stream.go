@@ -0,0 +1,246 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/middleware"
+	"sermo-be/pkg/llm"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// takeoverRetries 다른 인스턴스의 세션을 교체할 때 기존 세션 정리를 기다리며 재시도하는 횟수
+	takeoverRetries = 10
+	// takeoverRetryInterval 재시도 간격
+	takeoverRetryInterval = 100 * time.Millisecond
+)
+
+// errRemoteFanout 다른 인스턴스가 가진 세션에는 함께 연결할 수 없음 (스트림은 세션을 가진 인스턴스에만 존재)
+var errRemoteFanout = fiber.NewError(fiber.StatusConflict, "Session is active on another server instance; use takeover=replace to move it here")
+
+// frameJSON SSE 프레임("data: {json}\n\n")에서 JSON 본문만 추출
+func frameJSON(message string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(message, "data: "), "\n\n")
+}
+
+// newSSEFrame 이벤트를 세션 채널에 넣을 SSE 프레임으로 직렬화
+func newSSEFrame(event interface{}) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data: %s\n\n", string(data)), nil
+}
+
+// newUserFrame 사용자 메시지 SSE 프레임 생성
+func newUserFrame(sessionID, content string) (string, error) {
+	return newSSEFrame(SSEMessage{
+		Type:      "user",
+		Content:   content,
+		Timestamp: time.Now().Format(time.RFC3339),
+		SessionID: sessionID,
+	})
+}
+
+// newOnKeyboardFrame onkeyboard 이벤트 SSE 프레임 생성
+func newOnKeyboardFrame(sessionID string) (string, error) {
+	return newSSEFrame(map[string]interface{}{
+		"type":       "onkeyboard",
+		"session_id": sessionID,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	})
+}
+
+// newFlushFrame 즉시 응답 요청(flush) 이벤트 SSE 프레임 생성
+func newFlushFrame(sessionID string) (string, error) {
+	return newSSEFrame(map[string]interface{}{
+		"type":       "flush",
+		"session_id": sessionID,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	})
+}
+
+// routeToBot 세션 채널에서 꺼낸 프레임 중 봇이 처리할 이벤트(user, onkeyboard, flush)를 봇 채널로 전달
+// SSE/WebSocket 전송 방식과 무관하게 같은 규칙으로 BotGoroutine에 이벤트를 넘긴다.
+func routeToBot(sessionID, message string, botChannel chan string) {
+	var baseMessage struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(frameJSON(message)), &baseMessage); err != nil {
+		log.Printf("메시지 타입 파싱 실패: %v", err)
+		return
+	}
+
+	switch baseMessage.Type {
+	case "user", "onkeyboard", "flush":
+		log.Printf("%s 이벤트를 봇 채널로 전달 - 세션: %s", baseMessage.Type, sessionID)
+		select {
+		case botChannel <- message:
+			// 전달 성공
+		default:
+			log.Printf("봇 채널이 가득 참 - 세션: %s", sessionID)
+		}
+	case "bot_typing":
+		// 타이핑 이벤트는 클라이언트에만 전송 (봇 채널로 전달하지 않음)
+		log.Printf("봇 타이핑 이벤트 클라이언트 전송 - 세션: %s", sessionID)
+	}
+}
+
+// errorStatus 에러에 담긴 HTTP 상태 코드 (fiber.Error가 아니면 fallback)
+func errorStatus(err error, fallback int) int {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fallback
+}
+
+// sessionRequest 채팅 세션 연결 요청
+type sessionRequest struct {
+	UserUUID      string
+	ChatbotUUID   string
+	Resume        middleware.ResumeRequest
+	StreamReplies bool
+	LLMRouter     *llm.Router
+	Bot           *chat.BotGoroutine
+	AllowQueue    bool // 자리가 없으면 대기열에 넣을지 (false면 ErrSessionsFull 반환)
+}
+
+// openedSession 연결된 세션 (대기열에 들어간 경우 Ticket만 설정됨)
+type openedSession struct {
+	Session      *middleware.SSESession
+	Subscription *middleware.Subscription
+	Replay       []middleware.SSEEvent
+	Ticket       *middleware.QueueTicket
+}
+
+// openChatSession 기존 세션에 연결하거나 새 세션 시작 (SSE/WebSocket 공통)
+// 같은 사용자-채팅봇 세션이 있으면 takeover 방식에 따라 거절, 교체, 함께 연결하고,
+// 다른 인스턴스가 가진 세션은 교체(replace)만 가능하다. 스트림과 재전송 버퍼는 세션을 가진 인스턴스에만 있으므로
+// fanout은 거절하고(errRemoteFanout), replace는 기존 세션을 종료한 뒤 새로 시작해 놓친 이벤트를 재전송하지 못한다.
+func openChatSession(sseManager *middleware.SSEManager, req sessionRequest) (*openedSession, error) {
+	session, sub, replay, err := sseManager.ResumeSession(req.UserUUID, req.ChatbotUUID, req.Resume)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil {
+		log.Printf("기존 세션에 스트림 연결 - 세션: %s, 방식: %s, 연결된 스트림: %d개, 재전송 이벤트: %d개",
+			session.SessionID, req.Resume.Mode, session.StreamCount(), len(replay))
+		return &openedSession{Session: session, Subscription: sub, Replay: replay}, nil
+	}
+
+	session, err = sseManager.CreateSession(req.UserUUID, req.ChatbotUUID)
+	if errors.Is(err, middleware.ErrSessionConflict) {
+		switch req.Resume.Mode {
+		case middleware.TakeoverReplace:
+			session, err = replaceRemoteSession(sseManager, req.UserUUID, req.ChatbotUUID)
+		case middleware.TakeoverFanout:
+			err = errRemoteFanout
+		}
+	}
+	if errors.Is(err, middleware.ErrSessionsFull) && req.AllowQueue {
+		ticket, err := sseManager.Enqueue(req.UserUUID, req.ChatbotUUID)
+		if err != nil {
+			return nil, err
+		}
+		return &openedSession{Ticket: ticket}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &openedSession{Session: session, Subscription: startChatSession(sseManager, session, req)}, nil
+}
+
+// replaceRemoteSession 다른 인스턴스가 가진 세션을 종료하고 이 인스턴스에서 새 세션 시작
+// 대화 내용은 DB에 남아 있으므로 새 세션에서 이어진다.
+func replaceRemoteSession(sseManager *middleware.SSEManager, userUUID, chatbotUUID string) (*middleware.SSESession, error) {
+	info, err := sseManager.FindSession(userUUID, chatbotUUID)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil {
+		log.Printf("다른 인스턴스의 세션 교체 - 세션: %s, 인스턴스: %s", info.SessionID, info.InstanceID)
+		if err := sseManager.StopSession(info.SessionID); err != nil {
+			log.Printf("기존 세션 종료 요청 실패 - 세션: %s, 에러: %v", info.SessionID, err)
+		}
+	}
+
+	// 종료 요청은 버스로 비동기 전달되므로 등록이 해제될 때까지 잠시 재시도
+	for i := 0; ; i++ {
+		session, err := sseManager.CreateSession(userUUID, chatbotUUID)
+		if !errors.Is(err, middleware.ErrSessionConflict) || i >= takeoverRetries {
+			return session, err
+		}
+		time.Sleep(takeoverRetryInterval)
+	}
+}
+
+// startChatSession 새로 만든 세션에 첫 스트림을 연결하고 세션 고루틴(이벤트 전달, 봇) 시작
+func startChatSession(sseManager *middleware.SSEManager, session *middleware.SSESession, req sessionRequest) *middleware.Subscription {
+	sub := sseManager.AttachStream(session)
+	session.StreamReplies = req.StreamReplies
+	startSessionPump(session)
+	req.Bot.StartBotGoroutine(session, req.LLMRouter)
+	return sub
+}
+
+// startSessionPump 세션 채널을 소비하는 단일 고루틴 시작
+// 봇이 처리할 이벤트를 봇 채널로 넘기고, 모든 이벤트에 ID를 부여해 연결된 스트림(기기)들에 같은 순서로 전달한다.
+// 세션이 종료되면 채널에 남은 이벤트(session_expired 등)까지 전달한 뒤 스트림을 닫는다.
+func startSessionPump(session *middleware.SSESession) {
+	session.Go(func(ctx context.Context) {
+		for {
+			select {
+			case message := <-session.Channel:
+				routeToBot(session.SessionID, message, session.BotChannel)
+				session.RecordEvent(message)
+
+			case <-ctx.Done():
+				for {
+					select {
+					case message := <-session.Channel:
+						session.RecordEvent(message)
+					default:
+						session.CloseStreams()
+						return
+					}
+				}
+			}
+		}
+	})
+}
+
+// drainSubscription 스트림이 닫히기 전에 전달된 이벤트를 모두 전송
+func drainSubscription(sub *middleware.Subscription, write func(event middleware.SSEEvent) error) {
+	for {
+		select {
+		case event := <-sub.Events():
+			if err := write(event); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// parseTakeoverMode takeover 쿼리 파싱 (비어 있으면 fallback)
+func parseTakeoverMode(value string, fallback middleware.TakeoverMode) (middleware.TakeoverMode, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	mode, ok := middleware.ParseTakeoverMode(value)
+	if !ok {
+		return "", fiber.NewError(fiber.StatusBadRequest, "takeover must be one of reject, replace, fanout")
+	}
+	return mode, nil
+}
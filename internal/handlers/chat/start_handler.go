@@ -2,13 +2,13 @@ package chat
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
 	"sermo-be/internal/core/chat"
 	"sermo-be/internal/middleware"
+	"sermo-be/pkg/llm"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -33,16 +33,23 @@ func checkConnectionStatus(w *bufio.Writer) error {
 
 // StartChat 채팅 시작 및 SSE 연결
 // @Summary 채팅 시작
-// @Description 채팅을 시작하고 SSE 연결을 설정합니다
+// @Description 채팅을 시작하고 SSE 연결을 설정합니다. 모든 이벤트에는 id 필드가 붙으며, 연결이 끊긴 뒤 Last-Event-ID 헤더(또는 last_event_id 쿼리)로 다시 연결하면 기존 세션에 붙어 놓친 이벤트를 재전송받습니다. 봇 응답 대기 상태가 바뀔 때마다 reply_state 이벤트(pending, typing, first_message_at, due_at)로 응답 예정 시각을 알립니다. 일정 시간 메시지가 없으면 session_expired 이벤트를 보낸 뒤 세션을 종료합니다. 봇 응답 생성이 재시도와 대체 모델까지 모두 실패하면 타이핑 표시를 끄고 bot_error 이벤트(code, error)를 보냅니다. 동시 세션 수가 가득 차면 queued 이벤트(position, queue_length)로 대기 순번을 알리다가 자리가 나면 이어서 채팅을 시작하며, 대기 시간을 넘기면 queue_timeout 이벤트 후 연결을 종료합니다. 사용자별 세션 수 초과는 429, 대기열까지 가득 차면 503을 반환합니다.
 // @Tags Chat
 // @Accept json
 // @Produce text/event-stream
 // @Security BearerAuth
 // @Param chatbot_uuid query string true "채팅봇 UUID"
+// @Param Last-Event-ID header string false "마지막으로 받은 이벤트 ID (재연결 시)"
+// @Param last_event_id query string false "마지막으로 받은 이벤트 ID (헤더를 설정할 수 없는 클라이언트용)"
+// @Param takeover query string false "기존 세션 연결 방식: reject(다른 기기가 연결 중이면 409), replace(기존 연결을 끊고 이어받기), fanout(모든 기기에 같은 대화를 함께 전송). 기본값: reject (Last-Event-ID가 있으면 replace). Redis로 여러 인스턴스를 운영할 때 다른 인스턴스가 가진 세션에는 fanout이 409로 거절되고, replace는 그 세션을 종료한 뒤 이 인스턴스에서 새 세션을 시작하므로 재전송 버퍼가 이어지지 않아 놓친 이벤트는 /chat/history로 다시 조회해야 합니다." Enums(reject, replace, fanout)
+// @Param stream query bool false "봇 응답을 bot_delta 이벤트로 나눠 받고 bot_done으로 마무리 (기본값: false, 완성된 응답을 bot 이벤트로 전송)"
 // @Success 200 {string} string "SSE 스트림"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /chat/start [get]
 func StartChat(c *fiber.Ctx) error {
 	// 사용자 UUID 가져오기
@@ -57,33 +64,91 @@ func StartChat(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "chatbot_uuid is required"})
 	}
 
-	// SSE 매니저 가져오기
-	sseManager := middleware.GetSSEManager()
+	// 응답 생성용 언어 모델 확인
+	router := middleware.GetLLMRouter(c)
+	if router.For(llm.FeatureChat) == nil {
+		return c.Status(500).JSON(fiber.Map{"error": "LLM service unavailable"})
+	}
 
-	// 새로운 세션 생성
-	session, err := sseManager.CreateSession(userUUID, chatbotUUID)
+	// 재연결 요청이면 기존 세션에 다시 연결 (Last-Event-ID가 있으면 기본적으로 아직 끊김을 감지하지 못한 스트림도 이어받음)
+	lastEventID, hasEventID := middleware.ParseLastEventID(c.Get("Last-Event-ID", c.Query("last_event_id")))
+	defaultMode := middleware.TakeoverReject
+	if hasEventID {
+		defaultMode = middleware.TakeoverReplace
+	}
+	mode, err := parseTakeoverMode(c.Query("takeover"), defaultMode)
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	// SSE 헤더 설정
-	middleware.SSEHeaders(c)
-
-	// 봇 고루틴 시작
-	botGoroutine := chat.GetBotGoroutine()
-
-	// OpenAI 클라이언트 가져오기
-	openaiClient := middleware.GetOpenAIClient(c)
-	if openaiClient == nil {
-		return c.Status(500).JSON(fiber.Map{"error": "OpenAI service unavailable"})
+	// SSE 매니저 가져오기
+	sseManager := middleware.GetSSEManager(c)
+
+	// 기존 세션 연결 또는 새 세션 생성 (자리가 없으면 대기열에서 순번을 기다림)
+	req := sessionRequest{
+		UserUUID:      userUUID,
+		ChatbotUUID:   chatbotUUID,
+		Resume:        middleware.ResumeRequest{Mode: mode, LastEventID: lastEventID, HasEventID: hasEventID},
+		StreamReplies: c.QueryBool("stream"),
+		LLMRouter:     router,
+		Bot:           chat.GetServices(c).Bot,
+		AllowQueue:    true,
+	}
+	opened, err := openChatSession(sseManager, req)
+	if err != nil {
+		return c.Status(errorStatus(err, 400)).JSON(fiber.Map{"error": err.Error()})
 	}
+	session, sub := opened.Session, opened.Subscription
 
-	botChannel := botGoroutine.StartBotGoroutine(session, openaiClient)
+	// SSE 헤더 설정
+	middleware.SSEHeaders(c)
 
 	// SSE 스트림 시작
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if opened.Ticket != nil {
+			log.Printf("대기열 진입 - 사용자: %s, 채팅봇: %s", userUUID, chatbotUUID)
+			if session = waitInQueue(w, sseManager, opened.Ticket); session == nil {
+				return
+			}
+			sub = startChatSession(sseManager, session, req)
+		}
+
 		log.Printf("SSE 스트림 시작 - 세션: %s", session.SessionID)
 
+		// 연결이 끊기면 세션을 바로 지우지 않고 재연결 유예 기간 동안 유지 (다른 기기가 연결되어 있으면 세션은 그대로)
+		detach := func() {
+			sseManager.DetachStream(session, sub)
+		}
+
+		// 이벤트 전송 (id 필드 포함, 전송 실패 시 재연결에서 다시 보냄)
+		writeEvent := func(event middleware.SSEEvent) error {
+			if _, err := w.Write([]byte(event.Frame())); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		// 응답 헤더가 바로 전송되도록 주석 줄부터 보냄 (SSE 클라이언트는 무시, 보내지 않으면 첫 이벤트까지 연결이 열리지 않음)
+		if _, err := w.WriteString(": connected\n\n"); err != nil {
+			log.Printf("SSE 연결 응답 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+			detach()
+			return
+		}
+
+		// 놓친 이벤트 재전송
+		for _, event := range opened.Replay {
+			if _, err := w.Write([]byte(event.Frame())); err != nil {
+				log.Printf("이벤트 재전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+				detach()
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("이벤트 재전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+			detach()
+			return
+		}
+
 		// 연결 유지를 위한 heartbeat (30초마다)
 		heartbeatTicker := time.NewTicker(30 * time.Second)
 		defer heartbeatTicker.Stop()
@@ -92,60 +157,15 @@ func StartChat(c *fiber.Ctx) error {
 		connectionCheckTicker := time.NewTicker(5 * time.Second)
 		defer connectionCheckTicker.Stop()
 
-		// 클라이언트 메시지와 봇 메시지를 처리
-		for session.IsActive {
+		// 세션 이벤트(사용자 메시지 echo, 봇 메시지 등)를 클라이언트로 전송
+		for {
 			select {
-			case message := <-session.Channel:
-				// 클라이언트에서 온 메시지 처리
-				log.Printf("클라이언트 메시지 수신 - 세션: %s, 메시지: %s", session.SessionID, message)
-
-				// SSE 메시지에서 "data: " 접두사 제거
-				jsonData := message
-				if len(message) > 6 && message[:6] == "data: " {
-					jsonData = message[6:]
-				}
-
-				// 메시지 타입 확인
-				var baseMessage struct {
-					Type string `json:"type"`
-				}
-				if err := json.Unmarshal([]byte(jsonData), &baseMessage); err != nil {
-					log.Printf("메시지 타입 파싱 실패: %v", err)
-					continue
-				}
-
-				// 디버깅을 위한 상세 로그 추가
-				log.Printf("파싱된 메시지 타입: '%s'", baseMessage.Type)
-				log.Printf("원본 jsonData: '%s'", jsonData)
-				log.Printf("Type == 'user' 비교 결과: %t", baseMessage.Type == "user")
-				log.Printf("Type == 'onkeyboard' 비교 결과: %t", baseMessage.Type == "onkeyboard")
-				log.Printf("Type 길이: %d", len(baseMessage.Type))
-				log.Printf("Type 바이트: %v", []byte(baseMessage.Type))
-				// 사용자 메시지와 onkeyboard 이벤트를 봇 채널로 전달
-
-				if baseMessage.Type == "user" || baseMessage.Type == "onkeyboard" {
-					log.Printf("%s 이벤트를 봇 채널로 전달 - 세션: %s", baseMessage.Type, session.SessionID)
-					select {
-					case botChannel <- message:
-						// 전달 성공
-					default:
-						log.Printf("봇 채널이 가득 참 - 세션: %s", session.SessionID)
-					}
-				}
-
-				// 타이핑 이벤트는 클라이언트에만 전송 (봇 채널로 전달하지 않음)
-				if baseMessage.Type == "bot_typing" {
-					log.Printf("봇 타이핑 이벤트 클라이언트 전송 - 세션: %s", session.SessionID)
-				}
-
-				// 클라이언트에 메시지 전송 (echo)
-				_, err := w.Write([]byte(message))
-				if err != nil {
+			case event := <-sub.Events():
+				if err := writeEvent(event); err != nil {
 					log.Printf("클라이언트 메시지 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
-					sseManager.DeleteSession(session.SessionID)
+					detach()
 					return
 				}
-				w.Flush()
 
 			case <-heartbeatTicker.C:
 				// heartbeat 전송
@@ -154,7 +174,7 @@ func StartChat(c *fiber.Ctx) error {
 				_, err := w.Write([]byte(heartbeat))
 				if err != nil {
 					log.Printf("heartbeat 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
-					sseManager.DeleteSession(session.SessionID)
+					detach()
 					return
 				}
 				w.Flush()
@@ -163,18 +183,18 @@ func StartChat(c *fiber.Ctx) error {
 				// 5초마다 연결 상태 체크
 				if err := checkConnectionStatus(w); err != nil {
 					log.Printf("연결 상태 체크 실패 - 세션: %s, 에러: %v", session.SessionID, err)
-					sseManager.DeleteSession(session.SessionID)
+					detach()
 					return
 				}
 
-			case <-session.Done:
-				// SSE Manager에서 전송한 종료 신호
-				log.Printf("SSE Manager에서 세션 종료 신호 수신 - 세션: %s", session.SessionID)
+			case <-sub.Closed():
+				// 다른 기기로 교체, 전송 지연, 세션 종료 (session_expired 등 남은 이벤트는 보내고 종료)
+				drainSubscription(sub, writeEvent)
+				log.Printf("SSE 스트림 종료 - 세션: %s, 사유: %s", session.SessionID, sub.Reason())
+				detach()
 				return
 			}
 		}
-
-		log.Printf("SSE 스트림 종료 - 세션: %s", session.SessionID)
 	})
 
 	return nil
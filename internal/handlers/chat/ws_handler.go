@@ -0,0 +1,252 @@
+package chat
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/middleware"
+	"sermo-be/pkg/llm"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// wsPingInterval WebSocket 연결 유지를 위한 ping 주기
+	wsPingInterval = 30 * time.Second
+	// wsWriteTimeout 클라이언트 쓰기 제한 시간
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WSClientMessage WebSocket 클라이언트 → 서버 메시지
+// type: user(메시지 전송), onkeyboard(입력 중), flush(대기 없이 바로 응답 요청), stop(채팅 종료)
+type WSClientMessage struct {
+	Type    string `json:"type"`
+	Content string `json:"content,omitempty"`
+}
+
+// ChatWebSocketUpgrade WebSocket 업그레이드 요청만 통과시키고 세션에 필요한 값을 저장
+func ChatWebSocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{"error": "WebSocket upgrade required"})
+	}
+
+	if c.Query("chatbot_uuid") == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "chatbot_uuid is required"})
+	}
+
+	if _, err := parseTakeoverMode(c.Query("takeover"), middleware.TakeoverReject); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if middleware.GetLLM(c, llm.FeatureChat) == nil {
+		return c.Status(500).JSON(fiber.Map{"error": "LLM service unavailable"})
+	}
+
+	return c.Next()
+}
+
+// ChatWebSocket 양방향 채팅 (WebSocket)
+// @Summary 채팅 WebSocket
+// @Description 하나의 WebSocket 연결로 채팅을 진행합니다. SSE와 같은 이벤트(user, bot, bot_typing, bot_error, onkeyboard, reply_state, session_expired)를 JSON 텍스트 메시지로 주고받으며, 클라이언트는 {"type":"user","content":"..."}, {"type":"onkeyboard"}, {"type":"flush"}, {"type":"stop"}을 보낼 수 있습니다.
+// @Tags Chat
+// @Security BearerAuth
+// @Param chatbot_uuid query string true "채팅봇 UUID"
+// @Param takeover query string false "기존 세션 연결 방식: reject(다른 기기가 연결 중이면 거절), replace(기존 연결을 끊고 이어받기), fanout(모든 기기에 같은 대화를 함께 전송). 기본값: reject. Redis로 여러 인스턴스를 운영할 때 다른 인스턴스가 가진 세션에는 fanout이 409로 거절되고, replace는 그 세션을 종료한 뒤 이 인스턴스에서 새 세션을 시작하므로 재전송 버퍼가 이어지지 않아 놓친 이벤트는 /chat/history로 다시 조회해야 합니다." Enums(reject, replace, fanout)
+// @Param stream query bool false "봇 응답을 bot_delta 이벤트로 나눠 받고 bot_done으로 마무리 (기본값: false)"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 426 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /chat/ws [get]
+func ChatWebSocket(c *fiber.Ctx) error {
+	return chatWebSocketHandler(c)
+}
+
+var chatWebSocketHandler = websocket.New(serveChatWebSocket)
+
+// serveChatWebSocket 업그레이드된 연결에서 세션 생성부터 종료까지 처리
+func serveChatWebSocket(conn *websocket.Conn) {
+	userUUID, _ := conn.Locals("user_uuid").(string)
+	router, _ := conn.Locals("llm_router").(*llm.Router)
+	services, _ := conn.Locals("chat_services").(*chat.Services)
+	sseManager, _ := conn.Locals("sse_manager").(*middleware.SSEManager)
+	chatbotUUID := conn.Query("chatbot_uuid")
+
+	mode, _ := parseTakeoverMode(conn.Query("takeover"), middleware.TakeoverReject)
+
+	// 기존 세션에 연결하거나 SSE와 동일하게 세션 생성
+	// (같은 사용자-채팅봇 중복 방지, 다른 기기와의 교체/동시 연결, 다른 인스턴스에서의 /chat/send 라우팅 포함)
+	opened, err := openChatSession(sseManager, sessionRequest{
+		UserUUID:      userUUID,
+		ChatbotUUID:   chatbotUUID,
+		Resume:        middleware.ResumeRequest{Mode: mode},
+		StreamReplies: conn.Query("stream") == "true",
+		LLMRouter:     router,
+		Bot:           services.Bot,
+	})
+	if err != nil {
+		writeWSError(conn, err.Error())
+		conn.Close()
+		return
+	}
+	session, sub := opened.Session, opened.Subscription
+
+	log.Printf("WebSocket 채팅 시작 - 세션: %s", session.SessionID)
+
+	// 세션 채널 → 클라이언트 (쓰기는 이 고루틴에서만 수행)
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		writeWSLoop(conn, session, sub, opened.Replay)
+	}()
+
+	// 클라이언트 → 세션 채널
+	stopped := readWSLoop(conn, sseManager, services.Messages, userUUID, chatbotUUID, session.SessionID)
+
+	if stopped {
+		// 클라이언트가 stop을 보낸 경우: /chat/stop과 동일한 종료 처리
+		if err := sseManager.StopSession(session.SessionID); err == nil {
+			go services.ProcessChatEnd(router, userUUID, chatbotUUID)
+		}
+	} else {
+		// 연결 끊김: SSE 스트림이 끊긴 경우와 동일하게 재연결 유예 기간 동안 세션 유지
+		sseManager.DetachStream(session, sub)
+	}
+
+	<-writerDone
+	log.Printf("WebSocket 채팅 종료 - 세션: %s", session.SessionID)
+}
+
+// readWSLoop 클라이언트 메시지를 읽어 세션으로 전달 (stop 수신 시 true 반환)
+func readWSLoop(conn *websocket.Conn, sseManager *middleware.SSEManager, messageService *chat.MessageService, userUUID, chatbotUUID, sessionID string) bool {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("WebSocket 읽기 종료 - 세션: %s, 사유: %v", sessionID, err)
+			return false
+		}
+
+		var message WSClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("WebSocket 메시지 파싱 실패 - 세션: %s, 에러: %v", sessionID, err)
+			continue
+		}
+
+		switch message.Type {
+		case "user":
+			if message.Content == "" {
+				continue
+			}
+
+			// /chat/send와 동일: 세션으로 전송 후 DB 저장
+			frame, err := newUserFrame(sessionID, message.Content)
+			if err != nil {
+				continue
+			}
+			if err := sseManager.SendUserMessage(sessionID, frame); err != nil {
+				log.Printf("사용자 메시지 전송 실패 - 세션: %s, 에러: %v", sessionID, err)
+				return false
+			}
+			if _, err := messageService.CreateUserMessage(sessionID, userUUID, chatbotUUID, message.Content); err != nil {
+				log.Printf("사용자 메시지 저장 실패 - 세션: %s, 에러: %v", sessionID, err)
+			}
+
+		case "onkeyboard":
+			frame, err := newOnKeyboardFrame(sessionID)
+			if err != nil {
+				continue
+			}
+			if err := sseManager.SendMessage(sessionID, frame); err != nil {
+				log.Printf("onkeyboard 이벤트 전송 실패 - 세션: %s, 에러: %v", sessionID, err)
+				return false
+			}
+
+		case "flush":
+			frame, err := newFlushFrame(sessionID)
+			if err != nil {
+				continue
+			}
+			if err := sseManager.SendMessage(sessionID, frame); err != nil {
+				log.Printf("flush 이벤트 전송 실패 - 세션: %s, 에러: %v", sessionID, err)
+				return false
+			}
+
+		case "stop":
+			return true
+
+		default:
+			log.Printf("알 수 없는 WebSocket 메시지 타입: %s - 세션: %s", message.Type, sessionID)
+		}
+	}
+}
+
+// writeWSLoop 세션 이벤트를 클라이언트로 전송
+// SSE 스트림과 같은 이벤트를 JSON 텍스트 메시지로 내려보낸다 (끊긴 동안 쌓인 이벤트가 있으면 먼저 재전송).
+func writeWSLoop(conn *websocket.Conn, session *middleware.SSESession, sub *middleware.Subscription, replay []middleware.SSEEvent) {
+	pingTicker := time.NewTicker(wsPingInterval)
+	defer pingTicker.Stop()
+
+	// 루프가 끝나면 연결을 닫아 읽기 루프도 종료시킨다
+	defer conn.Close()
+
+	sessionID := session.SessionID
+
+	writeEvent := func(event middleware.SSEEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		return conn.WriteMessage(websocket.TextMessage, []byte(frameJSON(event.Data)))
+	}
+
+	for _, event := range replay {
+		if err := writeEvent(event); err != nil {
+			log.Printf("WebSocket 이벤트 재전송 실패 - 세션: %s, 에러: %v", sessionID, err)
+			return
+		}
+	}
+
+	for {
+		select {
+		case event := <-sub.Events():
+			if err := writeEvent(event); err != nil {
+				log.Printf("WebSocket 메시지 전송 실패 - 세션: %s, 에러: %v", sessionID, err)
+				return
+			}
+
+		case <-pingTicker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				log.Printf("WebSocket ping 실패 - 세션: %s, 에러: %v", sessionID, err)
+				return
+			}
+
+		case <-sub.Closed():
+			// 다른 기기로 교체, 전송 지연, 세션 종료 (/chat/stop, 유휴 만료, 서버 종료 등): 남은 이벤트를 보낸 뒤 연결 종료
+			drainSubscription(sub, writeEvent)
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, wsCloseReason(sub.Reason())))
+			return
+		}
+	}
+}
+
+// wsCloseReason 스트림 종료 사유별 WebSocket close 메시지
+func wsCloseReason(reason string) string {
+	switch reason {
+	case middleware.StreamReplaced:
+		return "session resumed elsewhere"
+	case middleware.StreamLagging:
+		return "client too slow, reconnect to resume"
+	default:
+		return "session stopped"
+	}
+}
+
+// writeWSError 에러 이벤트 전송
+func writeWSError(conn *websocket.Conn, message string) {
+	data, _ := json.Marshal(fiber.Map{"type": "error", "error": message})
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	conn.WriteMessage(websocket.TextMessage, data)
+}
@@ -16,6 +16,7 @@ type CreateChatbotRequest struct {
 	Hashtags []string `json:"hashtags"` // 해시태그 배열
 	Gender   string   `json:"gender"`   // 성별 (male, female, unspecified)
 	Details  string   `json:"details"`  // 상세 설명
+	ReplySettings
 }
 
 // CreateChatbotResponse 채팅봇 생성 응답 DTO
@@ -73,6 +74,13 @@ func CreateChatbot(c *fiber.Ctx) error {
 		})
 	}
 
+	// 응답 대기 설정 검증
+	if err := req.ReplySettings.validate(); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	// context에서 사용자 UUID 가져오기
 	userUUID := middleware.GetUserUUID(c)
 
@@ -96,6 +104,9 @@ func CreateChatbot(c *fiber.Ctx) error {
 		req.Details,
 		userUUID,
 	)
+	chatbot.ReplyDelayMs = req.ReplyDelayMs
+	chatbot.TypingExtensionMs = req.TypingExtensionMs
+	chatbot.MaxReplyWaitMs = req.MaxReplyWaitMs
 
 	// 데이터베이스에 저장
 	if err := db.Create(chatbot).Error; err != nil {
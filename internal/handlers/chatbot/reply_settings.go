@@ -0,0 +1,45 @@
+package chatbot
+
+import (
+	"fmt"
+)
+
+// maxReplySettingMs 채팅봇별 응답 대기 설정 상한 (1분)
+const maxReplySettingMs = 60000
+
+// ReplySettings 채팅봇별 봇 응답 대기 설정 (밀리초, 생략하면 서버 기본값 사용)
+type ReplySettings struct {
+	ReplyDelayMs      *int `json:"reply_delay_ms,omitempty"`      // 마지막 메시지 후 응답까지 대기 시간
+	TypingExtensionMs *int `json:"typing_extension_ms,omitempty"` // 입력 중 이벤트마다 연장하는 시간
+	MaxReplyWaitMs    *int `json:"max_reply_wait_ms,omitempty"`   // 첫 메시지부터 최대 대기 시간
+}
+
+// validate 설정 범위 검증
+func (s ReplySettings) validate() error {
+	if s.ReplyDelayMs != nil && (*s.ReplyDelayMs <= 0 || *s.ReplyDelayMs > maxReplySettingMs) {
+		return fmt.Errorf("reply_delay_ms must be between 1 and %d", maxReplySettingMs)
+	}
+	if s.TypingExtensionMs != nil && (*s.TypingExtensionMs < 0 || *s.TypingExtensionMs > maxReplySettingMs) {
+		return fmt.Errorf("typing_extension_ms must be between 0 and %d", maxReplySettingMs)
+	}
+	if s.MaxReplyWaitMs != nil && (*s.MaxReplyWaitMs <= 0 || *s.MaxReplyWaitMs > maxReplySettingMs) {
+		return fmt.Errorf("max_reply_wait_ms must be between 1 and %d", maxReplySettingMs)
+	}
+	if s.ReplyDelayMs != nil && s.MaxReplyWaitMs != nil && *s.MaxReplyWaitMs < *s.ReplyDelayMs {
+		return fmt.Errorf("max_reply_wait_ms must be at least reply_delay_ms")
+	}
+	return nil
+}
+
+// updates 지정된 설정만 업데이트 맵에 추가
+func (s ReplySettings) updates(updates map[string]interface{}) {
+	if s.ReplyDelayMs != nil {
+		updates["reply_delay_ms"] = *s.ReplyDelayMs
+	}
+	if s.TypingExtensionMs != nil {
+		updates["typing_extension_ms"] = *s.TypingExtensionMs
+	}
+	if s.MaxReplyWaitMs != nil {
+		updates["max_reply_wait_ms"] = *s.MaxReplyWaitMs
+	}
+}
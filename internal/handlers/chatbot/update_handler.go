@@ -17,6 +17,7 @@ type UpdateChatbotRequest struct {
 	Hashtags []string `json:"hashtags"` // 해시태그 배열
 	Gender   string   `json:"gender"`   // 성별 (male, female, unspecified)
 	Details  string   `json:"details"`  // 상세 설명
+	ReplySettings
 }
 
 // UpdateChatbotResponse 채팅봇 수정 응답 DTO
@@ -91,6 +92,13 @@ func UpdateChatbot(c *fiber.Ctx) error {
 		})
 	}
 
+	// 응답 대기 설정 검증
+	if err := req.ReplySettings.validate(); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	// context에서 사용자 UUID 가져오기
 	userUUID := middleware.GetUserUUID(c)
 
@@ -114,6 +122,7 @@ func UpdateChatbot(c *fiber.Ctx) error {
 		"details":    req.Details,
 		"updated_at": time.Now(),
 	}
+	req.ReplySettings.updates(updates)
 
 	// 데이터베이스 업데이트
 	if err := db.Model(&existingChatbot).Updates(updates).Error; err != nil {
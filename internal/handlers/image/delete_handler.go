@@ -5,7 +5,6 @@ import (
 
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -69,7 +68,7 @@ func DeleteImage(c *fiber.Ctx) error {
 
 	// DB에서 이미지 정보 조회
 	var image models.Image
-	if err := database.DB.Where("id = ? AND user_id = ?", imageUUID, userUUID).First(&image).Error; err != nil {
+	if err := middleware.GetDB(c).Where("id = ? AND user_id = ?", imageUUID, userUUID).First(&image).Error; err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "이미지를 찾을 수 없습니다",
 		})
@@ -77,15 +76,20 @@ func DeleteImage(c *fiber.Ctx) error {
 
 	// R2 클라이언트 가져오기
 	r2Client := middleware.GetR2Client(c)
+	if r2Client == nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "R2 클라이언트 초기화 실패",
+		})
+	}
 
 	// R2에서 파일 삭제 (FileKey 사용)
-	if err := r2Client.DeleteFile(c.Context(), image.FileKey); err != nil {
+	if err := r2Client.DeleteFile(c.UserContext(), image.FileKey); err != nil {
 		// R2 삭제 실패해도 DB는 삭제 (일관성 유지)
 		fmt.Printf("R2 파일 삭제 실패: %v\n", err)
 	}
 
 	// DB에서 이미지 정보 삭제
-	if err := database.DB.Delete(&image).Error; err != nil {
+	if err := middleware.GetDB(c).Delete(&image).Error; err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "이미지 정보 삭제에 실패했습니다",
 		})
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
 	"time"
@@ -12,7 +13,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
-	"sermo-be/pkg/openai"
+	"sermo-be/pkg/llm"
 	"sermo-be/pkg/prompt"
 )
 
@@ -39,6 +40,7 @@ type GenerateImageResponse struct {
 // @Success 200 {object} GenerateImageResponse "이미지 생성 성공"
 // @Failure 400 {object} map[string]interface{} "잘못된 요청 (프롬프트 누락 등)"
 // @Failure 401 {object} map[string]interface{} "인증 실패"
+// @Failure 429 {object} map[string]interface{} "AI 사용 한도 초과 (토큰 또는 이미지 수)"
 // @Failure 500 {object} map[string]interface{} "서버 오류 (OpenAI/Gemini API 오류 등)"
 // @Router /image/generate [post]
 func GenerateImage(c *fiber.Ctx) error {
@@ -57,11 +59,11 @@ func GenerateImage(c *fiber.Ctx) error {
 		})
 	}
 
-	// context에서 OpenAI 클라이언트 가져오기
-	openaiClient := middleware.GetOpenAIClient(c)
-	if openaiClient == nil {
+	// context에서 외형 특징 추출용 언어 모델 가져오기
+	model := middleware.GetLLM(c, llm.FeatureImagePrompt)
+	if model == nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "OpenAI client not available",
+			"error": "LLM client not available",
 		})
 	}
 
@@ -73,8 +75,8 @@ func GenerateImage(c *fiber.Ctx) error {
 		})
 	}
 
-	// OpenAI를 사용해서 외형 관련 설정 추출
-	appearanceFeatures, err := extractAppearanceFeatures(openaiClient, req.Prompt, c)
+	// 언어 모델을 사용해서 외형 관련 설정 추출
+	appearanceFeatures, err := extractAppearanceFeatures(model, req.Prompt, c)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to extract appearance features: " + err.Error(),
@@ -90,12 +92,21 @@ func GenerateImage(c *fiber.Ctx) error {
 	fmt.Printf("Enhanced prompt: %s\n", enhancedPrompt)
 
 	// 이미지 생성
-	response, err := geminiClient.GenerateImage(c.Context(), enhancedPrompt)
+	response, err := geminiClient.GenerateImage(c.UserContext(), enhancedPrompt)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate image: " + err.Error(),
 		})
 	}
+	middleware.GetUsageService(c).Record(&models.UsageRecord{
+		UserUUID:         middleware.GetUserUUID(c),
+		Feature:          usage.FeatureImage,
+		Model:            response.Model,
+		PromptTokens:     response.Usage.PromptTokenCount,
+		CompletionTokens: response.Usage.CandidatesTokenCount,
+		TotalTokens:      response.Usage.TotalTokenCount,
+		Images:           len(response.Images),
+	})
 
 	// 디버깅: Gemini 응답 확인
 	fmt.Printf("Gemini response: %d images received\n", len(response.Images))
@@ -147,7 +158,7 @@ func GenerateImage(c *fiber.Ctx) error {
 
 		// R2에 이미지 업로드
 		reader := bytes.NewReader(imageBytes)
-		if err := r2Client.UploadFile(c.Context(), fileKey, reader); err != nil {
+		if err := r2Client.UploadFile(c.UserContext(), fileKey, reader); err != nil {
 			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to upload image to R2: " + err.Error(),
 			})
@@ -182,13 +193,13 @@ func GenerateImage(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
-// extractAppearanceFeatures OpenAI를 사용해서 외형 관련 특징 추출
-func extractAppearanceFeatures(openaiClient *openai.Client, basePrompt string, c *fiber.Ctx) (string, error) {
+// extractAppearanceFeatures 언어 모델을 사용해서 외형 관련 특징 추출
+func extractAppearanceFeatures(model llm.LLM, basePrompt string, c *fiber.Ctx) (string, error) {
 	// 외형 추출을 위한 프롬프트 생성
 	extractionPrompt := prompt.GetAppearanceExtractionPrompt()
 
 	// 사용자 프롬프트와 함께 메시지 구성
-	messages := []openai.ChatMessage{
+	messages := []llm.ChatMessage{
 		{
 			Role:    "system",
 			Content: extractionPrompt,
@@ -199,11 +210,12 @@ func extractAppearanceFeatures(openaiClient *openai.Client, basePrompt string, c
 		},
 	}
 
-	// OpenAI API 호출
-	response, err := openaiClient.ChatCompletion(c.Context(), messages)
+	// 언어 모델 호출
+	response, err := model.ChatCompletion(c.UserContext(), messages)
 	if err != nil {
 		return "", fmt.Errorf("failed to extract appearance features: %w", err)
 	}
+	middleware.GetUsageService(c).RecordChat(middleware.GetUserUUID(c), usage.FeatureImagePrompt, model, response)
 
 	return response.Message.Content, nil
 }
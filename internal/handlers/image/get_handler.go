@@ -7,7 +7,6 @@ import (
 
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -65,14 +64,14 @@ func GetImage(c *fiber.Ctx) error {
 
 	// DB에서 이미지 정보 조회
 	var image models.Image
-	if err := database.DB.Where("id = ? AND user_id = ?", imageID, userUUID).First(&image).Error; err != nil {
+	if err := middleware.GetDB(c).Where("id = ? AND user_id = ?", imageID, userUUID).First(&image).Error; err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "이미지를 찾을 수 없습니다",
 		})
 	}
 
 	// 프리사인드 URL 생성 (24시간 유효)
-	presignedURL, err := r2Client.GeneratePresignedURL(c.Context(), image.FileKey, 24*time.Hour)
+	presignedURL, err := r2Client.GeneratePresignedURL(c.UserContext(), image.FileKey, 24*time.Hour)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "프리사인드 URL 생성에 실패했습니다",
@@ -128,14 +127,14 @@ func DownloadImage(c *fiber.Ctx) error {
 
 	// DB에서 이미지 정보 조회
 	var image models.Image
-	if err := database.DB.Where("id = ? AND user_id = ?", imageID, userUUID).First(&image).Error; err != nil {
+	if err := middleware.GetDB(c).Where("id = ? AND user_id = ?", imageID, userUUID).First(&image).Error; err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "이미지를 찾을 수 없습니다",
 		})
 	}
 
 	// R2에서 파일 다운로드
-	fileReader, err := r2Client.DownloadFile(c.Context(), image.FileKey)
+	fileReader, err := r2Client.DownloadFile(c.UserContext(), image.FileKey)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "파일 다운로드에 실패했습니다",
@@ -8,7 +8,6 @@ import (
 
 	"sermo-be/internal/middleware"
 	"sermo-be/internal/models"
-	"sermo-be/pkg/database"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -84,7 +83,7 @@ func UploadImage(c *fiber.Ctx) error {
 	}
 	defer fileReader.Close()
 
-	if err := r2Client.UploadFile(c.Context(), key, fileReader); err != nil {
+	if err := r2Client.UploadFile(c.UserContext(), key, fileReader); err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "파일 업로드에 실패했습니다",
 			"details": err.Error(),
@@ -100,7 +99,7 @@ func UploadImage(c *fiber.Ctx) error {
 		file.Size,
 	)
 
-	if err := database.DB.Create(&image).Error; err != nil {
+	if err := middleware.GetDB(c).Create(&image).Error; err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "이미지 정보 저장에 실패했습니다",
 			"details": err.Error(),
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"sermo-be/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetUsage AI 사용량 조회 (인증 필요)
+// @Summary AI 사용량 조회
+// @Description 현재 사용자의 오늘/이번 달 AI 사용량(토큰, 생성 이미지 수)과 한도, 이번 달 기능·모델별 내역을 조회합니다. 기간은 UTC 기준이며 한도가 0이면 무제한입니다.
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} usage.Report
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /user/usage [get]
+func GetUsage(c *fiber.Ctx) error {
+	userUUID := middleware.GetUserUUID(c)
+
+	report, err := middleware.GetUsageService(c).Report(userUUID, time.Now())
+	if err != nil {
+		log.Printf("사용량 조회 실패 - 사용자: %s, 에러: %v", userUUID, err)
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get usage",
+		})
+	}
+
+	return c.JSON(report)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"sermo-be/pkg/jwt"
 	"strings"
@@ -50,6 +51,20 @@ func AuthMiddleware() fiber.Handler {
 			})
 		}
 
+		// 세션 폐기 여부 확인 (로그아웃 또는 토큰 재사용 감지로 폐기된 세션 거부)
+		active, err := GetTokenService(c).IsSessionActive(claims.SessionID)
+		if err != nil {
+			log.Printf("세션 상태 확인 실패 - 세션: %s, 에러: %v", claims.SessionID, err)
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to verify session",
+			})
+		}
+		if !active {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Session has been revoked",
+			})
+		}
+
 		// context에 사용자 UUID 저장
 		c.Locals("user_uuid", claims.UUID)
 		c.Locals("user_claims", claims)
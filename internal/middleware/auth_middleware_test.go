@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/testutil"
+	"sermo-be/pkg/jwt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	testutil.ConfigureJWT(t)
+	svc := token.NewTokenService(testutil.NewDB(t))
+
+	app := fiber.New()
+	app.Use(middleware.TokenServiceMiddleware(svc))
+	app.Get("/me", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetUserUUID(c) + " " + middleware.GetUserClaims(c).SessionID)
+	})
+
+	issue := func(t *testing.T) *token.TokenPair {
+		t.Helper()
+		pair, err := svc.IssueTokens("user-1", "test-device")
+		if err != nil {
+			t.Fatalf("IssueTokens: %v", err)
+		}
+		return pair
+	}
+
+	active := issue(t)
+	loggedOut := issue(t)
+	if err := svc.RevokeByRefreshToken(loggedOut.RefreshToken); err != nil {
+		t.Fatalf("RevokeByRefreshToken: %v", err)
+	}
+	reused := issue(t)
+	if _, err := svc.RotateTokens(reused.RefreshToken, ""); err != nil {
+		t.Fatalf("RotateTokens: %v", err)
+	}
+	if _, err := svc.RotateTokens(reused.RefreshToken, ""); err == nil {
+		t.Fatal("reusing a rotated refresh token should fail")
+	}
+	unknownSession, err := jwt.GenerateToken("user-1", "unknown-session")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"bearer token", "/me", "Bearer " + active.AccessToken, fiber.StatusOK, "user-1 " + active.SessionID},
+		{"query token", "/me?token=" + active.AccessToken, "", fiber.StatusOK, "user-1 " + active.SessionID},
+		{"missing token", "/me", "", fiber.StatusUnauthorized, "Authorization header or token query parameter is required"},
+		{"not a bearer token", "/me", "Token " + active.AccessToken, fiber.StatusUnauthorized, "Invalid authorization format"},
+		{"invalid token", "/me", "Bearer not-a-jwt", fiber.StatusUnauthorized, "Invalid or expired token"},
+		{"logged out session", "/me", "Bearer " + loggedOut.AccessToken, fiber.StatusUnauthorized, "Session has been revoked"},
+		{"session revoked by reuse", "/me", "Bearer " + reused.AccessToken, fiber.StatusUnauthorized, "Session has been revoked"},
+		{"unknown session", "/me", "Bearer " + unknownSession, fiber.StatusUnauthorized, "Session has been revoked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", resp.StatusCode, tt.wantStatus, body)
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Fatalf("body = %s, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+
+	"sermo-be/internal/config"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/compat"
+	"sermo-be/pkg/llm/llmtest"
+	"sermo-be/pkg/openai"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewLLMRouter 설정의 제공자를 만들고 기능별로 연결한 Router 생성
+// 사용하지 않는 제공자는 만들지 않으며, 제공자를 쓸 수 없는 기능(openai 비활성화 등)은 Router에서 nil을 반환한다.
+func NewLLMRouter(cfg *config.Config) (*llm.Router, error) {
+	router := llm.NewRouter()
+	providers := make(map[string]llm.LLM)
+
+	for _, feature := range llm.Features {
+		name := cfg.LLM.ProviderFor(feature)
+
+		provider, ok := providers[name]
+		if !ok {
+			var err error
+			provider, err = newLLMProvider(cfg, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create LLM provider %q: %w", name, err)
+			}
+			providers[name] = provider
+		}
+
+		if provider != nil {
+			router.Route(feature, provider)
+		}
+	}
+
+	return router, nil
+}
+
+// newLLMProvider 이름에 해당하는 제공자 생성 (openai가 비활성화된 기본 제공자는 nil)
+func newLLMProvider(cfg *config.Config, name string) (llm.LLM, error) {
+	provider, ok := cfg.LLM.Providers[name]
+	if !ok {
+		if name != config.DefaultLLMProvider || !cfg.OpenAI.Enabled {
+			return nil, nil
+		}
+		provider = config.LLMProviderConfig{
+			Type:                config.LLMProviderOpenAI,
+			APIKey:              cfg.OpenAI.APIKey,
+			Model:               cfg.OpenAI.Model,
+			MaxCompletionTokens: cfg.OpenAI.MaxCompletionTokens,
+			EmbeddingModel:      cfg.OpenAI.EmbeddingModel,
+			FallbackModels:      cfg.OpenAI.FallbackModels,
+		}
+	}
+
+	switch provider.Type {
+	case config.LLMProviderOpenAI:
+		// 재시도와 회로 차단 설정은 openai 설정을 공유
+		return openai.NewClient(&openai.Config{
+			APIKey:              provider.APIKey,
+			Model:               provider.Model,
+			MaxCompletionTokens: provider.MaxCompletionTokens,
+			EmbeddingModel:      provider.EmbeddingModel,
+			FallbackModels:      provider.FallbackModels,
+			MaxAttempts:         cfg.OpenAI.MaxAttempts,
+			CallTimeout:         cfg.OpenAI.CallTimeout,
+			BreakerThreshold:    cfg.OpenAI.BreakerThreshold,
+			BreakerCooldown:     cfg.OpenAI.BreakerCooldown,
+		})
+	case config.LLMProviderCompatible:
+		return compat.NewClient(&compat.Config{
+			BaseURL:             provider.BaseURL,
+			APIKey:              provider.APIKey,
+			Model:               provider.Model,
+			MaxCompletionTokens: provider.MaxCompletionTokens,
+			EmbeddingModel:      provider.EmbeddingModel,
+		})
+	case config.LLMProviderFake:
+		return &llmtest.Fake{Model: provider.Model, Dimensions: openai.EmbeddingDimensions}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", provider.Type)
+	}
+}
+
+// LLMMiddleware 기능별 언어 모델 Router를 컨텍스트에 저장하는 미들웨어
+func LLMMiddleware(router *llm.Router) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("llm_router", router)
+		return c.Next()
+	}
+}
+
+// GetLLMRouter 컨텍스트에서 언어 모델 Router 가져오기
+func GetLLMRouter(c *fiber.Ctx) *llm.Router {
+	if router, ok := c.Locals("llm_router").(*llm.Router); ok {
+		return router
+	}
+	return nil
+}
+
+// GetLLM 컨텍스트에서 기능에 지정된 언어 모델 가져오기 (없으면 nil)
+func GetLLM(c *fiber.Ctx, feature llm.Feature) llm.LLM {
+	return GetLLMRouter(c).For(feature)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"testing"
+
+	"sermo-be/internal/config"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/compat"
+	"sermo-be/pkg/llm/llmtest"
+	"sermo-be/pkg/openai"
+)
+
+func TestNewLLMRouter(t *testing.T) {
+	cfg := &config.Config{
+		OpenAI: config.OpenAIConfig{Enabled: true, APIKey: "sk-test", Model: "big"},
+		LLM: config.LLMConfig{
+			Providers: map[string]config.LLMProviderConfig{
+				"small": {Type: config.LLMProviderCompatible, BaseURL: "http://localhost:8000/v1", Model: "small"},
+				"fake":  {Type: config.LLMProviderFake},
+			},
+			Features: map[string]string{
+				"status":   "small",
+				"summary":  "small",
+				"bookmark": "fake",
+			},
+		},
+	}
+
+	router, err := NewLLMRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewLLMRouter: %v", err)
+	}
+
+	if _, ok := router.For(llm.FeatureChat).(*openai.Client); !ok {
+		t.Errorf("chat = %T, want *openai.Client", router.For(llm.FeatureChat))
+	}
+	if _, ok := router.For(llm.FeatureStatus).(*compat.Client); !ok {
+		t.Errorf("status = %T, want *compat.Client", router.For(llm.FeatureStatus))
+	}
+	if _, ok := router.For(llm.FeatureBookmark).(*llmtest.Fake); !ok {
+		t.Errorf("bookmark = %T, want *llmtest.Fake", router.For(llm.FeatureBookmark))
+	}
+
+	// 같은 제공자를 쓰는 기능은 같은 클라이언트를 공유
+	if router.For(llm.FeatureStatus) != router.For(llm.FeatureSummary) {
+		t.Error("status and summary should share the small provider")
+	}
+	if router.For(llm.FeatureChat) != router.For(llm.FeatureEmbedding) {
+		t.Error("chat and embedding should share the default provider")
+	}
+}
+
+func TestNewLLMRouterOpenAIDisabled(t *testing.T) {
+	cfg := &config.Config{
+		LLM: config.LLMConfig{
+			Providers: map[string]config.LLMProviderConfig{"fake": {Type: config.LLMProviderFake}},
+			Features:  map[string]string{"chat": "fake"},
+		},
+	}
+
+	router, err := NewLLMRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewLLMRouter: %v", err)
+	}
+
+	if router.For(llm.FeatureChat) == nil {
+		t.Error("chat should use the fake provider")
+	}
+	// openai가 비활성화되어 기본 제공자를 쓰는 기능은 사용할 수 없음
+	if model := router.For(llm.FeatureStatus); model != nil {
+		t.Errorf("status = %T, want nil", model)
+	}
+}
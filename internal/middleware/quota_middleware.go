@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sermo-be/internal/core/usage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuotaMiddleware 사용자의 일/월 AI 사용량이 한도를 넘었으면 429를 반환하는 미들웨어 (AuthMiddleware 뒤에 사용)
+// resources를 지정하지 않으면 토큰 한도만 확인한다.
+func QuotaMiddleware(resources ...usage.Resource) fiber.Handler {
+	if len(resources) == 0 {
+		resources = []usage.Resource{usage.ResourceTokens}
+	}
+
+	return func(c *fiber.Ctx) error {
+		now := time.Now()
+		err := GetUsageService(c).CheckQuota(GetUserUUID(c), now, resources...)
+		if err == nil {
+			return c.Next()
+		}
+
+		var exceeded *usage.QuotaExceededError
+		if !errors.As(err, &exceeded) {
+			log.Printf("사용량 한도 확인 실패 - 사용자: %s, 에러: %v", GetUserUUID(c), err)
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check usage quota",
+			})
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(exceeded.ResetAt.Sub(now).Seconds())+1))
+		return c.Status(http.StatusTooManyRequests).JSON(fiber.Map{
+			"error":    "AI usage quota exceeded",
+			"period":   exceeded.Period,
+			"resource": exceeded.Resource,
+			"limit":    exceeded.Limit,
+			"used":     exceeded.Used,
+			"reset_at": exceeded.ResetAt.Format(time.RFC3339),
+		})
+	}
+}
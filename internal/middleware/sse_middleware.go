@@ -1,70 +1,246 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"log"
 	"sync"
 	"time"
 
+	"sermo-be/internal/core/session"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+// sessionRefreshInterval 레지스트리 등록 만료 연장 주기 (Redis TTL보다 충분히 짧게)
+const sessionRefreshInterval = 30 * time.Second
+
+// registryTimeout 세션 생성 시 레지스트리 조회/등록 제한 시간
+const registryTimeout = 5 * time.Second
+
 // SSESession SSE 세션 정보
 type SSESession struct {
 	SessionID   string
 	UserUUID    string
 	ChatbotUUID string
-	Channel     chan string   // 기존 채널 (클라이언트와의 통신용)
-	Done        chan struct{} // 종료 신호 전송용 채널
+	Channel     chan string // 클라이언트로 보낼 이벤트 채널 (닫지 않음, 종료는 Done()으로 감지)
+	BotChannel  chan string // 봇 고루틴 입력 채널 (스트림 재연결 시 재사용)
 	CreatedAt   time.Time
-	IsActive    bool
+
+	// StreamReplies 봇 응답을 bot_delta/bot_done 이벤트로 나눠 전송할지 여부 (false면 완성된 응답을 bot 이벤트 하나로 전송)
+	StreamReplies bool
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup // Go로 실행한 세션 고루틴 (봇 고루틴, 응답 생성)
+
+	mu          sync.Mutex
+	lastEventID uint64                     // 마지막으로 부여한 이벤트 ID
+	events      []SSEEvent                 // 재전송 버퍼 (최근 replayBufferSize개)
+	streams     map[*Subscription]struct{} // 연결된 스트림 (기기별 SSE/WebSocket 연결)
+	detachedAt  uint64                     // 마지막 스트림이 끊긴 시점의 이벤트 ID
+	detachTimer *time.Timer                // 재연결 유예 타이머
+	replyState  ReplyState                 // 봇 응답 대기 상태
+
+	lastActivity time.Time // 마지막 사용자/봇 메시지 시각 (유휴 세션 만료 기준)
+}
+
+// ReplyState 봇 응답 대기(debounce) 상태
+type ReplyState struct {
+	Pending        bool      `json:"pending"`                    // 응답 대기 중인 메시지가 있는지
+	Typing         bool      `json:"typing"`                     // 입력 중 이벤트로 연장된 상태인지
+	FirstMessageAt time.Time `json:"first_message_at,omitempty"` // 대기 중인 첫 메시지 시각
+	DueAt          time.Time `json:"due_at,omitempty"`           // 응답 예정 시각
+}
+
+// Equal 대기 상태가 같은지 비교 (시각은 time.Equal로 비교)
+func (r ReplyState) Equal(other ReplyState) bool {
+	return r.Pending == other.Pending &&
+		r.Typing == other.Typing &&
+		r.FirstMessageAt.Equal(other.FirstMessageAt) &&
+		r.DueAt.Equal(other.DueAt)
+}
+
+// ReplyState 현재 봇 응답 대기 상태
+func (s *SSESession) ReplyState() ReplyState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replyState
+}
+
+// SetReplyState 봇 응답 대기 상태 갱신 (봇 고루틴에서 호출)
+func (s *SSESession) SetReplyState(state ReplyState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replyState = state
+}
+
+// info 레지스트리에 등록할 세션 정보
+func (s *SSESession) info(instanceID string) session.Info {
+	return session.Info{
+		SessionID:   s.SessionID,
+		UserUUID:    s.UserUUID,
+		ChatbotUUID: s.ChatbotUUID,
+		InstanceID:  instanceID,
+		CreatedAt:   s.CreatedAt,
+	}
 }
 
 // SSEManager SSE 세션 관리자
+// SSE 스트림(세션 채널)은 세션을 만든 인스턴스에만 존재하고,
+// 다른 인스턴스로 들어온 메시지/종료 요청은 레지스트리로 소유자를 찾아 버스로 전달한다.
 type SSEManager struct {
 	sessions    map[string]*SSESession
 	mutex       sync.RWMutex
-	maxSessions int
+	limits      SessionLimits
+	resumeGrace time.Duration
+
+	queue    []*QueueTicket // 자리를 기다리는 대기열 (앞쪽이 먼저 입장)
+	reserved int            // 배정됐지만 아직 세션이 만들어지지 않은 자리 수 (대기자, 레지스트리 등록 중인 요청)
+
+	instanceID string
+	registry   session.Registry
+	bus        session.Bus
+	cancel     context.CancelFunc
 }
 
-// NewSSEManager 새로운 SSE 매니저 생성
+// NewSSEManager 새로운 SSE 매니저 생성 (단일 인스턴스용 메모리 레지스트리/버스 사용)
 func NewSSEManager(maxSessions int) *SSEManager {
-	return &SSEManager{
+	sm, err := NewSSEManagerWithBackend(maxSessions, session.NewMemoryRegistry(), session.NewMemoryBus())
+	if err != nil {
+		// 메모리 버스 구독은 실패하지 않음
+		panic(err)
+	}
+	return sm
+}
+
+// NewSSEManagerWithBackend 주어진 레지스트리/버스로 SSE 매니저 생성
+// 인스턴스 전용 채널을 구독하고 소유 세션의 등록 만료를 주기적으로 연장한다.
+func NewSSEManagerWithBackend(maxSessions int, registry session.Registry, bus session.Bus) (*SSEManager, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sm := &SSEManager{
 		sessions:    make(map[string]*SSESession),
-		maxSessions: maxSessions,
+		limits:      SessionLimits{MaxSessions: maxSessions},
+		resumeGrace: DefaultResumeGracePeriod,
+		instanceID:  uuid.New().String(),
+		registry:    registry,
+		bus:         bus,
+		cancel:      cancel,
+	}
+
+	if err := bus.Subscribe(ctx, sm.instanceID, sm.handleEnvelope); err != nil {
+		cancel()
+		return nil, err
 	}
+
+	go sm.refreshLoop(ctx)
+
+	return sm, nil
+}
+
+// InstanceID 현재 인스턴스 ID
+func (sm *SSEManager) InstanceID() string {
+	return sm.instanceID
 }
 
 // CreateSession 새로운 SSE 세션 생성
+// 자리가 없거나 먼저 기다리는 대기자가 있으면 ErrSessionsFull을 반환한다 (대기열은 Enqueue로 진입).
 func (sm *SSEManager) CreateSession(userUUID, chatbotUUID string) (*SSESession, error) {
+	sm.mutex.Lock()
+	// 최대 세션 수 확인 (대기자보다 먼저 들어가지 않도록 대기열도 확인)
+	if !sm.hasCapacityLocked() || len(sm.queue) > 0 {
+		sm.mutex.Unlock()
+		return nil, ErrSessionsFull
+	}
+	// 레지스트리 확인 동안 다른 요청이 같은 자리를 쓰지 않도록 예약
+	sm.reserved++
+	sm.mutex.Unlock()
+
+	return sm.createReservedSession(userUUID, chatbotUUID)
+}
+
+// createReservedSession 예약해 둔 자리로 세션 생성 (sm.mutex를 잡지 않은 상태에서 호출)
+// 레지스트리 조회/등록은 네트워크를 거칠 수 있으므로 락 밖에서 제한 시간을 두고 실행하고,
+// 결과를 맵에 반영할 때만 락을 잡는다. 실패하면 예약한 자리는 다음 대기자에게 넘어간다.
+func (sm *SSEManager) createReservedSession(userUUID, chatbotUUID string) (*SSESession, error) {
+	newSession, err := sm.claimSession(userUUID, chatbotUUID)
+
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
 
-	// 최대 세션 수 확인
-	if len(sm.sessions) >= sm.maxSessions {
-		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Maximum number of sessions reached")
+	sm.reserved--
+	if err != nil {
+		sm.promoteLocked()
+		return nil, err
+	}
+	sm.sessions[newSession.SessionID] = newSession
+	return newSession, nil
+}
+
+// claimSession 사용자별 제한 확인 후 레지스트리에 세션 소유권 등록
+// 사용자별 세션 수는 등록 전후로 확인해, 동시에 등록한 요청이 제한을 넘기면 등록을 되돌린다.
+func (sm *SSEManager) claimSession(userUUID, chatbotUUID string) (*SSESession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	// 사용자별 최대 세션 수 확인 (레지스트리 기준이므로 다른 인스턴스의 세션도 포함)
+	maxPerUser := sm.Limits().MaxSessionsPerUser
+	if maxPerUser > 0 {
+		if err := sm.checkUserSessions(ctx, userUUID, maxPerUser-1); err != nil {
+			return nil, err
+		}
 	}
 
-	// 기존 활성 세션 확인 (한 유저당 하나의 채팅봇과 하나의 세션만 허용)
-	for _, session := range sm.sessions {
-		if session.UserUUID == userUUID && session.ChatbotUUID == chatbotUUID && session.IsActive {
-			return nil, fiber.NewError(fiber.StatusConflict, "Active session already exists for this user and chatbot")
+	newSession := NewSSESession(userUUID, chatbotUUID)
+	info := newSession.info(sm.instanceID)
+
+	// 레지스트리에 소유권 등록 (한 유저당 하나의 채팅봇과 하나의 세션만 허용, 전체 인스턴스 기준)
+	if err := sm.registry.Claim(ctx, info); err != nil {
+		if errors.Is(err, session.ErrSessionExists) {
+			return nil, ErrSessionConflict
+		}
+		log.Printf("세션 등록 실패: %v", err)
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Failed to register session")
+	}
+
+	if maxPerUser > 0 {
+		if err := sm.checkUserSessions(ctx, userUUID, maxPerUser); err != nil {
+			if releaseErr := sm.registry.Release(ctx, info); releaseErr != nil {
+				log.Printf("세션 등록 해제 실패 - 세션: %s, 에러: %v", info.SessionID, releaseErr)
+			}
+			return nil, err
 		}
 	}
 
-	sessionID := uuid.New().String()
-	session := &SSESession{
-		SessionID:   sessionID,
-		UserUUID:    userUUID,
-		ChatbotUUID: chatbotUUID,
-		Channel:     make(chan string, 100), // 버퍼 크기 100
-		Done:        make(chan struct{}),
-		CreatedAt:   time.Now(),
-		IsActive:    true,
+	return newSession, nil
+}
+
+// checkUserSessions 사용자의 세션 수가 allowed를 넘으면 ErrUserSessionsFull
+func (sm *SSEManager) checkUserSessions(ctx context.Context, userUUID string, allowed int) error {
+	count, err := sm.registry.CountUser(ctx, userUUID)
+	if err != nil {
+		log.Printf("사용자 세션 수 조회 실패: %v", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Failed to check user sessions")
+	}
+	if count > allowed {
+		return ErrUserSessionsFull
 	}
+	return nil
+}
 
-	sm.sessions[sessionID] = session
-	return session, nil
+// FindSession 사용자와 채팅봇으로 활성 세션 조회 (다른 인스턴스가 소유한 세션 포함)
+// 세션이 없으면 nil을 반환하고, 레지스트리 조회 자체가 실패한 경우에만 에러를 반환한다.
+func (sm *SSEManager) FindSession(userUUID, chatbotUUID string) (*session.Info, error) {
+	info, err := sm.registry.Find(context.Background(), userUUID, chatbotUUID)
+	if errors.Is(err, session.ErrSessionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("세션 조회 실패: %v", err)
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Failed to look up session")
+	}
+	return info, nil
 }
 
 // GetSession 세션 조회
@@ -76,70 +252,67 @@ func (sm *SSEManager) GetSession(sessionID string) (*SSESession, bool) {
 	return session, exists
 }
 
-// StopSession 세션 중단 (핸들러 고루틴에 종료 신호 전송)
+// StopSession 세션 중단 (다른 인스턴스 소유 세션이면 소유자에게 종료 요청 전달)
 func (sm *SSEManager) StopSession(sessionID string) error {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
-	session, exists := sm.sessions[sessionID]
-	if !exists {
-		return fiber.NewError(fiber.StatusNotFound, "Session not found")
-	}
-
-	if !session.IsActive {
-		return fiber.NewError(fiber.StatusBadRequest, "Session already stopped")
+	if _, exists := sm.GetSession(sessionID); !exists {
+		return sm.forward(sessionID, session.Envelope{Kind: session.KindStop, SessionID: sessionID})
 	}
+	return sm.stopLocalSession(sessionID)
+}
 
-	// 세션 비활성화
-	session.IsActive = false
-
-	// 핸들러 고루틴에 종료 신호 전송
-	select {
-	case session.Done <- struct{}{}:
-		// 종료 신호 전송 성공
-	default:
-		// 채널이 가득 찬 경우 (거의 발생하지 않음)
-	}
-
-	// 채널들 닫기
-	close(session.Channel)
-	close(session.Done)
-
-	// 세션 제거
-	delete(sm.sessions, sessionID)
-
-	return nil
+// stopLocalSession 이 인스턴스가 소유한 세션 중단 (세션 context를 취소해 스트림/봇 고루틴에 종료 신호 전송)
+func (sm *SSEManager) stopLocalSession(sessionID string) error {
+	return sm.removeSession(sessionID)
 }
 
-// DeleteSession 세션 제거 (종료 신호 없이 단순 제거)
+// DeleteSession 세션 제거 (재연결 유예 만료 등 클라이언트 요청 없이 정리하는 경우)
 func (sm *SSEManager) DeleteSession(sessionID string) error {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	return sm.removeSession(sessionID)
+}
 
+// removeSession 세션을 맵에서 제거하고 종료
+// 맵에서 꺼내는 쪽이 하나뿐이므로 같은 세션에 대한 중복 종료/레지스트리 해제가 일어나지 않는다.
+func (sm *SSEManager) removeSession(sessionID string) error {
+	sm.mutex.Lock()
 	session, exists := sm.sessions[sessionID]
+	if exists {
+		delete(sm.sessions, sessionID)
+		// 빈 자리를 대기자에게 배정
+		sm.promoteLocked()
+	}
+	sm.mutex.Unlock()
+
 	if !exists {
 		return fiber.NewError(fiber.StatusNotFound, "Session not found")
 	}
 
-	if !session.IsActive {
+	if !session.close() {
 		return fiber.NewError(fiber.StatusBadRequest, "Session already stopped")
 	}
-
-	// 세션 비활성화
-	session.IsActive = false
-
-	// 채널들 닫기
-	close(session.Channel)
-	close(session.Done)
-
-	// 세션 제거
-	delete(sm.sessions, sessionID)
+	sm.release(session)
 
 	return nil
 }
 
-// SendMessage 세션에 메시지 전송 (기존 메서드, 호환성 유지)
+// SendMessage 세션에 메시지 전송 (다른 인스턴스 소유 세션이면 소유자에게 전달)
 func (sm *SSEManager) SendMessage(sessionID, message string) error {
+	if _, exists := sm.GetSession(sessionID); !exists {
+		return sm.forward(sessionID, session.Envelope{Kind: session.KindMessage, SessionID: sessionID, Data: message})
+	}
+	return sm.sendLocalMessage(sessionID, message, false)
+}
+
+// SendUserMessage 세션에 사용자 메시지 전송 (세션 활동으로 기록해 유휴 만료를 늦춤)
+// 입력 중(onkeyboard), 즉시 응답(flush) 같은 제어 이벤트는 SendMessage로 보내며 활동으로 치지 않는다.
+func (sm *SSEManager) SendUserMessage(sessionID, message string) error {
+	if _, exists := sm.GetSession(sessionID); !exists {
+		return sm.forward(sessionID, session.Envelope{Kind: session.KindUserMessage, SessionID: sessionID, Data: message})
+	}
+	return sm.sendLocalMessage(sessionID, message, true)
+}
+
+// sendLocalMessage 이 인스턴스가 소유한 세션 채널에 메시지 전송 (activity면 세션 활동 시각 갱신)
+func (sm *SSEManager) sendLocalMessage(sessionID, message string, activity bool) error {
 	sm.mutex.RLock()
 	session, exists := sm.sessions[sessionID]
 	sm.mutex.RUnlock()
@@ -148,13 +321,14 @@ func (sm *SSEManager) SendMessage(sessionID, message string) error {
 		return fiber.NewError(fiber.StatusNotFound, "Session not found")
 	}
 
-	if !session.IsActive {
-		return fiber.NewError(fiber.StatusBadRequest, "Session is not active")
-	}
-
-	select {
-	case session.Channel <- message:
+	switch err := session.Send(message); {
+	case err == nil:
+		if activity {
+			session.Touch()
+		}
 		return nil
+	case errors.Is(err, ErrSessionClosed):
+		return fiber.NewError(fiber.StatusBadRequest, "Session is not active")
 	default:
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to send message to session")
 	}
@@ -167,97 +341,156 @@ func (sm *SSEManager) GetActiveSessionsCount() int {
 	return len(sm.sessions)
 }
 
-// GetUserSessions 사용자의 활성 세션 조회
+// GetUserSessions 이 인스턴스가 소유한 사용자의 활성 세션 조회
 func (sm *SSEManager) GetUserSessions(userUUID string) []*SSESession {
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
 
 	var userSessions []*SSESession
 	for _, session := range sm.sessions {
-		if session.UserUUID == userUUID && session.IsActive {
+		if session.UserUUID == userUUID && session.IsActive() {
 			userSessions = append(userSessions, session)
 		}
 	}
 	return userSessions
 }
 
-// FindSessionByUserAndChatbot 사용자와 채팅봇으로 세션 찾기
-func (sm *SSEManager) FindSessionByUserAndChatbot(userUUID, chatbotUUID string) *SSESession {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
+// forward 다른 인스턴스가 소유한 세션으로 메시지 전달
+func (sm *SSEManager) forward(sessionID string, envelope session.Envelope) error {
+	ctx := context.Background()
 
-	for _, session := range sm.sessions {
-		if session.UserUUID == userUUID && session.ChatbotUUID == chatbotUUID && session.IsActive {
-			return session
+	info, err := sm.registry.Get(ctx, sessionID)
+	if errors.Is(err, session.ErrSessionNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Session not found")
+	}
+	if err != nil {
+		log.Printf("세션 조회 실패 - 세션: %s, 에러: %v", sessionID, err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Failed to look up session")
+	}
+
+	if info.InstanceID == sm.instanceID {
+		// 레지스트리에는 남아 있지만 이 인스턴스에서는 이미 정리된 세션
+		return fiber.NewError(fiber.StatusNotFound, "Session not found")
+	}
+
+	if err := sm.bus.Publish(ctx, info.InstanceID, envelope); err != nil {
+		if errors.Is(err, session.ErrNoSubscriber) {
+			// 소유 인스턴스가 사라진 경우 남은 등록 정리
+			sm.registry.Release(ctx, *info)
+			return fiber.NewError(fiber.StatusNotFound, "Session not found")
 		}
+		log.Printf("세션 메시지 전달 실패 - 세션: %s, 에러: %v", sessionID, err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Failed to deliver message to session")
 	}
 	return nil
 }
 
-// CleanupInactiveSessions 비활성 세션 정리
-func (sm *SSEManager) CleanupInactiveSessions() {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+// handleEnvelope 다른 인스턴스에서 전달된 메시지 처리
+func (sm *SSEManager) handleEnvelope(envelope session.Envelope) {
+	var err error
+	switch envelope.Kind {
+	case session.KindMessage:
+		err = sm.sendLocalMessage(envelope.SessionID, envelope.Data, false)
+	case session.KindUserMessage:
+		err = sm.sendLocalMessage(envelope.SessionID, envelope.Data, true)
+	case session.KindStop:
+		err = sm.stopLocalSession(envelope.SessionID)
+	default:
+		log.Printf("알 수 없는 세션 버스 메시지 종류: %s", envelope.Kind)
+		return
+	}
 
-	now := time.Now()
-	var sessionsToDelete []string
+	if err != nil {
+		log.Printf("세션 버스 메시지 처리 실패 - 세션: %s, 종류: %s, 에러: %v", envelope.SessionID, envelope.Kind, err)
+	}
+}
 
-	// 삭제할 세션 ID 수집
-	for sessionID, session := range sm.sessions {
-		// 30분 이상 비활성인 세션 정리
-		if now.Sub(session.CreatedAt) > 30*time.Minute {
-			sessionsToDelete = append(sessionsToDelete, sessionID)
-		}
+// release 레지스트리에서 세션 등록 해제
+func (sm *SSEManager) release(s *SSESession) {
+	if err := sm.registry.Release(context.Background(), s.info(sm.instanceID)); err != nil {
+		log.Printf("세션 등록 해제 실패 - 세션: %s, 에러: %v", s.SessionID, err)
 	}
+}
 
-	// 뮤텍스 해제 후 DeleteSession 호출
-	sm.mutex.Unlock()
+// refreshLoop 소유 세션의 레지스트리 등록 만료를 주기적으로 연장
+func (sm *SSEManager) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(sessionRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sm.mutex.RLock()
+			infos := make([]session.Info, 0, len(sm.sessions))
+			for _, s := range sm.sessions {
+				infos = append(infos, s.info(sm.instanceID))
+			}
+			sm.mutex.RUnlock()
 
-	for _, sessionID := range sessionsToDelete {
-		sm.DeleteSession(sessionID)
+			if err := sm.registry.Refresh(ctx, infos); err != nil {
+				log.Printf("세션 등록 갱신 실패: %v", err)
+			}
+		}
 	}
 }
 
 // Shutdown 모든 SSE 세션 정리 (서버 종료 시 사용)
+// 모든 세션을 종료한 뒤 세션에 묶인 고루틴(스트림은 제외)이 끝나기를 잠시 기다린다.
 func (sm *SSEManager) Shutdown() {
 	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	sessions := sm.sessions
+	sm.sessions = make(map[string]*SSESession)
+
+	// 대기자 내보내기 (대기 중인 스트림은 순번이 사라진 것을 보고 종료)
+	for _, ticket := range sm.queue {
+		ticket.left = true
+		ticket.signal()
+	}
+	sm.queue = nil
+	sm.mutex.Unlock()
 
-	log.Printf("SSE Manager 종료 시작 - 활성 세션 수: %d", len(sm.sessions))
+	log.Printf("SSE Manager 종료 시작 - 활성 세션 수: %d", len(sessions))
 
 	// 모든 활성 세션에 종료 신호 전송
-	for sessionID, session := range sm.sessions {
-		if session.IsActive {
+	for sessionID, session := range sessions {
+		if session.close() {
 			log.Printf("세션 종료 신호 전송 - 세션: %s", sessionID)
+		}
+		sm.release(session)
+	}
 
-			// 세션 비활성화
-			session.IsActive = false
-
-			// 핸들러 고루틴에 종료 신호 전송
-			select {
-			case session.Done <- struct{}{}:
-				// 종료 신호 전송 성공
-			default:
-				// 채널이 가득 찬 경우
-			}
-
-			// 채널들 닫기
-			close(session.Channel)
-			close(session.Done)
+	// 세션 고루틴 종료 대기 (진행 중인 응답 생성은 context 취소로 곧 끝남)
+	deadline := time.Now().Add(shutdownWaitTimeout)
+	for sessionID, session := range sessions {
+		if !session.Wait(time.Until(deadline)) {
+			log.Printf("세션 고루틴 종료 대기 시간 초과 - 세션: %s", sessionID)
 		}
 	}
 
-	// 세션 맵 초기화
-	sm.sessions = make(map[string]*SSESession)
+	// 버스 구독 및 등록 갱신 중단
+	sm.cancel()
 	log.Printf("SSE Manager 종료 완료")
 }
 
-// 전역 SSE 매니저 인스턴스
-var globalSSEManager = NewSSEManager(20) // 최대 20개 세션
+// DefaultMaxSessions 인스턴스당 최대 동시 세션 수 기본값 (서버 시작 시 SetLimits로 설정값 적용)
+const DefaultMaxSessions = 20
+
+// SSEManagerMiddleware SSE 매니저를 요청 컨텍스트에 주입하는 미들웨어
+func SSEManagerMiddleware(sm *SSEManager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("sse_manager", sm)
+		return c.Next()
+	}
+}
 
-// GetSSEManager 전역 SSE 매니저 반환
-func GetSSEManager() *SSEManager {
-	return globalSSEManager
+// GetSSEManager 요청 컨텍스트에서 SSE 매니저 가져오기
+func GetSSEManager(c *fiber.Ctx) *SSEManager {
+	if sm, ok := c.Locals("sse_manager").(*SSEManager); ok {
+		return sm
+	}
+	return nil
 }
 
 // SSEHeaders SSE 응답 헤더 설정
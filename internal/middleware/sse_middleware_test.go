@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/session"
+)
+
+// newInstances 같은 레지스트리/버스를 공유하는 두 인스턴스 (서로 다른 파드 흉내)
+func newInstances(t *testing.T) (*SSEManager, *SSEManager) {
+	t.Helper()
+
+	registry := session.NewMemoryRegistry()
+	bus := session.NewMemoryBus()
+
+	podA, err := NewSSEManagerWithBackend(DefaultMaxSessions, registry, bus)
+	if err != nil {
+		t.Fatalf("NewSSEManagerWithBackend: %v", err)
+	}
+	podB, err := NewSSEManagerWithBackend(DefaultMaxSessions, registry, bus)
+	if err != nil {
+		t.Fatalf("NewSSEManagerWithBackend: %v", err)
+	}
+	t.Cleanup(func() {
+		podA.Shutdown()
+		podB.Shutdown()
+	})
+	return podA, podB
+}
+
+func TestSendMessageRoutesToOwningInstance(t *testing.T) {
+	podA, podB := newInstances(t)
+
+	owned, err := podA.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	info, err := podB.FindSession("user-1", "bot-1")
+	if err != nil || info == nil {
+		t.Fatalf("FindSession on other instance: info=%v err=%v", info, err)
+	}
+	if info.InstanceID != podA.InstanceID() {
+		t.Errorf("expected owner %s, got %s", podA.InstanceID(), info.InstanceID)
+	}
+
+	if err := podB.SendMessage(info.SessionID, "data: hello\n\n"); err != nil {
+		t.Fatalf("SendMessage via other instance: %v", err)
+	}
+
+	select {
+	case message := <-owned.Channel:
+		if message != "data: hello\n\n" {
+			t.Errorf("unexpected message %q", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered to owning instance")
+	}
+}
+
+func TestCreateSessionConflictsAcrossInstances(t *testing.T) {
+	podA, podB := newInstances(t)
+
+	if _, err := podA.CreateSession("user-1", "bot-1"); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if _, err := podB.CreateSession("user-1", "bot-1"); err == nil {
+		t.Fatal("expected conflict for second session on another instance")
+	}
+}
+
+func TestStopSessionFromOtherInstance(t *testing.T) {
+	podA, podB := newInstances(t)
+
+	owned, err := podA.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	if err := podB.StopSession(owned.SessionID); err != nil {
+		t.Fatalf("StopSession via other instance: %v", err)
+	}
+
+	if _, exists := podA.GetSession(owned.SessionID); exists {
+		t.Error("session still registered on owning instance")
+	}
+	if info, _ := podB.FindSession("user-1", "bot-1"); info != nil {
+		t.Error("session still present in registry")
+	}
+	if _, err := podB.CreateSession("user-1", "bot-1"); err != nil {
+		t.Errorf("expected new session after stop, got %v", err)
+	}
+}
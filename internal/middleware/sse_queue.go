@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// ErrSessionsFull 인스턴스의 최대 동시 세션 수에 도달한 경우 (대기열이 켜져 있으면 대기열로 안내)
+	ErrSessionsFull = fiber.NewError(fiber.StatusServiceUnavailable, "Maximum number of sessions reached")
+	// ErrUserSessionsFull 사용자별 최대 동시 세션 수에 도달한 경우
+	ErrUserSessionsFull = fiber.NewError(fiber.StatusTooManyRequests, "Maximum number of sessions for this user reached")
+	// ErrWaitingRoomFull 대기열이 가득 찬 경우
+	ErrWaitingRoomFull = fiber.NewError(fiber.StatusServiceUnavailable, "Waiting room is full")
+)
+
+// SessionLimits 세션 수 제한과 대기열 설정 (모두 인스턴스 기준)
+type SessionLimits struct {
+	MaxSessions        int           // 최대 동시 세션 수
+	MaxSessionsPerUser int           // 사용자별 최대 동시 세션 수 (레지스트리 기준, 모든 인스턴스 합산, 0이면 제한 없음)
+	MaxQueueLength     int           // 자리가 날 때까지 기다릴 수 있는 최대 대기 인원 (0이면 대기열 없이 바로 거절)
+	QueueTimeout       time.Duration // 대기열에서 기다리는 최대 시간
+}
+
+// QueueTicket 대기열 순번표
+type QueueTicket struct {
+	UserUUID    string
+	ChatbotUUID string
+	EnqueuedAt  time.Time
+
+	notify   chan struct{} // 순번 변경 또는 입장 알림
+	admitted bool          // 자리가 배정됨 (sm.mutex로 보호)
+	left     bool          // 대기열에서 빠짐 (sm.mutex로 보호)
+}
+
+// Notify 순번이 바뀌거나 입장할 수 있게 되면 신호를 받는 채널
+func (t *QueueTicket) Notify() <-chan struct{} {
+	return t.notify
+}
+
+// signal 대기자에게 알림 (이미 알림이 쌓여 있으면 생략)
+func (t *QueueTicket) signal() {
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+// SetLimits 세션 수 제한과 대기열 설정 변경 (서버 시작 시 설정값 적용)
+func (sm *SSEManager) SetLimits(limits SessionLimits) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.limits = limits
+	sm.promoteLocked()
+}
+
+// Limits 현재 세션 수 제한 설정
+func (sm *SSEManager) Limits() SessionLimits {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return sm.limits
+}
+
+// Enqueue 대기열에 들어가기 (대기열이 꺼져 있으면 ErrSessionsFull, 가득 찼으면 ErrWaitingRoomFull)
+func (sm *SSEManager) Enqueue(userUUID, chatbotUUID string) (*QueueTicket, error) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.limits.MaxQueueLength <= 0 {
+		return nil, ErrSessionsFull
+	}
+	if len(sm.queue) >= sm.limits.MaxQueueLength {
+		return nil, ErrWaitingRoomFull
+	}
+
+	ticket := &QueueTicket{
+		UserUUID:    userUUID,
+		ChatbotUUID: chatbotUUID,
+		EnqueuedAt:  time.Now(),
+		notify:      make(chan struct{}, 1),
+	}
+	sm.queue = append(sm.queue, ticket)
+	sm.promoteLocked()
+
+	return ticket, nil
+}
+
+// QueuePosition 대기 순번 조회 (1부터 시작, 자리가 배정됐으면 admitted가 true)
+func (sm *SSEManager) QueuePosition(ticket *QueueTicket) (position int, admitted bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	if ticket.admitted {
+		return 0, true
+	}
+	for i, queued := range sm.queue {
+		if queued == ticket {
+			return i + 1, false
+		}
+	}
+	return 0, false
+}
+
+// CreateQueuedSession 자리가 배정된 순번표로 세션 생성
+// 배정된 자리는 이 순번표만 사용할 수 있으며, 세션 생성에 실패하면 다음 대기자에게 넘어간다.
+func (sm *SSEManager) CreateQueuedSession(ticket *QueueTicket) (*SSESession, error) {
+	sm.mutex.Lock()
+	if !ticket.admitted {
+		sm.mutex.Unlock()
+		return nil, ErrSessionsFull
+	}
+	// 배정된 자리(reserved)는 세션 생성이 끝날 때까지 유지
+	ticket.admitted = false
+	ticket.left = true
+	sm.mutex.Unlock()
+
+	return sm.createReservedSession(ticket.UserUUID, ticket.ChatbotUUID)
+}
+
+// LeaveQueue 대기열에서 빠지기 (연결 종료, 대기 시간 초과 등)
+// 이미 자리가 배정된 경우에는 그 자리를 다음 대기자에게 넘긴다.
+func (sm *SSEManager) LeaveQueue(ticket *QueueTicket) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if ticket.left {
+		return
+	}
+	ticket.left = true
+
+	if ticket.admitted {
+		ticket.admitted = false
+		sm.reserved--
+	} else {
+		for i, queued := range sm.queue {
+			if queued == ticket {
+				sm.queue = append(sm.queue[:i], sm.queue[i+1:]...)
+				break
+			}
+		}
+		// 뒤에 있던 대기자들의 순번이 당겨짐
+		for _, queued := range sm.queue {
+			queued.signal()
+		}
+	}
+	sm.promoteLocked()
+}
+
+// QueueLength 현재 대기 인원
+func (sm *SSEManager) QueueLength() int {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return len(sm.queue)
+}
+
+// hasCapacityLocked 새 세션을 만들 자리가 있는지 (배정만 되고 아직 생성되지 않은 자리 포함, sm.mutex를 잡은 상태에서 호출)
+func (sm *SSEManager) hasCapacityLocked() bool {
+	return len(sm.sessions)+sm.reserved < sm.limits.MaxSessions
+}
+
+// promoteLocked 빈 자리만큼 대기열 앞사람에게 자리 배정 (sm.mutex를 잡은 상태에서 호출)
+func (sm *SSEManager) promoteLocked() {
+	promoted := false
+	for len(sm.queue) > 0 && sm.hasCapacityLocked() {
+		ticket := sm.queue[0]
+		sm.queue = sm.queue[1:]
+
+		ticket.admitted = true
+		sm.reserved++
+		ticket.signal()
+		promoted = true
+	}
+
+	if promoted {
+		for _, queued := range sm.queue {
+			queued.signal()
+		}
+	}
+}
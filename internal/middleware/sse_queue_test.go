@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/session"
+)
+
+func newLimitedManager(t *testing.T, limits SessionLimits) *SSEManager {
+	t.Helper()
+
+	sm := NewSSEManager(limits.MaxSessions)
+	sm.SetLimits(limits)
+	t.Cleanup(sm.Shutdown)
+	return sm
+}
+
+// expectNotified 순번표 알림 확인
+func expectNotified(t *testing.T, ticket *QueueTicket) {
+	t.Helper()
+
+	select {
+	case <-ticket.Notify():
+	case <-time.After(time.Second):
+		t.Fatal("ticket was not notified")
+	}
+}
+
+func TestPerUserSessionLimit(t *testing.T) {
+	sm := newLimitedManager(t, SessionLimits{MaxSessions: 10, MaxSessionsPerUser: 1})
+
+	if _, err := sm.CreateSession("user-1", "bot-1"); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if _, err := sm.CreateSession("user-1", "bot-2"); !errors.Is(err, ErrUserSessionsFull) {
+		t.Fatalf("expected ErrUserSessionsFull, got %v", err)
+	}
+	if _, err := sm.CreateSession("user-2", "bot-1"); err != nil {
+		t.Errorf("other users must not be limited, got %v", err)
+	}
+}
+
+func TestPerUserSessionLimitSpansInstances(t *testing.T) {
+	podA, podB := newInstances(t)
+	limits := SessionLimits{MaxSessions: 10, MaxSessionsPerUser: 2}
+	podA.SetLimits(limits)
+	podB.SetLimits(limits)
+
+	if _, err := podA.CreateSession("user-1", "bot-1"); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	second, err := podB.CreateSession("user-1", "bot-2")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	// 다른 인스턴스의 세션까지 합쳐 제한
+	if _, err := podA.CreateSession("user-1", "bot-3"); !errors.Is(err, ErrUserSessionsFull) {
+		t.Fatalf("expected ErrUserSessionsFull, got %v", err)
+	}
+
+	podB.DeleteSession(second.SessionID)
+	if _, err := podA.CreateSession("user-1", "bot-3"); err != nil {
+		t.Errorf("slot released on another instance must be reusable, got %v", err)
+	}
+}
+
+// slowRegistry release가 닫힐 때까지 Claim을 붙잡는 레지스트리 (느린 Redis 흉내)
+type slowRegistry struct {
+	session.Registry
+	claiming chan struct{}
+	release  chan struct{}
+}
+
+func (r *slowRegistry) Claim(ctx context.Context, info session.Info) error {
+	r.claiming <- struct{}{}
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return r.Registry.Claim(ctx, info)
+}
+
+func TestSlowRegistryDoesNotBlockManager(t *testing.T) {
+	registry := &slowRegistry{
+		Registry: session.NewMemoryRegistry(),
+		claiming: make(chan struct{}, 1),
+		release:  make(chan struct{}),
+	}
+	sm, err := NewSSEManagerWithBackend(1, registry, session.NewMemoryBus())
+	if err != nil {
+		t.Fatalf("NewSSEManagerWithBackend: %v", err)
+	}
+	t.Cleanup(sm.Shutdown)
+
+	created := make(chan error, 1)
+	go func() {
+		_, err := sm.CreateSession("user-1", "bot-1")
+		created <- err
+	}()
+	<-registry.claiming
+
+	// 레지스트리 호출이 걸려 있는 동안에도 매니저의 다른 작업은 막히지 않아야 함
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sm.GetActiveSessionsCount()
+		sm.QueueLength()
+		sm.GetSession("unknown")
+		// 등록 중인 요청이 자리를 예약했으므로 바로 거절
+		if _, err := sm.CreateSession("user-2", "bot-1"); !errors.Is(err, ErrSessionsFull) {
+			t.Errorf("expected ErrSessionsFull while the only slot is being claimed, got %v", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("manager blocked while the registry call was in flight")
+	}
+
+	close(registry.release)
+	if err := <-created; err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if count := sm.GetActiveSessionsCount(); count != 1 {
+		t.Errorf("active sessions = %d, want 1", count)
+	}
+}
+
+func TestConcurrentCreatesRespectPerUserLimit(t *testing.T) {
+	sm := newLimitedManager(t, SessionLimits{MaxSessions: 20, MaxSessionsPerUser: 1})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := sm.CreateSession("user-1", fmt.Sprintf("bot-%d", i))
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrUserSessionsFull):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if created > 1 {
+		t.Fatalf("created %d sessions, want at most 1", created)
+	}
+
+	// 되돌린 등록이 남지 않아야 함
+	count, err := sm.registry.CountUser(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("CountUser: %v", err)
+	}
+	if count != created || sm.GetActiveSessionsCount() != created {
+		t.Errorf("registry count = %d, active = %d, want %d", count, sm.GetActiveSessionsCount(), created)
+	}
+}
+
+func TestWaitingRoomAdmitsInOrder(t *testing.T) {
+	sm := newLimitedManager(t, SessionLimits{MaxSessions: 1, MaxQueueLength: 2, QueueTimeout: time.Minute})
+
+	first, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if _, err := sm.CreateSession("user-2", "bot-1"); !errors.Is(err, ErrSessionsFull) {
+		t.Fatalf("expected ErrSessionsFull, got %v", err)
+	}
+
+	second, err := sm.Enqueue("user-2", "bot-1")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	third, err := sm.Enqueue("user-3", "bot-1")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := sm.Enqueue("user-4", "bot-1"); !errors.Is(err, ErrWaitingRoomFull) {
+		t.Fatalf("expected ErrWaitingRoomFull, got %v", err)
+	}
+	if position, _ := sm.QueuePosition(third); position != 2 {
+		t.Fatalf("expected position 2, got %d", position)
+	}
+
+	// 자리가 나면 맨 앞 대기자에게 배정되고 뒤 대기자의 순번이 당겨짐
+	if err := sm.StopSession(first.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	expectNotified(t, second)
+	if _, admitted := sm.QueuePosition(second); !admitted {
+		t.Fatal("head of queue was not admitted")
+	}
+	expectNotified(t, third)
+	if position, _ := sm.QueuePosition(third); position != 1 {
+		t.Errorf("expected position 1, got %d", position)
+	}
+
+	// 배정된 자리는 새로 온 요청이 가져갈 수 없음
+	if _, err := sm.CreateSession("user-5", "bot-1"); !errors.Is(err, ErrSessionsFull) {
+		t.Errorf("newcomer took a reserved slot, got %v", err)
+	}
+
+	s, err := sm.CreateQueuedSession(second)
+	if err != nil {
+		t.Fatalf("CreateQueuedSession: %v", err)
+	}
+	if s.UserUUID != "user-2" {
+		t.Errorf("unexpected session owner %s", s.UserUUID)
+	}
+}
+
+func TestLeavingAdmittedTicketPassesSlotOn(t *testing.T) {
+	sm := newLimitedManager(t, SessionLimits{MaxSessions: 1, MaxQueueLength: 5, QueueTimeout: time.Minute})
+
+	first, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	second, _ := sm.Enqueue("user-2", "bot-1")
+	third, _ := sm.Enqueue("user-3", "bot-1")
+
+	sm.StopSession(first.SessionID)
+	if _, admitted := sm.QueuePosition(second); !admitted {
+		t.Fatal("second was not admitted")
+	}
+
+	// 배정받은 대기자가 연결을 끊으면 다음 대기자에게 자리가 넘어감
+	sm.LeaveQueue(second)
+	if _, admitted := sm.QueuePosition(third); !admitted {
+		t.Fatal("slot was not passed to the next ticket")
+	}
+	if _, err := sm.CreateQueuedSession(second); err == nil {
+		t.Error("ticket that left the queue must not create a session")
+	}
+	if _, err := sm.CreateQueuedSession(third); err != nil {
+		t.Errorf("CreateQueuedSession: %v", err)
+	}
+}
+
+func TestEnqueueWithoutWaitingRoom(t *testing.T) {
+	sm := newLimitedManager(t, SessionLimits{MaxSessions: 1})
+
+	if _, err := sm.Enqueue("user-1", "bot-1"); !errors.Is(err, ErrSessionsFull) {
+		t.Errorf("expected ErrSessionsFull when waiting room is disabled, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Touch 세션 활동 시각 갱신 (사용자/봇 메시지가 오갈 때)
+func (s *SSESession) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = time.Now()
+}
+
+// LastActivity 마지막 활동 시각
+func (s *SSESession) LastActivity() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastActivity
+}
+
+// newSessionExpiredFrame 세션 만료(session_expired) 이벤트 SSE 프레임 생성
+func newSessionExpiredFrame(s *SSESession, idleTimeout time.Duration) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":                 "session_expired",
+		"session_id":           s.SessionID,
+		"idle_timeout_seconds": int(idleTimeout.Seconds()),
+		"timestamp":            time.Now().Format(time.RFC3339),
+	})
+	return fmt.Sprintf("data: %s\n\n", string(data))
+}
+
+// ExpireIdleSessions idleTimeout 동안 활동이 없는 세션을 만료시키고 만료된 세션 목록 반환
+// 종료 직전에 session_expired 이벤트를 보내며, 스트림은 종료 신호를 받은 뒤 남은 이벤트를 전송하고 끝난다.
+func (sm *SSEManager) ExpireIdleSessions(idleTimeout time.Duration) []*SSESession {
+	cutoff := time.Now().Add(-idleTimeout)
+
+	// 만료 대상 수집
+	sm.mutex.RLock()
+	var candidates []*SSESession
+	for _, session := range sm.sessions {
+		if session.LastActivity().Before(cutoff) {
+			candidates = append(candidates, session)
+		}
+	}
+	sm.mutex.RUnlock()
+
+	// 락을 잡지 않은 상태에서 종료 (removeSession이 다시 락을 잡음)
+	var expired []*SSESession
+	for _, session := range candidates {
+		// 수집 이후 새 메시지가 오간 세션은 유지
+		if !session.LastActivity().Before(cutoff) {
+			continue
+		}
+
+		if err := session.Send(newSessionExpiredFrame(session, idleTimeout)); err != nil {
+			log.Printf("session_expired 이벤트 전송 실패 - 세션: %s, 에러: %v", session.SessionID, err)
+		}
+		if err := sm.removeSession(session.SessionID); err != nil {
+			continue
+		}
+
+		log.Printf("유휴 세션 만료 - 세션: %s, 마지막 활동: %s", session.SessionID, session.LastActivity().Format(time.RFC3339))
+		expired = append(expired, session)
+	}
+	return expired
+}
+
+// RunReaper interval마다 유휴 세션을 만료시키는 루프 (ctx가 취소될 때까지 실행)
+// onExpired는 만료된 세션마다 호출되며 /chat/stop과 같은 종료 후처리를 맡는다.
+func (sm *SSEManager) RunReaper(ctx context.Context, interval, idleTimeout time.Duration, onExpired func(*SSESession)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, session := range sm.ExpireIdleSessions(idleTimeout) {
+				if onExpired != nil {
+					onExpired(session)
+				}
+			}
+		}
+	}
+}
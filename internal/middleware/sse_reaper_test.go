@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setLastActivity 테스트용 마지막 활동 시각 설정
+func setLastActivity(s *SSESession, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = at
+}
+
+func TestExpireIdleSessionsUsesLastActivity(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	idle, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	lively, err := sm.CreateSession("user-2", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	// 오래전에 시작됐지만 방금 메시지가 오간 세션은 유지되어야 함
+	lively.CreatedAt = time.Now().Add(-time.Hour)
+	setLastActivity(lively, time.Now().Add(-time.Hour))
+	if err := sm.SendUserMessage(lively.SessionID, `data: {"type":"user","content":"hello"}`+"\n\n"); err != nil {
+		t.Fatalf("SendUserMessage: %v", err)
+	}
+	setLastActivity(idle, time.Now().Add(-31*time.Minute))
+
+	expired := sm.ExpireIdleSessions(30 * time.Minute)
+	if len(expired) != 1 || expired[0] != idle {
+		t.Fatalf("expected only the idle session to expire, got %v", expired)
+	}
+	if idle.IsActive() {
+		t.Error("expired session still active")
+	}
+	if !lively.IsActive() {
+		t.Error("session with recent activity was expired")
+	}
+
+	select {
+	case frame := <-idle.Channel:
+		if !strings.Contains(frame, `"type":"session_expired"`) {
+			t.Errorf("expected session_expired event, got %q", frame)
+		}
+	default:
+		t.Error("session_expired event was not sent")
+	}
+
+	if _, err := sm.CreateSession("user-1", "bot-1"); err != nil {
+		t.Errorf("expected new session after expiry, got %v", err)
+	}
+}
+
+func TestExpireIdleSessionsIgnoresControlEvents(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	setLastActivity(s, time.Now().Add(-31*time.Minute))
+
+	// 입력 중 이벤트와 봇이 보내는 제어 이벤트만 오가는 세션은 활동이 없는 것으로 봄
+	for _, frame := range []string{
+		`data: {"type":"onkeyboard"}` + "\n\n",
+		`data: {"type":"flush"}` + "\n\n",
+	} {
+		if err := sm.SendMessage(s.SessionID, frame); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	for _, frame := range []string{
+		`data: {"type":"bot_typing"}` + "\n\n",
+		`data: {"type":"bot_delta","content":"he"}` + "\n\n",
+		`data: {"type":"reply_state","pending":true}` + "\n\n",
+	} {
+		if err := s.Send(frame); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	expired := sm.ExpireIdleSessions(30 * time.Minute)
+	if len(expired) != 1 || expired[0] != s {
+		t.Fatalf("expected the session with only control events to expire, got %v", expired)
+	}
+}
+
+func TestRunReaperCallsOnExpired(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	setLastActivity(s, time.Now().Add(-time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expired := make(chan *SSESession, 1)
+	go sm.RunReaper(ctx, 5*time.Millisecond, time.Minute, func(s *SSESession) {
+		expired <- s
+	})
+
+	select {
+	case got := <-expired:
+		if got != s {
+			t.Errorf("unexpected expired session %s", got.SessionID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reaper did not expire idle session")
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// replayBufferSize 세션별로 보관하는 최근 이벤트 수 (Last-Event-ID 재전송용)
+	replayBufferSize = 100
+	// DefaultResumeGracePeriod 스트림이 끊긴 세션을 재연결을 위해 유지하는 시간
+	DefaultResumeGracePeriod = 2 * time.Minute
+)
+
+// SSEEvent 클라이언트로 전송된 이벤트 (재전송 버퍼에 보관)
+type SSEEvent struct {
+	ID   uint64
+	Data string // "data: {json}\n\n" 형식의 SSE 프레임
+}
+
+// Frame id 필드를 붙인 SSE 프레임
+func (e SSEEvent) Frame() string {
+	return fmt.Sprintf("id: %d\n%s", e.ID, e.Data)
+}
+
+// ParseLastEventID Last-Event-ID 값 파싱 (비어 있거나 숫자가 아니면 false)
+func ParseLastEventID(value string) (uint64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecordEventAssignsIncreasingIDs(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	first := s.RecordEvent("data: one\n\n")
+	second := s.RecordEvent("data: two\n\n")
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("expected ids 1, 2, got %d, %d", first.ID, second.ID)
+	}
+	if got := second.Frame(); got != "id: 2\ndata: two\n\n" {
+		t.Errorf("unexpected frame %q", got)
+	}
+
+	events := s.EventsSince(1)
+	if len(events) != 1 || events[0].ID != 2 {
+		t.Errorf("expected only event 2 after id 1, got %+v", events)
+	}
+}
+
+func TestEventsSinceKeepsOnlyRecentEvents(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	for i := 0; i < replayBufferSize+10; i++ {
+		s.RecordEvent(fmt.Sprintf("data: %d\n\n", i))
+	}
+
+	events := s.EventsSince(0)
+	if len(events) != replayBufferSize {
+		t.Fatalf("expected %d buffered events, got %d", replayBufferSize, len(events))
+	}
+	if events[0].ID != 11 {
+		t.Errorf("expected oldest buffered id 11, got %d", events[0].ID)
+	}
+}
+
+func TestResumeDetachedSession(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	stream := sm.AttachStream(s)
+
+	if _, _, _, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReject}); err != ErrSessionConflict {
+		t.Fatalf("attached session must not be resumed without takeover, got %v", err)
+	}
+
+	s.RecordEvent("data: before\n\n")
+	sm.DetachStream(s, stream)
+	s.RecordEvent("data: missed\n\n")
+
+	// Last-Event-ID 없이 다시 연결해도 끊긴 동안 쌓인 이벤트는 재전송
+	resumed, next, replay, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReject})
+	if err != nil || resumed != s {
+		t.Fatalf("expected detached session to be resumed, got %v (err=%v)", resumed, err)
+	}
+	if next == nil {
+		t.Fatal("expected new subscription")
+	}
+	if len(replay) != 1 || replay[0].Data != "data: missed\n\n" {
+		t.Errorf("expected only the missed event to be replayed, got %+v", replay)
+	}
+
+	// 이미 끊긴 스트림의 DetachStream은 새 스트림에 영향을 주지 않아야 함
+	sm.DetachStream(s, stream)
+	if _, _, _, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReject}); err != ErrSessionConflict {
+		t.Error("stale stream detached the resumed session")
+	}
+}
+
+func TestResumeTakeoverClosesPreviousStream(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	stream := sm.AttachStream(s)
+	s.RecordEvent("data: one\n\n")
+
+	resumed, _, replay, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReplace, LastEventID: 0, HasEventID: true})
+	if err != nil || resumed != s {
+		t.Fatalf("expected takeover of attached session, got %v (err=%v)", resumed, err)
+	}
+	if len(replay) != 1 {
+		t.Errorf("expected events after Last-Event-ID to be replayed, got %d", len(replay))
+	}
+
+	select {
+	case <-stream.Closed():
+		if stream.Reason() != StreamReplaced {
+			t.Errorf("unexpected close reason %q", stream.Reason())
+		}
+	default:
+		t.Error("previous stream was not signalled")
+	}
+}
+
+func TestDetachedSessionExpiresAfterGracePeriod(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	sm.resumeGrace = 10 * time.Millisecond
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	sm.DetachStream(s, sm.AttachStream(s))
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, exists := sm.GetSession(s.SessionID); !exists {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("detached session was not removed after grace period")
+}
+
+func TestParseLastEventID(t *testing.T) {
+	if id, ok := ParseLastEventID(" 42 "); !ok || id != 42 {
+		t.Errorf("expected 42, got %d (ok=%t)", id, ok)
+	}
+	for _, value := range []string{"", "abc", "-1"} {
+		if _, ok := ParseLastEventID(value); ok {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
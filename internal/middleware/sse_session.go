@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// sessionChannelSize 세션 채널 버퍼 크기
+	sessionChannelSize = 100
+	// shutdownWaitTimeout 서버 종료 시 세션 고루틴 종료를 기다리는 최대 시간
+	shutdownWaitTimeout = 10 * time.Second
+)
+
+var (
+	// ErrSessionClosed 이미 종료된 세션에 전송하려는 경우
+	ErrSessionClosed = errors.New("session is closed")
+	// ErrSessionBusy 세션 채널이 가득 차 전송하지 못한 경우
+	ErrSessionBusy = errors.New("session channel is full")
+)
+
+// NewSSESession 새로운 세션 생성 (매니저에 등록되지 않은 상태)
+// 세션 수명은 내부 context로 관리한다. Channel과 BotChannel은 닫지 않으므로 종료 후 전송해도 panic이 나지 않고,
+// 수신 측은 Done()으로 종료를 감지한다.
+func NewSSESession(userUUID, chatbotUUID string) *SSESession {
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	return &SSESession{
+		SessionID:    uuid.New().String(),
+		UserUUID:     userUUID,
+		ChatbotUUID:  chatbotUUID,
+		Channel:      make(chan string, sessionChannelSize),
+		BotChannel:   make(chan string, sessionChannelSize),
+		CreatedAt:    now,
+		ctx:          ctx,
+		cancel:       cancel,
+		lastActivity: now,
+	}
+}
+
+// Context 세션 수명 context (세션이 종료되면 취소됨, 봇 응답 생성 등 세션에 묶인 작업에 사용)
+func (s *SSESession) Context() context.Context {
+	return s.ctx
+}
+
+// Done 세션 종료 신호 채널
+func (s *SSESession) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// IsActive 세션이 아직 종료되지 않았는지
+func (s *SSESession) IsActive() bool {
+	return s.ctx.Err() == nil
+}
+
+// Send 세션 채널로 이벤트 전송 (세션이 종료됐거나 채널이 가득 차면 에러)
+// 활동 시각은 갱신하지 않는다 (사용자/봇 메시지를 보내는 쪽에서 Touch 호출).
+func (s *SSESession) Send(message string) error {
+	if !s.IsActive() {
+		return ErrSessionClosed
+	}
+
+	select {
+	case s.Channel <- message:
+		return nil
+	case <-s.ctx.Done():
+		return ErrSessionClosed
+	default:
+		return ErrSessionBusy
+	}
+}
+
+// Go 세션에 묶인 고루틴 실행 (세션 종료 시 ctx가 취소되며, Wait으로 모두 끝날 때까지 기다릴 수 있음)
+// 이미 종료된 세션이면 실행하지 않고 false를 반환한다.
+func (s *SSESession) Go(f func(ctx context.Context)) bool {
+	s.mu.Lock()
+	if !s.IsActive() {
+		s.mu.Unlock()
+		return false
+	}
+	s.workers.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.workers.Done()
+		f(s.ctx)
+	}()
+	return true
+}
+
+// Wait 세션에 묶인 고루틴이 모두 끝날 때까지 대기 (timeout 안에 끝나면 true)
+func (s *SSESession) Wait(timeout time.Duration) bool {
+	finished := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// close 세션 종료 (처음 종료한 호출만 true)
+// Go와 같은 락 안에서 취소해 종료 이후에는 새 고루틴이 추가되지 않도록 한다.
+func (s *SSESession) close() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.IsActive() {
+		return false
+	}
+	s.cancel()
+
+	if s.detachTimer != nil {
+		s.detachTimer.Stop()
+		s.detachTimer = nil
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStopSessionDuringSend(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	// 응답 생성 고루틴 흉내: 종료될 때까지 세션 채널로 계속 전송
+	var sent atomic.Int64
+	s.Go(func(ctx context.Context) {
+		for ctx.Err() == nil {
+			if s.Send("data: token\n\n") == nil {
+				sent.Add(1)
+			}
+			select {
+			case <-s.Channel:
+			default:
+			}
+		}
+	})
+
+	// 종료 후에도 외부에서 보내는 메시지가 panic 없이 거절되어야 함
+	var senders sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for j := 0; j < 100; j++ {
+				sm.SendMessage(s.SessionID, "data: user\n\n")
+			}
+		}()
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if err := sm.StopSession(s.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	senders.Wait()
+
+	if !s.Wait(time.Second) {
+		t.Fatal("session goroutine did not exit after stop")
+	}
+	if err := s.Send("data: late\n\n"); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("expected ErrSessionClosed after stop, got %v", err)
+	}
+	if s.Go(func(context.Context) {}) {
+		t.Error("Go must not start goroutines on a stopped session")
+	}
+	if err := sm.StopSession(s.SessionID); err == nil {
+		t.Error("expected error when stopping twice")
+	}
+}
+
+func TestShutdownStopsSessionsAndWaitsForWorkers(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+
+	var exited atomic.Int64
+	var sessions []*SSESession
+	for _, user := range []string{"user-1", "user-2", "user-3"} {
+		s, err := sm.CreateSession(user, "bot-1")
+		if err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+		s.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(5 * time.Millisecond)
+			exited.Add(1)
+		})
+		sessions = append(sessions, s)
+	}
+
+	sm.Shutdown()
+
+	if got := exited.Load(); got != int64(len(sessions)) {
+		t.Fatalf("expected %d workers to finish before Shutdown returned, got %d", len(sessions), got)
+	}
+	if count := sm.GetActiveSessionsCount(); count != 0 {
+		t.Errorf("expected no sessions after shutdown, got %d", count)
+	}
+	for _, s := range sessions {
+		if s.IsActive() {
+			t.Errorf("session %s still active after shutdown", s.SessionID)
+		}
+	}
+}
+
+func TestConcurrentReconnectAndStop(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	sm.AttachStream(s)
+
+	// 여러 클라이언트가 동시에 재연결/끊김을 반복하는 중에 세션 중단
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				resumed, stream, _, _ := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReplace})
+				if resumed == nil {
+					return
+				}
+				resumed.RecordEvent("data: event\n\n")
+				sm.DetachStream(resumed, stream)
+			}
+		}()
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := sm.StopSession(s.SessionID); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	wg.Wait()
+
+	if resumed, _, _, _ := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReplace}); resumed != nil {
+		t.Error("stopped session must not be resumed")
+	}
+	if _, err := sm.CreateSession("user-1", "bot-1"); err != nil {
+		t.Errorf("expected new session after stop, got %v", err)
+	}
+}
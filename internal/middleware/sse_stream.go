@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// subscriptionBufferSize 스트림별 전송 대기 이벤트 수 (넘치면 해당 스트림을 끊고 Last-Event-ID 재연결로 복구)
+const subscriptionBufferSize = 100
+
+// ErrSessionConflict 같은 사용자-채팅봇 세션에 이미 다른 스트림이 연결된 경우
+var ErrSessionConflict = fiber.NewError(fiber.StatusConflict, "Active session already exists for this user and chatbot")
+
+// TakeoverMode 이미 스트림이 연결된 세션에 다른 기기가 연결할 때의 처리 방식
+type TakeoverMode string
+
+const (
+	// TakeoverReject 기존 연결을 유지하고 새 연결은 거절 (409)
+	TakeoverReject TakeoverMode = "reject"
+	// TakeoverReplace 기존 연결을 끊고 새 연결로 교체
+	TakeoverReplace TakeoverMode = "replace"
+	// TakeoverFanout 기존 연결을 유지하면서 새 연결에도 같은 이벤트를 전송
+	TakeoverFanout TakeoverMode = "fanout"
+)
+
+// ParseTakeoverMode takeover 값 파싱 (알 수 없는 값이면 false)
+func ParseTakeoverMode(value string) (TakeoverMode, bool) {
+	switch mode := TakeoverMode(value); mode {
+	case TakeoverReject, TakeoverReplace, TakeoverFanout:
+		return mode, true
+	}
+	return "", false
+}
+
+// 스트림 종료 사유
+const (
+	StreamReplaced = "replaced" // 다른 기기가 세션을 이어받음
+	StreamLagging  = "lagging"  // 전송이 밀려 연결을 끊음 (재연결 시 재전송)
+	StreamEnded    = "ended"    // 세션 종료
+)
+
+// Subscription 세션에 연결된 스트림 하나 (SSE 또는 WebSocket 연결)
+// 세션 이벤트는 모든 구독에 같은 순서와 ID로 전달된다.
+type Subscription struct {
+	events chan SSEEvent
+	closed chan struct{}
+	reason string // closed가 닫히기 전에 설정됨
+}
+
+// Events 전송할 이벤트 채널
+func (sub *Subscription) Events() <-chan SSEEvent {
+	return sub.events
+}
+
+// Closed 스트림을 끊어야 할 때 닫히는 채널 (닫히기 전에 전달된 이벤트는 Events에 남아 있음)
+func (sub *Subscription) Closed() <-chan struct{} {
+	return sub.closed
+}
+
+// Reason 스트림 종료 사유 (Closed가 닫힌 뒤에만 유효)
+func (sub *Subscription) Reason() string {
+	return sub.reason
+}
+
+// RecordEvent 이벤트에 다음 ID를 부여해 재전송 버퍼에 보관하고 연결된 모든 스트림에 전달
+func (s *SSESession) RecordEvent(data string) SSEEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastEventID++
+	event := SSEEvent{ID: s.lastEventID, Data: data}
+
+	s.events = append(s.events, event)
+	if len(s.events) > replayBufferSize {
+		s.events = s.events[len(s.events)-replayBufferSize:]
+	}
+
+	for sub := range s.streams {
+		select {
+		case sub.events <- event:
+		default:
+			// 밀린 스트림은 끊고 클라이언트가 Last-Event-ID로 다시 받도록 함
+			s.closeStreamLocked(sub, StreamLagging)
+		}
+	}
+	return event
+}
+
+// EventsSince lastID 이후에 기록된 이벤트 조회
+// 버퍼에서 밀려난 이벤트는 복구할 수 없으므로 남아 있는 이벤트만 반환한다.
+func (s *SSESession) EventsSince(lastID uint64) []SSEEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventsSinceLocked(lastID)
+}
+
+// eventsSinceLocked lastID 이후 이벤트 (s.mu를 잡은 상태에서 호출)
+func (s *SSESession) eventsSinceLocked(lastID uint64) []SSEEvent {
+	var events []SSEEvent
+	for _, event := range s.events {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// StreamCount 현재 연결된 스트림 수
+func (s *SSESession) StreamCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams)
+}
+
+// CloseStreams 연결된 모든 스트림 종료 (세션 종료 후 남은 이벤트를 모두 전달한 뒤 호출)
+func (s *SSESession) CloseStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.streams {
+		s.closeStreamLocked(sub, StreamEnded)
+	}
+}
+
+// closeStreamLocked 스트림 종료 신호 (s.mu를 잡은 상태에서 호출)
+func (s *SSESession) closeStreamLocked(sub *Subscription, reason string) {
+	delete(s.streams, sub)
+	sub.reason = reason
+	close(sub.closed)
+}
+
+// AttachStream 새 세션에 첫 스트림 연결
+func (sm *SSEManager) AttachStream(s *SSESession) *Subscription {
+	sub, _, _ := sm.attach(s, ResumeRequest{Mode: TakeoverReplace, HasEventID: true})
+	return sub
+}
+
+// attach 세션에 스트림 연결 후 재전송할 이벤트를 함께 반환
+// 기존 스트림 확인(reject 모드 거절), 구독 등록, 재전송 목록 계산을 한 락 안에서 처리해
+// 동시에 연결해도 둘 다 reject 검사를 통과하지 않고, 이벤트가 빠지거나 중복되지 않는다.
+func (sm *SSEManager) attach(s *SSESession, req ResumeRequest) (*Subscription, []SSEEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attached := len(s.streams) > 0
+	if attached && req.Mode == TakeoverReject {
+		return nil, nil, ErrSessionConflict
+	}
+
+	since := s.lastEventID
+	if req.HasEventID {
+		since = req.LastEventID
+	} else if !attached {
+		since = s.detachedAt
+	}
+
+	if req.Mode == TakeoverReplace {
+		for sub := range s.streams {
+			s.closeStreamLocked(sub, StreamReplaced)
+		}
+	}
+	if s.detachTimer != nil {
+		s.detachTimer.Stop()
+		s.detachTimer = nil
+	}
+
+	sub := &Subscription{
+		events: make(chan SSEEvent, subscriptionBufferSize),
+		closed: make(chan struct{}),
+	}
+	if s.streams == nil {
+		s.streams = make(map[*Subscription]struct{})
+	}
+	s.streams[sub] = struct{}{}
+
+	return sub, s.eventsSinceLocked(since), nil
+}
+
+// DetachStream 스트림 연결 해제
+// 마지막 스트림이 끊기면 세션을 재연결 유예 기간 동안 유지하고, 그 안에 다시 연결되지 않으면 제거한다.
+func (sm *SSEManager) DetachStream(s *SSESession, sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, attached := s.streams[sub]; attached {
+		s.closeStreamLocked(sub, StreamEnded)
+	}
+	if len(s.streams) > 0 || s.detachTimer != nil || !s.IsActive() {
+		return
+	}
+
+	// 끊긴 동안 쌓인 이벤트는 Last-Event-ID 없이 재연결해도 재전송
+	s.detachedAt = s.lastEventID
+	s.detachTimer = time.AfterFunc(sm.resumeGrace, func() {
+		s.mu.Lock()
+		expired := len(s.streams) == 0
+		s.mu.Unlock()
+
+		if expired {
+			sm.DeleteSession(s.SessionID)
+		}
+	})
+}
+
+// ResumeRequest 기존 세션에 다시 연결하는 요청
+type ResumeRequest struct {
+	Mode        TakeoverMode // 이미 다른 스트림이 연결된 경우의 처리 방식
+	LastEventID uint64       // 클라이언트가 마지막으로 받은 이벤트 ID
+	HasEventID  bool         // LastEventID가 주어졌는지 (false면 끊긴 동안 쌓인 이벤트만 재전송)
+}
+
+// ResumeSession 이 인스턴스가 소유한 사용자-채팅봇 세션에 스트림 연결
+// 연결된 스트림이 없으면 항상 이어받고, 있으면 Mode에 따라 거절(ErrSessionConflict), 교체, 또는 함께 연결한다.
+// 대상 세션이 없으면 nil을 반환한다.
+func (sm *SSEManager) ResumeSession(userUUID, chatbotUUID string, req ResumeRequest) (*SSESession, *Subscription, []SSEEvent, error) {
+	sm.mutex.RLock()
+	var target *SSESession
+	for _, s := range sm.sessions {
+		if s.UserUUID == userUUID && s.ChatbotUUID == chatbotUUID && s.IsActive() {
+			target = s
+			break
+		}
+	}
+	sm.mutex.RUnlock()
+
+	if target == nil {
+		return nil, nil, nil, nil
+	}
+
+	sub, replay, err := sm.attach(target, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return target, sub, replay, nil
+}
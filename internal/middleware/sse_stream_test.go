@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// receive 구독에서 이벤트 하나를 꺼냄
+func receive(t *testing.T, sub *Subscription) SSEEvent {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events():
+		return event
+	default:
+		t.Fatal("expected an event on subscription")
+		return SSEEvent{}
+	}
+}
+
+func TestFanoutDeliversToAllStreams(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	phone := sm.AttachStream(s)
+
+	resumed, tablet, _, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverFanout})
+	if err != nil || resumed != s {
+		t.Fatalf("expected fan-out to join the session, got %v (err=%v)", resumed, err)
+	}
+	if count := s.StreamCount(); count != 2 {
+		t.Fatalf("expected 2 streams, got %d", count)
+	}
+
+	recorded := s.RecordEvent("data: hello\n\n")
+	for _, sub := range []*Subscription{phone, tablet} {
+		if event := receive(t, sub); event != recorded {
+			t.Errorf("expected %+v, got %+v", recorded, event)
+		}
+	}
+
+	// 한 기기가 끊겨도 다른 기기는 계속 이벤트를 받음
+	sm.DetachStream(s, phone)
+	s.RecordEvent("data: still here\n\n")
+	receive(t, tablet)
+	if _, _, _, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReject}); err != ErrSessionConflict {
+		t.Errorf("session with a remaining stream must still reject, got %v", err)
+	}
+}
+
+func TestConcurrentRejectResumeAttachesOnce(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	sm.DetachStream(s, sm.AttachStream(s))
+
+	// 끊긴 세션에 여러 기기가 동시에 reject 모드로 재연결해도 하나만 붙어야 함
+	const clients = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	attached, rejected := 0, 0
+	start := make(chan struct{})
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, _, _, err := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverReject})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				attached++
+			case ErrSessionConflict:
+				rejected++
+			default:
+				t.Errorf("ResumeSession: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if attached != 1 || rejected != clients-1 {
+		t.Fatalf("expected 1 attached and %d rejected, got %d and %d", clients-1, attached, rejected)
+	}
+	if count := s.StreamCount(); count != 1 {
+		t.Fatalf("expected 1 stream, got %d", count)
+	}
+}
+
+func TestLaggingStreamIsClosed(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	sub := sm.AttachStream(s)
+
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		s.RecordEvent(fmt.Sprintf("data: %d\n\n", i))
+	}
+
+	select {
+	case <-sub.Closed():
+		if sub.Reason() != StreamLagging {
+			t.Errorf("unexpected close reason %q", sub.Reason())
+		}
+	default:
+		t.Fatal("lagging stream was not closed")
+	}
+	if len(sub.Events()) != subscriptionBufferSize {
+		t.Errorf("events delivered before closing must remain readable, got %d", len(sub.Events()))
+	}
+}
+
+func TestCloseStreamsEndsAllSubscriptions(t *testing.T) {
+	sm := NewSSEManager(DefaultMaxSessions)
+	t.Cleanup(sm.Shutdown)
+
+	s, err := sm.CreateSession("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	first := sm.AttachStream(s)
+	_, second, _, _ := sm.ResumeSession("user-1", "bot-1", ResumeRequest{Mode: TakeoverFanout})
+
+	s.CloseStreams()
+	for _, sub := range []*Subscription{first, second} {
+		select {
+		case <-sub.Closed():
+			if sub.Reason() != StreamEnded {
+				t.Errorf("unexpected close reason %q", sub.Reason())
+			}
+		default:
+			t.Error("stream was not closed")
+		}
+	}
+}
+
+func TestParseTakeoverMode(t *testing.T) {
+	for _, value := range []string{"reject", "replace", "fanout"} {
+		if mode, ok := ParseTakeoverMode(value); !ok || string(mode) != value {
+			t.Errorf("expected %q to parse, got %q (ok=%t)", value, mode, ok)
+		}
+	}
+	if _, ok := ParseTakeoverMode("steal"); ok {
+		t.Error("expected unknown mode to be rejected")
+	}
+}
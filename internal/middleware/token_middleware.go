@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	tokenservice "sermo-be/internal/core/token"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TokenServiceMiddleware 서버 시작 시 만든 토큰 서비스를 context에 주입하는 미들웨어
+func TokenServiceMiddleware(service *tokenservice.TokenService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// context에 토큰 서비스 저장
+		c.Locals("token_service", service)
+		return c.Next()
+	}
+}
+
+// GetTokenService context에서 토큰 서비스 가져오기
+func GetTokenService(c *fiber.Ctx) *tokenservice.TokenService {
+	if service, ok := c.Locals("token_service").(*tokenservice.TokenService); ok {
+		return service
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"sermo-be/internal/core/usage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsageMiddleware 서버 시작 시 만든 사용량 서비스(설정의 사용자별 한도 포함)를 context에 주입하는 미들웨어
+func UsageMiddleware(service *usage.UsageService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// context에 사용량 서비스 저장
+		c.Locals("usage_service", service)
+		return c.Next()
+	}
+}
+
+// GetUsageService context에서 사용량 서비스 가져오기
+func GetUsageService(c *fiber.Ctx) *usage.UsageService {
+	if service, ok := c.Locals("usage_service").(*usage.UsageService); ok {
+		return service
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatReadCursor 사용자가 채팅봇과의 대화에서 마지막으로 읽은 메시지 위치
+// (LastReadAt, LastReadMessageUUID) 이후의 채팅봇 메시지를 읽지 않은 메시지로 센다.
+type ChatReadCursor struct {
+	UserUUID            string    `json:"user_uuid" gorm:"type:varchar(36);primaryKey"`
+	ChatbotUUID         string    `json:"chatbot_uuid" gorm:"type:varchar(36);primaryKey"`
+	LastReadMessageUUID uuid.UUID `json:"last_read_message_uuid" gorm:"type:uuid;not null"`
+	LastReadAt          time.Time `json:"last_read_at" gorm:"not null"` // 마지막으로 읽은 메시지의 created_at
+	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName GORM 테이블명 지정
+func (ChatReadCursor) TableName() string {
+	return "chat_read_cursors"
+}
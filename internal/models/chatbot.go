@@ -28,6 +28,11 @@ type Chatbot struct {
 	UserUUID  string          `json:"user_uuid" gorm:"type:varchar(36);not null"` // FK 없이 문자열로 저장
 	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// 봇 응답 대기 설정 (밀리초, nil이면 서버 기본값 사용)
+	ReplyDelayMs      *int `json:"reply_delay_ms,omitempty" gorm:"type:integer"`
+	TypingExtensionMs *int `json:"typing_extension_ms,omitempty" gorm:"type:integer"`
+	MaxReplyWaitMs    *int `json:"max_reply_wait_ms,omitempty" gorm:"type:integer"`
 }
 
 // NewChatbot 새로운 채팅봇 인스턴스 생성
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversationMemory 사용자-채팅봇 대화의 누적 요약 (장기 기억)
+// (SummarizedUntilAt, SummarizedUntilUUID)까지의 메시지가 Summary에 반영되어 있다.
+type ConversationMemory struct {
+	UserUUID            string    `json:"user_uuid" gorm:"type:varchar(36);primaryKey"`
+	ChatbotUUID         string    `json:"chatbot_uuid" gorm:"type:varchar(36);primaryKey"`
+	Summary             string    `json:"summary" gorm:"type:text;not null"`
+	SummarizedUntilAt   time.Time `json:"summarized_until_at" gorm:"not null"`             // 마지막으로 요약한 메시지의 created_at
+	SummarizedUntilUUID uuid.UUID `json:"summarized_until_uuid" gorm:"type:uuid;not null"` // 마지막으로 요약한 메시지 UUID
+	SummarizedCount     int       `json:"summarized_count" gorm:"not null;default:0"`      // 요약에 반영된 메시지 수
+	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName GORM 테이블명 지정
+func (ConversationMemory) TableName() string {
+	return "conversation_memories"
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 임베딩 원본 종류
+const (
+	EmbeddingSourceMessage    = "message"     // 사용자 메시지
+	EmbeddingSourceUserStatus = "user_status" // 사용자 상태 정보
+)
+
+// MemoryEmbedding 지난 대화 검색을 위한 사용자 메시지/상태 정보의 임베딩
+type MemoryEmbedding struct {
+	UUID        uuid.UUID `json:"uuid" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserUUID    string    `json:"user_uuid" gorm:"type:varchar(36);not null"`
+	ChatbotUUID string    `json:"chatbot_uuid" gorm:"type:varchar(36);not null"`
+	SourceType  string    `json:"source_type" gorm:"type:varchar(20);not null"` // message, user_status
+	SourceUUID  uuid.UUID `json:"source_uuid" gorm:"type:uuid;not null"`        // 원본 메시지/상태 정보 UUID
+	Content     string    `json:"content" gorm:"type:text;not null"`
+	Embedding   Vector    `json:"-" gorm:"type:vector(1536);not null"`
+	CreatedAt   time.Time `json:"created_at"` // 원본이 만들어진 시각
+}
+
+// TableName GORM 테이블명 지정
+func (MemoryEmbedding) TableName() string {
+	return "memory_embeddings"
+}
+
+// Vector pgvector 컬럼 값 ("[1,2,3]" 텍스트 형식으로 주고받음)
+type Vector []float32
+
+// Value DB에 저장할 값
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String(), nil
+}
+
+// Scan DB에서 읽은 값 파싱
+func (v *Vector) Scan(src interface{}) error {
+	var text string
+	switch value := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		text = value
+	case []byte:
+		text = string(value)
+	default:
+		return fmt.Errorf("unsupported vector type %T", src)
+	}
+
+	text = strings.TrimSpace(text)
+	if len(text) < 2 || text[0] != '[' || text[len(text)-1] != ']' {
+		return fmt.Errorf("invalid vector %q", text)
+	}
+
+	text = text[1 : len(text)-1]
+	if text == "" {
+		*v = Vector{}
+		return nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make(Vector, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("invalid vector element %q: %w", part, err)
+		}
+		vector[i] = float32(x)
+	}
+	*v = vector
+	return nil
+}
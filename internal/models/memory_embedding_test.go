@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestVectorRoundTrip(t *testing.T) {
+	value, err := Vector{1, -0.5, 0.25}.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if value != "[1,-0.5,0.25]" {
+		t.Fatalf("Value() = %v, want [1,-0.5,0.25]", value)
+	}
+
+	var v Vector
+	if err := v.Scan([]byte("[1, -0.5,0.25]")); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(v) != 3 || v[0] != 1 || v[1] != -0.5 || v[2] != 0.25 {
+		t.Fatalf("Scan() = %v", v)
+	}
+
+	if err := v.Scan("1,2"); err == nil {
+		t.Error("Scan() accepted vector without brackets")
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken 리프레시 토큰 모델 (토큰 원문은 저장하지 않고 해시만 저장)
+type RefreshToken struct {
+	UUID         uuid.UUID  `json:"uuid" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserUUID     string     `json:"user_uuid" gorm:"type:varchar(36);not null;index"`
+	FamilyID     string     `json:"family_id" gorm:"type:varchar(36);not null;index"` // 로그인 세션 단위로 유지되는 토큰 계열 ID
+	TokenHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	DeviceInfo   string     `json:"device_info" gorm:"type:varchar(255);default:''"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	Revoked      bool       `json:"revoked" gorm:"default:false;index"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ReplacedByID *uuid.UUID `json:"replaced_by_id" gorm:"type:uuid"` // 회전 시 새로 발급된 토큰
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// NewRefreshToken 새로운 리프레시 토큰 인스턴스 생성
+func NewRefreshToken(userUUID, familyID, tokenHash, deviceInfo string, expiresAt time.Time) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		UUID:       uuid.New(),
+		UserUUID:   userUUID,
+		FamilyID:   familyID,
+		TokenHash:  tokenHash,
+		DeviceInfo: deviceInfo,
+		ExpiresAt:  expiresAt,
+		Revoked:    false,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// TableName GORM 테이블명 지정
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsExpired 만료 여부 확인
+func (r *RefreshToken) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
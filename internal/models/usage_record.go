@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageRecord 언어 모델/이미지 생성 호출 한 번의 사용량 기록 (사용자별 한도 계산에 사용)
+type UsageRecord struct {
+	UUID             uuid.UUID `json:"uuid" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserUUID         string    `json:"user_uuid" gorm:"type:varchar(36);not null"`
+	Feature          string    `json:"feature" gorm:"type:varchar(30);not null"` // answer, validation, status, summary, alarm, bookmark, image_prompt, image, embedding
+	Model            string    `json:"model" gorm:"type:varchar(100);not null"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"not null;default:0"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"not null;default:0"`
+	TotalTokens      int       `json:"total_tokens" gorm:"not null;default:0"`
+	Images           int       `json:"images" gorm:"not null;default:0"` // 생성된 이미지 수
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
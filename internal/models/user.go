@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"sermo-be/pkg/password"
+
 	"github.com/google/uuid"
 )
 
@@ -10,19 +12,42 @@ type User struct {
 	UUID      uuid.UUID `json:"uuid" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	ID        string    `json:"id" gorm:"type:varchar(20);uniqueIndex;not null"`
 	Nickname  string    `json:"nickname" gorm:"type:varchar(100);not null"`
-	Password  string    `json:"-" gorm:"type:varchar(255);not null"`
+	Password  string    `json:"-" gorm:"type:varchar(255);not null"` // bcrypt 해시 (레거시 계정은 첫 로그인 전까지 평문)
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-func NewUser(id, nickname, password string) *User {
+// NewUser 새로운 사용자 인스턴스 생성 (비밀번호는 해시하여 저장)
+func NewUser(id, nickname, plainPassword string) (*User, error) {
 	now := time.Now()
-	return &User{
+	user := &User{
 		UUID:      uuid.New(),
 		ID:        id,
 		Nickname:  nickname,
-		Password:  password,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+
+	if err := user.SetPassword(plainPassword); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetPassword 평문 비밀번호를 해시하여 설정
+func (u *User) SetPassword(plainPassword string) error {
+	hashed, err := password.Hash(plainPassword)
+	if err != nil {
+		return err
+	}
+	u.Password = hashed
+	return nil
+}
+
+// CheckPassword 비밀번호 검증 (일치 여부와 재해시 필요 여부 반환)
+func (u *User) CheckPassword(plainPassword string) (ok bool, needsRehash bool) {
+	if !password.Verify(u.Password, plainPassword) {
+		return false, false
+	}
+	return true, password.NeedsRehash(u.Password)
 }
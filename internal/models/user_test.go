@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"sermo-be/pkg/password"
+)
+
+func TestNewUserHashesPassword(t *testing.T) {
+	user, err := NewUser("tester", "Tester", "secret123")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+
+	if user.Password == "secret123" || !password.IsHashed(user.Password) {
+		t.Fatalf("Password = %q, want bcrypt hash", user.Password)
+	}
+
+	ok, needsRehash := user.CheckPassword("secret123")
+	if !ok || needsRehash {
+		t.Errorf("CheckPassword() = (%t, %t), want (true, false)", ok, needsRehash)
+	}
+}
+
+func TestLegacyPlaintextUserLogsInOnceThenUpgrades(t *testing.T) {
+	// 해시 도입 이전에 저장된 평문 비밀번호 행
+	user := &User{ID: "legacy", Nickname: "Legacy", Password: "plain-old"}
+
+	if ok, _ := user.CheckPassword("wrong"); ok {
+		t.Fatal("CheckPassword() accepted wrong password for legacy row")
+	}
+
+	ok, needsRehash := user.CheckPassword("plain-old")
+	if !ok || !needsRehash {
+		t.Fatalf("CheckPassword() = (%t, %t), want (true, true)", ok, needsRehash)
+	}
+
+	// 로그인 핸들러와 동일하게 재해시
+	if err := user.SetPassword("plain-old"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if !password.IsHashed(user.Password) {
+		t.Fatalf("Password = %q after upgrade, want bcrypt hash", user.Password)
+	}
+
+	ok, needsRehash = user.CheckPassword("plain-old")
+	if !ok || needsRehash {
+		t.Errorf("CheckPassword() after upgrade = (%t, %t), want (true, false)", ok, needsRehash)
+	}
+
+	// 업그레이드 후에는 저장된 해시 문자열 자체로 로그인할 수 없어야 함
+	if ok, _ := user.CheckPassword(user.Password); ok {
+		t.Error("CheckPassword() accepted the stored hash as a password")
+	}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sermo-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NewGormRepositories db를 사용하는 저장소 묶음 생성
+func NewGormRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Messages:  &gormChatMessages{db: db},
+		Memories:  &gormConversationMemories{db: db},
+		Chatbots:  &gormChatbots{db: db},
+		Users:     &gormUsers{db: db},
+		Statuses:  &gormUserStatuses{db: db},
+		Alarms:    &gormAlarms{db: db},
+		FCMTokens: &gormFCMTokens{db: db},
+	}
+}
+
+// notFound gorm.ErrRecordNotFound를 ErrNotFound로 변환
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type gormChatMessages struct {
+	db *gorm.DB
+}
+
+func (r *gormChatMessages) Create(message *models.ChatMessage) error {
+	if err := r.db.Create(message).Error; err != nil {
+		return fmt.Errorf("failed to save chat message: %w", err)
+	}
+	return nil
+}
+
+func (r *gormChatMessages) Recent(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	query := r.after(userUUID, chatbotUUID, after).Order("created_at DESC, uuid DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch chat history: %w", err)
+	}
+
+	reverseMessages(messages)
+	return messages, nil
+}
+
+func (r *gormChatMessages) Oldest(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	query := r.after(userUUID, chatbotUUID, after).Order("created_at ASC, uuid ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch chat history: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *gormChatMessages) Count(userUUID, chatbotUUID string, after *Cursor) (int64, error) {
+	var count int64
+	if err := r.after(userUUID, chatbotUUID, after).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+	return count, nil
+}
+
+// after 커서 이후의 사용자-채팅봇 메시지 조회 조건
+func (r *gormChatMessages) after(userUUID, chatbotUUID string, after *Cursor) *gorm.DB {
+	query := r.db.Model(&models.ChatMessage{}).Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID)
+	if after != nil {
+		query = query.Where("(created_at, uuid) > (?, ?)", after.At, after.UUID)
+	}
+	return query
+}
+
+type gormConversationMemories struct {
+	db *gorm.DB
+}
+
+func (r *gormConversationMemories) Find(userUUID, chatbotUUID string) (*models.ConversationMemory, error) {
+	var memory models.ConversationMemory
+	err := r.db.Where("user_uuid = ? AND chatbot_uuid = ?", userUUID, chatbotUUID).First(&memory).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &memory, nil
+}
+
+// Advance 요약 위치는 앞으로만 이동하므로 다른 인스턴스가 먼저 저장한 최신 요약을 덮어쓰지 않음
+func (r *gormConversationMemories) Advance(memory *models.ConversationMemory) error {
+	err := r.db.Exec(`
+INSERT INTO conversation_memories (user_uuid, chatbot_uuid, summary, summarized_until_at, summarized_until_uuid, summarized_count, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_uuid, chatbot_uuid) DO UPDATE SET
+	summary = EXCLUDED.summary,
+	summarized_until_at = EXCLUDED.summarized_until_at,
+	summarized_until_uuid = EXCLUDED.summarized_until_uuid,
+	summarized_count = conversation_memories.summarized_count + EXCLUDED.summarized_count,
+	updated_at = EXCLUDED.updated_at
+WHERE (EXCLUDED.summarized_until_at, EXCLUDED.summarized_until_uuid)
+	> (conversation_memories.summarized_until_at, conversation_memories.summarized_until_uuid)`,
+		memory.UserUUID, memory.ChatbotUUID, memory.Summary, memory.SummarizedUntilAt, memory.SummarizedUntilUUID,
+		memory.SummarizedCount, time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to save conversation memory: %w", err)
+	}
+	return nil
+}
+
+type gormChatbots struct {
+	db *gorm.DB
+}
+
+func (r *gormChatbots) Create(chatbot *models.Chatbot) error {
+	if err := r.db.Create(chatbot).Error; err != nil {
+		return fmt.Errorf("failed to save chatbot: %w", err)
+	}
+	return nil
+}
+
+func (r *gormChatbots) FindByUUID(chatbotUUID string) (*models.Chatbot, error) {
+	var chatbot models.Chatbot
+	if err := r.db.Where("uuid = ?", chatbotUUID).First(&chatbot).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &chatbot, nil
+}
+
+func (r *gormChatbots) UpdateSummary(chatbotUUID, summary string) error {
+	err := r.db.Model(&models.Chatbot{}).Where("uuid = ?", chatbotUUID).Update("summary", summary).Error
+	if err != nil {
+		return fmt.Errorf("failed to save chatbot summary: %w", err)
+	}
+	return nil
+}
+
+type gormUsers struct {
+	db *gorm.DB
+}
+
+func (r *gormUsers) Create(user *models.User) error {
+	if err := r.db.Create(user).Error; err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+	return nil
+}
+
+func (r *gormUsers) FindByUUID(userUUID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+type gormUserStatuses struct {
+	db *gorm.DB
+}
+
+func (r *gormUserStatuses) Create(status *models.UserStatus) error {
+	if err := r.db.Create(status).Error; err != nil {
+		return fmt.Errorf("failed to save user status: %w", err)
+	}
+	return nil
+}
+
+func (r *gormUserStatuses) FindActive(statusUUID string, now time.Time) (*models.UserStatus, error) {
+	var status models.UserStatus
+	err := r.db.Where("uuid = ? AND is_active = ? AND valid_until > ?", statusUUID, true, now).First(&status).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &status, nil
+}
+
+func (r *gormUserStatuses) ListValid(userUUID, chatbotUUID string, now time.Time) ([]models.UserStatus, error) {
+	var statuses []models.UserStatus
+	err := r.db.Where("user_uuid = ? AND chatbot_uuid = ? AND valid_until > ?", userUUID, chatbotUUID, now).Find(&statuses).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+type gormAlarms struct {
+	db *gorm.DB
+}
+
+func (r *gormAlarms) Create(alarm *models.AlarmSchedule) error {
+	if err := r.db.Create(alarm).Error; err != nil {
+		return fmt.Errorf("failed to save alarm: %w", err)
+	}
+	return nil
+}
+
+func (r *gormAlarms) ListDue(now time.Time) ([]models.AlarmSchedule, error) {
+	var alarms []models.AlarmSchedule
+	if err := r.db.Where("send_time <= ? AND sended = ?", now, false).Find(&alarms).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch due alarms: %w", err)
+	}
+	return alarms, nil
+}
+
+func (r *gormAlarms) MarkSent(alarm *models.AlarmSchedule) error {
+	if err := r.db.Model(alarm).Update("sended", true).Error; err != nil {
+		return fmt.Errorf("failed to mark alarm as sent: %w", err)
+	}
+	return nil
+}
+
+type gormFCMTokens struct {
+	db *gorm.DB
+}
+
+func (r *gormFCMTokens) Create(token *models.FCMToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("failed to save FCM token: %w", err)
+	}
+	return nil
+}
+
+func (r *gormFCMTokens) ListByUser(userUUID string) ([]models.FCMToken, error) {
+	var tokens []models.FCMToken
+	if err := r.db.Where("user_uuid = ?", userUUID).Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch FCM tokens: %w", err)
+	}
+	return tokens, nil
+}
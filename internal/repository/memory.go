@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"sermo-be/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// NewMemoryRepositories 프로세스 메모리에 저장하는 저장소 묶음 생성 (DB 없이 테스트할 때 사용)
+// DB 기본값(UUID, 생성 시각, 자동 증가 ID)은 저장할 때 채운다.
+func NewMemoryRepositories() *Repositories {
+	return &Repositories{
+		Messages:  &memoryChatMessages{},
+		Memories:  &memoryConversationMemories{items: make(map[string]models.ConversationMemory)},
+		Chatbots:  &memoryChatbots{items: make(map[string]models.Chatbot)},
+		Users:     &memoryUsers{items: make(map[string]models.User)},
+		Statuses:  &memoryUserStatuses{},
+		Alarms:    &memoryAlarms{},
+		FCMTokens: &memoryFCMTokens{},
+	}
+}
+
+// stamp 비어 있는 UUID와 시각 채우기
+func stamp(id *uuid.UUID, times ...*time.Time) {
+	if *id == uuid.Nil {
+		*id = uuid.New()
+	}
+	now := time.Now()
+	for _, t := range times {
+		if t.IsZero() {
+			*t = now
+		}
+	}
+}
+
+type memoryChatMessages struct {
+	mu       sync.RWMutex
+	messages []models.ChatMessage
+}
+
+func (r *memoryChatMessages) Create(message *models.ChatMessage) error {
+	stamp(&message.UUID, &message.CreatedAt)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, *message)
+	return nil
+}
+
+func (r *memoryChatMessages) Recent(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error) {
+	messages := r.after(userUUID, chatbotUUID, after)
+	if limit > 0 && len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	return messages, nil
+}
+
+func (r *memoryChatMessages) Oldest(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error) {
+	messages := r.after(userUUID, chatbotUUID, after)
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+func (r *memoryChatMessages) Count(userUUID, chatbotUUID string, after *Cursor) (int64, error) {
+	return int64(len(r.after(userUUID, chatbotUUID, after))), nil
+}
+
+// after 커서 이후의 사용자-채팅봇 메시지 (오래된 순 복사본)
+func (r *memoryChatMessages) after(userUUID, chatbotUUID string, after *Cursor) []models.ChatMessage {
+	r.mu.RLock()
+	var messages []models.ChatMessage
+	for _, message := range r.messages {
+		if message.UserUUID == userUUID && message.ChatbotUUID == chatbotUUID && after.after(message.CreatedAt, message.UUID) {
+			messages = append(messages, message)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return bytes.Compare(messages[i].UUID[:], messages[j].UUID[:]) < 0
+	})
+	return messages
+}
+
+type memoryConversationMemories struct {
+	mu    sync.RWMutex
+	items map[string]models.ConversationMemory // user_uuid:chatbot_uuid → 요약
+}
+
+func (r *memoryConversationMemories) Find(userUUID, chatbotUUID string) (*models.ConversationMemory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	memory, ok := r.items[userUUID+":"+chatbotUUID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &memory, nil
+}
+
+func (r *memoryConversationMemories) Advance(memory *models.ConversationMemory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memory.UserUUID + ":" + memory.ChatbotUUID
+	saved := *memory
+	if existing, ok := r.items[key]; ok {
+		if !MemoryCursor(&existing).after(memory.SummarizedUntilAt, memory.SummarizedUntilUUID) {
+			return nil
+		}
+		saved.SummarizedCount += existing.SummarizedCount
+	}
+	saved.UpdatedAt = time.Now()
+	r.items[key] = saved
+	return nil
+}
+
+type memoryChatbots struct {
+	mu    sync.RWMutex
+	items map[string]models.Chatbot
+}
+
+func (r *memoryChatbots) Create(chatbot *models.Chatbot) error {
+	stamp(&chatbot.UUID, &chatbot.CreatedAt, &chatbot.UpdatedAt)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[chatbot.UUID.String()] = *chatbot
+	return nil
+}
+
+func (r *memoryChatbots) FindByUUID(chatbotUUID string) (*models.Chatbot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chatbot, ok := r.items[chatbotUUID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &chatbot, nil
+}
+
+func (r *memoryChatbots) UpdateSummary(chatbotUUID, summary string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if chatbot, ok := r.items[chatbotUUID]; ok {
+		chatbot.Summary = &summary
+		chatbot.UpdatedAt = time.Now()
+		r.items[chatbotUUID] = chatbot
+	}
+	return nil
+}
+
+type memoryUsers struct {
+	mu    sync.RWMutex
+	items map[string]models.User
+}
+
+func (r *memoryUsers) Create(user *models.User) error {
+	stamp(&user.UUID, &user.CreatedAt, &user.UpdatedAt)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[user.UUID.String()] = *user
+	return nil
+}
+
+func (r *memoryUsers) FindByUUID(userUUID string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.items[userUUID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+type memoryUserStatuses struct {
+	mu       sync.RWMutex
+	statuses []models.UserStatus
+}
+
+func (r *memoryUserStatuses) Create(status *models.UserStatus) error {
+	stamp(&status.UUID, &status.CreatedAt, &status.UpdatedAt)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses = append(r.statuses, *status)
+	return nil
+}
+
+func (r *memoryUserStatuses) FindActive(statusUUID string, now time.Time) (*models.UserStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, status := range r.statuses {
+		if status.UUID.String() == statusUUID && status.IsActive && status.ValidUntil.After(now) {
+			return &status, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserStatuses) ListValid(userUUID, chatbotUUID string, now time.Time) ([]models.UserStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var statuses []models.UserStatus
+	for _, status := range r.statuses {
+		if status.UserUUID == userUUID && status.ChatbotUUID == chatbotUUID && status.ValidUntil.After(now) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+type memoryAlarms struct {
+	mu     sync.RWMutex
+	alarms []models.AlarmSchedule
+}
+
+func (r *memoryAlarms) Create(alarm *models.AlarmSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alarm.ID = uint(len(r.alarms) + 1)
+	now := time.Now()
+	alarm.CreatedAt, alarm.UpdatedAt = now, now
+	r.alarms = append(r.alarms, *alarm)
+	return nil
+}
+
+func (r *memoryAlarms) ListDue(now time.Time) ([]models.AlarmSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alarms []models.AlarmSchedule
+	for _, alarm := range r.alarms {
+		if !alarm.SendTime.After(now) && !alarm.Sended {
+			alarms = append(alarms, alarm)
+		}
+	}
+	return alarms, nil
+}
+
+func (r *memoryAlarms) MarkSent(alarm *models.AlarmSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.alarms {
+		if r.alarms[i].ID == alarm.ID {
+			r.alarms[i].Sended = true
+			r.alarms[i].UpdatedAt = time.Now()
+		}
+	}
+	alarm.Sended = true
+	return nil
+}
+
+type memoryFCMTokens struct {
+	mu     sync.RWMutex
+	tokens []models.FCMToken
+}
+
+func (r *memoryFCMTokens) Create(token *models.FCMToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = uint(len(r.tokens) + 1)
+	now := time.Now()
+	token.CreatedAt, token.UpdatedAt = now, now
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *memoryFCMTokens) ListByUser(userUUID string) ([]models.FCMToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tokens []models.FCMToken
+	for _, token := range r.tokens {
+		if token.UserUUID != nil && token.UserUUID.String() == userUUID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
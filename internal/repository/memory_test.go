@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+)
+
+func TestMemoryChatMessagesCursor(t *testing.T) {
+	repos := NewMemoryRepositories()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var created []*models.ChatMessage
+	for i, content := range []string{"a", "b", "c", "d"} {
+		message := models.NewChatMessage("s", "user-1", "bot-1", models.MessageTypeUser, content)
+		message.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := repos.Messages.Create(message); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		created = append(created, message)
+	}
+	// 다른 대화의 메시지는 조회되지 않아야 함
+	repos.Messages.Create(models.NewChatMessage("s", "user-1", "bot-2", models.MessageTypeUser, "other"))
+
+	recent, _ := repos.Messages.Recent("user-1", "bot-1", nil, 2)
+	if contents(recent) != "cd" {
+		t.Errorf("recent = %q, want cd", contents(recent))
+	}
+
+	after := &Cursor{At: created[1].CreatedAt, UUID: created[1].UUID}
+	oldest, _ := repos.Messages.Oldest("user-1", "bot-1", after, 1)
+	if contents(oldest) != "c" {
+		t.Errorf("oldest after b = %q, want c", contents(oldest))
+	}
+	if count, _ := repos.Messages.Count("user-1", "bot-1", after); count != 2 {
+		t.Errorf("count after b = %d, want 2", count)
+	}
+}
+
+func TestMemoryConversationMemoriesAdvanceOnlyForward(t *testing.T) {
+	repos := NewMemoryRepositories()
+	now := time.Now()
+
+	if _, err := repos.Memories.Find("user-1", "bot-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Find before save: %v, want ErrNotFound", err)
+	}
+
+	repos.Memories.Advance(&models.ConversationMemory{UserUUID: "user-1", ChatbotUUID: "bot-1", Summary: "first", SummarizedUntilAt: now, SummarizedCount: 10})
+	// 더 오래된 위치의 요약은 무시
+	repos.Memories.Advance(&models.ConversationMemory{UserUUID: "user-1", ChatbotUUID: "bot-1", Summary: "stale", SummarizedUntilAt: now.Add(-time.Minute), SummarizedCount: 5})
+	repos.Memories.Advance(&models.ConversationMemory{UserUUID: "user-1", ChatbotUUID: "bot-1", Summary: "second", SummarizedUntilAt: now.Add(time.Minute), SummarizedCount: 5})
+
+	memory, err := repos.Memories.Find("user-1", "bot-1")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if memory.Summary != "second" || memory.SummarizedCount != 15 {
+		t.Errorf("memory = %q (%d), want second (15)", memory.Summary, memory.SummarizedCount)
+	}
+}
+
+func TestMemoryUserStatusesFindActive(t *testing.T) {
+	repos := NewMemoryRepositories()
+	now := time.Now()
+
+	valid := models.NewUserStatus("user-1", "bot-1", "exam", now.Add(time.Hour), "")
+	expired := models.NewUserStatus("user-1", "bot-1", "meeting", now.Add(-time.Hour), "")
+	repos.Statuses.Create(valid)
+	repos.Statuses.Create(expired)
+
+	if _, err := repos.Statuses.FindActive(valid.UUID.String(), now); err != nil {
+		t.Errorf("FindActive(valid): %v", err)
+	}
+	if _, err := repos.Statuses.FindActive(expired.UUID.String(), now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindActive(expired) = %v, want ErrNotFound", err)
+	}
+	if statuses, _ := repos.Statuses.ListValid("user-1", "bot-1", now); len(statuses) != 1 || statuses[0].Event != "exam" {
+		t.Errorf("ListValid = %+v", statuses)
+	}
+}
+
+func contents(messages []models.ChatMessage) string {
+	s := ""
+	for _, message := range messages {
+		s += message.Content
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+// Package repository 채팅 파이프라인이 사용하는 데이터 저장소 인터페이스와 구현
+// GORM(Postgres) 구현은 서버에서, 메모리 구현은 DB 없이 테스트할 때 사용한다.
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"sermo-be/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound 조회한 데이터가 없음
+var ErrNotFound = errors.New("record not found")
+
+// Cursor 메시지 위치 (created_at, uuid 순서)
+type Cursor struct {
+	At   time.Time
+	UUID uuid.UUID
+}
+
+// MemoryCursor 대화 요약이 반영된 마지막 메시지 위치 (요약이 없으면 nil)
+func MemoryCursor(memory *models.ConversationMemory) *Cursor {
+	if memory == nil {
+		return nil
+	}
+	return &Cursor{At: memory.SummarizedUntilAt, UUID: memory.SummarizedUntilUUID}
+}
+
+// after 메시지가 커서보다 뒤인지 여부 (커서가 nil이면 항상 true)
+func (c *Cursor) after(at time.Time, id uuid.UUID) bool {
+	if c == nil {
+		return true
+	}
+	if !at.Equal(c.At) {
+		return at.After(c.At)
+	}
+	return bytes.Compare(id[:], c.UUID[:]) > 0
+}
+
+// ChatMessageRepository 채팅 메시지 저장소
+type ChatMessageRepository interface {
+	// Create 메시지 저장 (CreatedAt이 비어 있으면 저장 시각)
+	Create(message *models.ChatMessage) error
+	// Recent after 이후 메시지 중 최근 limit개를 오래된 순으로 조회 (after가 nil이면 처음부터, limit이 0이면 전체)
+	Recent(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error)
+	// Oldest after 이후 메시지 중 오래된 limit개를 오래된 순으로 조회
+	Oldest(userUUID, chatbotUUID string, after *Cursor, limit int) ([]models.ChatMessage, error)
+	// Count after 이후 메시지 수
+	Count(userUUID, chatbotUUID string, after *Cursor) (int64, error)
+}
+
+// ConversationMemoryRepository 사용자-채팅봇 대화의 누적 요약 저장소
+type ConversationMemoryRepository interface {
+	// Find 대화 요약 조회 (없으면 ErrNotFound)
+	Find(userUUID, chatbotUUID string) (*models.ConversationMemory, error)
+	// Advance 요약 저장 (요약 위치가 기존보다 뒤일 때만 반영, SummarizedCount는 이번에 요약한 메시지 수로 누적)
+	Advance(memory *models.ConversationMemory) error
+}
+
+// ChatbotRepository 채팅봇 저장소
+type ChatbotRepository interface {
+	Create(chatbot *models.Chatbot) error
+	// FindByUUID 채팅봇 조회 (없으면 ErrNotFound)
+	FindByUUID(chatbotUUID string) (*models.Chatbot, error)
+	// UpdateSummary AI가 만든 캐릭터 요약 저장
+	UpdateSummary(chatbotUUID, summary string) error
+}
+
+// UserRepository 사용자 저장소
+type UserRepository interface {
+	Create(user *models.User) error
+	// FindByUUID 사용자 조회 (없으면 ErrNotFound)
+	FindByUUID(userUUID string) (*models.User, error)
+}
+
+// UserStatusRepository 사용자 상태 정보 저장소
+type UserStatusRepository interface {
+	Create(status *models.UserStatus) error
+	// FindActive now에 유효한 활성 상태 정보 조회 (없거나 만료되었으면 ErrNotFound)
+	FindActive(statusUUID string, now time.Time) (*models.UserStatus, error)
+	// ListValid 사용자-채팅봇의 상태 정보 중 now 이후까지 유효한 것
+	ListValid(userUUID, chatbotUUID string, now time.Time) ([]models.UserStatus, error)
+}
+
+// AlarmRepository 채팅 종료 후 보낼 알람 저장소
+type AlarmRepository interface {
+	Create(alarm *models.AlarmSchedule) error
+	// ListDue 전송 시간이 되었고 아직 보내지 않은 알람
+	ListDue(now time.Time) ([]models.AlarmSchedule, error)
+	// MarkSent 알람을 전송 완료로 표시
+	MarkSent(alarm *models.AlarmSchedule) error
+}
+
+// FCMTokenRepository 사용자 기기의 FCM 토큰 저장소
+type FCMTokenRepository interface {
+	Create(token *models.FCMToken) error
+	// ListByUser 사용자의 FCM 토큰 목록
+	ListByUser(userUUID string) ([]models.FCMToken, error)
+}
+
+// Repositories 채팅 파이프라인이 사용하는 저장소 묶음
+type Repositories struct {
+	Messages  ChatMessageRepository
+	Memories  ConversationMemoryRepository
+	Chatbots  ChatbotRepository
+	Users     UserRepository
+	Statuses  UserStatusRepository
+	Alarms    AlarmRepository
+	FCMTokens FCMTokenRepository
+}
+
+// reverseMessages 메시지 순서 뒤집기 (최신순 조회 결과를 오래된 순으로)
+func reverseMessages(messages []models.ChatMessage) {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+}
@@ -15,4 +15,13 @@ func SetupAuthRoutes(app *fiber.App) {
 
 	// 로그인
 	authGroup.Post("/login", auth.Login)
+
+	// 토큰 갱신
+	authGroup.Post("/refresh", auth.Refresh)
+
+	// 로그아웃
+	authGroup.Post("/logout", auth.Logout)
+
+	// 토큰 검증용 공개키 (JWKS)
+	app.Get("/.well-known/jwks.json", auth.JWKS)
 }
@@ -9,16 +9,16 @@ import (
 
 // SetupBookmarkRoutes 북마크 라우터 설정
 func SetupBookmarkRoutes(app *fiber.App) {
-	// 북마크 라우터 그룹 (인증 필요)
+	// 북마크 라우터 그룹 (인증 필요, 뜻을 생성하는 요청은 사용 한도 확인)
 	bookmarkGroup := app.Group("/bookmark", middleware.AuthMiddleware())
 
 	// 문장 북마크 라우트
-	bookmarkGroup.Post("/sentence", bookmark.CreateSentenceBookmark)
+	bookmarkGroup.Post("/sentence", middleware.QuotaMiddleware(), bookmark.CreateSentenceBookmark)
 	bookmarkGroup.Get("/sentence", bookmark.FindByUserUUIDSentenceBookmark)
 	bookmarkGroup.Get("/sentence/date", bookmark.FindByDateSentenceBookmark)
 
 	// 단어 북마크 라우트
-	bookmarkGroup.Post("/word", bookmark.CreateWordBookmark)
+	bookmarkGroup.Post("/word", middleware.QuotaMiddleware(), bookmark.CreateWordBookmark)
 	bookmarkGroup.Get("/word", bookmark.FindByUserUUIDWordBookmark)
 	bookmarkGroup.Get("/word/date", bookmark.FindByDateWordBookmark)
 }
@@ -9,14 +9,17 @@ import (
 
 // SetupChatRoutes 채팅 관련 라우트 설정
 func SetupChatRoutes(app *fiber.App) {
-	// 채팅 라우터 그룹 (인증 필요)
+	// 채팅 라우터 그룹 (인증 필요, 봇 응답을 만드는 요청은 사용 한도 확인)
 	chatGroup := app.Group("/chat", middleware.AuthMiddleware())
 
 	// 채팅 시작 (SSE 연결)
-	chatGroup.Get("/start", chat.StartChat)
+	chatGroup.Get("/start", middleware.QuotaMiddleware(), chat.StartChat)
+
+	// 양방향 채팅 (WebSocket 연결, SSE + POST 엔드포인트 대체)
+	chatGroup.Get("/ws", middleware.QuotaMiddleware(), chat.ChatWebSocketUpgrade, chat.ChatWebSocket)
 
 	// 메시지 전송
-	chatGroup.Post("/send", chat.SendMessage)
+	chatGroup.Post("/send", middleware.QuotaMiddleware(), chat.SendMessage)
 
 	// 채팅 세션 중단
 	chatGroup.Post("/stop", chat.StopChat)
@@ -24,6 +27,18 @@ func SetupChatRoutes(app *fiber.App) {
 	// 채팅 히스토리 조회
 	chatGroup.Post("/history", chat.GetChatHistory)
 
+	// 채팅 메시지 검색
+	chatGroup.Post("/search", chat.SearchChatHistory)
+
+	// 대화 목록 조회 (마지막 메시지, 읽지 않은 메시지 수)
+	chatGroup.Get("/conversations", chat.GetConversations)
+
+	// 읽음 처리
+	chatGroup.Post("/read", chat.MarkRead)
+
 	// 키보드 입력 이벤트
 	chatGroup.Post("/onkeyboard", chat.OnKeyboard)
+
+	// 응답 대기 없이 즉시 봇 응답 요청
+	chatGroup.Post("/flush", middleware.QuotaMiddleware(), chat.Flush)
 }
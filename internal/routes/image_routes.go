@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"sermo-be/internal/core/usage"
 	"sermo-be/internal/handlers/image"
 	"sermo-be/internal/middleware"
 
@@ -9,14 +10,14 @@ import (
 
 // SetupImageRoutes 이미지 관련 라우트 설정
 func SetupImageRoutes(app *fiber.App) {
-	// 이미지 라우터 그룹 (인증 필요 + Gemini 미들웨어)
-	imageGroup := app.Group("/image", middleware.AuthMiddleware(), middleware.GeminiMiddleware())
+	// 이미지 라우터 그룹 (인증 필요)
+	imageGroup := app.Group("/image", middleware.AuthMiddleware())
 
 	// 이미지 업로드
 	imageGroup.Post("/upload", image.UploadImage)
 
-	// AI 이미지 생성
-	imageGroup.Post("/generate", image.GenerateImage)
+	// AI 이미지 생성 (토큰과 이미지 사용 한도 확인)
+	imageGroup.Post("/generate", middleware.QuotaMiddleware(usage.ResourceTokens, usage.ResourceImages), image.GenerateImage)
 
 	// 이미지 조회
 	imageGroup.Get("/:image_id", image.GetImage)
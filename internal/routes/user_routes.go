@@ -14,4 +14,7 @@ func SetupUserRoutes(app *fiber.App) {
 
 	// 프로필 조회
 	userGroup.Get("/profile", user.GetProfile)
+
+	// AI 사용량 조회
+	userGroup.Get("/usage", user.GetUsage)
 }
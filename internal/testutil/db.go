@@ -0,0 +1,79 @@
+// Package testutil 외부 서비스 없이 서버 전체를 띄워 보는 엔드투엔드 테스트 도구
+// SQLite 인메모리 DB, 정해 둔 응답을 돌려주는 가짜 언어 모델, 보낸 알림을 기록하는 가짜 푸시,
+// S3 호환 가짜 오브젝트 저장소로 실제 라우트와 미들웨어를 그대로 실행한다.
+package testutil
+
+import (
+	"testing"
+
+	"sermo-be/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqliteUUIDDefault Postgres gen_random_uuid()를 대신하는 SQLite UUID v4 기본값
+const sqliteUUIDDefault = "(lower(hex(randomblob(4)))||'-'||lower(hex(randomblob(2)))||'-4'||substr(lower(hex(randomblob(2))),2)||'-'||" +
+	"substr('89ab',1+(abs(random())%4),1)||substr(lower(hex(randomblob(2))),2)||'-'||lower(hex(randomblob(6))))"
+
+// SchemaModels 테스트 DB에 만드는 테이블 (pgvector가 필요한 memory_embeddings 제외, 기억 검색은 메모리 저장소 사용)
+var SchemaModels = []interface{}{
+	&models.User{},
+	&models.RefreshToken{},
+	&models.Image{},
+	&models.Chatbot{},
+	&models.ChatMessage{},
+	&models.ChatReadCursor{},
+	&models.ConversationMemory{},
+	&models.UserStatus{},
+	&models.AlarmSchedule{},
+	&models.FCMToken{},
+	&models.SentenceBookmark{},
+	&models.WordBookmark{},
+	&models.UsageRecord{},
+}
+
+// NewDB 모든 모델 테이블을 만든 SQLite 인메모리 DB 생성 (테스트가 끝나면 닫힘)
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+
+	// 인메모리 DB는 연결마다 따로 생기므로 연결 하나만 사용
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sqlite connection: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, model := range SchemaModels {
+		if err := useSQLiteDefaults(db, model); err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		if err := db.AutoMigrate(model); err != nil {
+			t.Fatalf("migrate %T: %v", model, err)
+		}
+	}
+	return db
+}
+
+// useSQLiteDefaults Postgres 전용 기본값을 SQLite 식으로 교체 (이 DB의 스키마 캐시에만 적용)
+func useSQLiteDefaults(db *gorm.DB, model interface{}) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.DefaultValue == "gen_random_uuid()" {
+			field.DefaultValue = sqliteUUIDDefault
+		}
+	}
+	return nil
+}
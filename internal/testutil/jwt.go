@@ -0,0 +1,31 @@
+package testutil
+
+import (
+	"sync"
+	"testing"
+
+	"sermo-be/pkg/jwt"
+)
+
+var (
+	jwtOnce sync.Once
+	jwtErr  error
+)
+
+// ConfigureJWT 테스트 프로세스에서 한 번 임의의 HS256 키를 만들어 pkg/jwt에 적용
+// 같은 프로세스의 테스트 서버들이 서로의 토큰을 검증할 수 있도록 키는 다시 만들지 않는다.
+func ConfigureJWT(t testing.TB) {
+	t.Helper()
+
+	jwtOnce.Do(func() {
+		key, err := jwt.NewRandomKey("test")
+		if err != nil {
+			jwtErr = err
+			return
+		}
+		jwtErr = jwt.Configure(&jwt.Config{ActiveKeyID: key.ID, Keys: []jwt.KeyConfig{key}})
+	})
+	if jwtErr != nil {
+		t.Fatalf("configure JWT: %v", jwtErr)
+	}
+}
@@ -0,0 +1,66 @@
+package testutil
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/llmtest"
+)
+
+// Script 요청 종류별로 정해 둔 가짜 언어 모델 응답
+// 응답 형식(JSON 스키마 이름)과 시스템 프롬프트로 요청 종류를 구분하고, 나머지 요청(봇 응답, 검증, 요약)에는 Reply를 돌려준다.
+type Script struct {
+	Reply         string // 봇 응답 (검증 단계도 같은 응답을 돌려줘 그대로 전송됨)
+	Status        string // 상태 정보 추출 결과 JSON (status_extraction)
+	Keywords      string // 알람 키워드 JSON (alarm_keywords)
+	AlarmMessage  string // 1차 알람 메시지 JSON (alarm_message)
+	EnhancedAlarm string // 2차로 가공한 최종 알람 메시지
+}
+
+// DefaultScript 내일 시험이 있다는 상태 정보를 저장하고 그에 맞는 알람을 만드는 응답
+func DefaultScript() Script {
+	examAt := time.Now().Add(24 * time.Hour)
+	status, _ := json.Marshal(map[string]interface{}{
+		"needs_save":  true,
+		"event":       "시험",
+		"valid_until": examAt.Format(time.RFC3339),
+		"context":     "내일 수학 시험",
+	})
+	alarm, _ := json.Marshal(map[string]string{
+		"message":   "Good luck on your exam!",
+		"send_time": examAt.Add(-time.Hour).Format("2006-01-02 15:04:05"),
+	})
+
+	return Script{
+		Reply:         "Good luck! You studied hard for it.",
+		Status:        string(status),
+		Keywords:      `{"keywords":["exam","math"]}`,
+		AlarmMessage:  string(alarm),
+		EnhancedAlarm: "Hey, your math exam is coming up. You've got this!",
+	}
+}
+
+// LLM 스크립트대로 응답하는 가짜 언어 모델 생성
+func (s Script) LLM() *llmtest.Fake {
+	return &llmtest.Fake{Respond: s.respond}
+}
+
+// respond 요청 종류에 맞는 응답 선택
+func (s Script) respond(req llm.ChatRequest) (string, error) {
+	if req.ResponseFormat != nil {
+		switch req.ResponseFormat.Name {
+		case "status_extraction":
+			return s.Status, nil
+		case "alarm_keywords":
+			return s.Keywords, nil
+		case "alarm_message":
+			return s.AlarmMessage, nil
+		}
+	}
+	if len(req.Messages) > 0 && strings.Contains(req.Messages[0].Content, "transforming alarm messages") {
+		return s.EnhancedAlarm, nil
+	}
+	return s.Reply, nil
+}
@@ -0,0 +1,115 @@
+package testutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"sermo-be/pkg/r2"
+)
+
+// ObjectStoreBucket 가짜 오브젝트 저장소의 버킷 이름
+const ObjectStoreBucket = "sermo-test"
+
+// ObjectStore R2 대신 메모리에 파일을 보관하는 S3 호환 서버 (PUT, GET, HEAD, DELETE만 지원)
+// r2 클라이언트는 경로 방식(/버킷/키)으로 요청하므로 버킷 뒤의 경로를 키로 사용한다.
+type ObjectStore struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewObjectStore 가짜 오브젝트 저장소 시작 (테스트가 끝나면 종료)
+func NewObjectStore(t testing.TB) *ObjectStore {
+	t.Helper()
+
+	s := &ObjectStore{objects: make(map[string][]byte)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// NewClient 가짜 저장소에 연결된 r2 클라이언트 생성
+func (s *ObjectStore) NewClient(t testing.TB) *r2.Client {
+	t.Helper()
+
+	client, err := r2.NewClient(&r2.Config{
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Endpoint:        s.server.URL,
+		Bucket:          ObjectStoreBucket,
+	})
+	if err != nil {
+		t.Fatalf("r2 client: %v", err)
+	}
+	return client
+}
+
+// Object 저장된 파일 내용 조회
+func (s *ObjectStore) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	return data, ok
+}
+
+// Keys 저장된 파일 키 목록
+func (s *ObjectStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// serveHTTP S3 오브젝트 요청 처리
+func (s *ObjectStore) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+ObjectStoreBucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "unknown bucket", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.objects[key] = data
+		s.mu.Unlock()
+		w.Header().Set("ETag", `"`+strconv.Itoa(len(data))+`"`)
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodGet, http.MethodHead:
+		data, ok := s.Object(key)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
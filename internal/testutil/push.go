@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/internal/core/chat"
+	"sermo-be/pkg/firebase"
+)
+
+// PushedNotification 가짜 푸시로 보낸 알림
+type PushedNotification struct {
+	UserUUID     string
+	Notification *firebase.ChatNotification
+}
+
+// Push FCM 대신 보낸 알림을 기록하는 chat.PushSender 구현
+type Push struct {
+	// Err 설정되면 전송이 이 에러로 실패 (실패한 알림은 기록하지 않음)
+	Err error
+
+	mu   sync.Mutex
+	sent []PushedNotification
+}
+
+var _ chat.PushSender = (*Push)(nil)
+
+// SendToUser 알림 기록
+func (p *Push) SendToUser(ctx context.Context, userUUID string, notification *firebase.ChatNotification) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Err != nil {
+		return p.Err
+	}
+	p.sent = append(p.sent, PushedNotification{UserUUID: userUUID, Notification: notification})
+	return nil
+}
+
+// Sent 지금까지 보낸 알림
+func (p *Push) Sent() []PushedNotification {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PushedNotification(nil), p.sent...)
+}
+
+// WaitSent 알림이 count개 이상 보내질 때까지 대기 (채팅 종료 처리는 백그라운드에서 실행됨)
+func (p *Push) WaitSent(t testing.TB, count int) []PushedNotification {
+	t.Helper()
+
+	var sent []PushedNotification
+	Eventually(t, "push notification", func() bool {
+		sent = p.Sent()
+		return len(sent) >= count
+	})
+	return sent
+}
+
+// WaitTimeout Eventually가 조건을 기다리는 최대 시간
+var WaitTimeout = 5 * time.Second
+
+// Eventually 조건이 참이 될 때까지 대기 (WaitTimeout을 넘기면 테스트 실패)
+func Eventually(t testing.TB, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(WaitTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,223 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"sermo-be/internal/config"
+	"sermo-be/internal/container"
+	"sermo-be/internal/core/chat"
+	"sermo-be/internal/core/recall"
+	"sermo-be/internal/core/token"
+	"sermo-be/internal/core/usage"
+	"sermo-be/internal/middleware"
+	"sermo-be/internal/repository"
+	"sermo-be/internal/routes"
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/llmtest"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Server 가짜 외부 서비스로 띄운 테스트 서버
+// 라우트와 미들웨어는 실제 서버와 같고, 모든 기능의 언어 모델은 LLM 하나가 처리한다.
+type Server struct {
+	URL string
+
+	DB      *gorm.DB
+	Repos   *repository.Repositories
+	Chat    *chat.Services
+	SSE     *middleware.SSEManager
+	LLM     *llmtest.Fake
+	Push    *Push
+	Objects *ObjectStore
+
+	client *http.Client
+}
+
+// NewServer 테스트 서버 시작 (model이 nil이면 DefaultScript 응답 사용, 테스트가 끝나면 종료)
+func NewServer(t testing.TB, model *llmtest.Fake) *Server {
+	t.Helper()
+
+	if model == nil {
+		model = DefaultScript().LLM()
+	}
+
+	ConfigureJWT(t)
+	db := NewDB(t)
+	objects := NewObjectStore(t)
+	push := &Push{}
+
+	sse := middleware.NewSSEManager(middleware.DefaultMaxSessions)
+
+	repos := repository.NewGormRepositories(db)
+	usageService := usage.NewUsageService(db, usage.Quota{})
+	appContainer := &container.Container{
+		Config: &config.Config{Server: config.ServerConfig{Env: "test"}},
+		DB:     db,
+		LLM:    llm.NewRouter().RouteAll(model),
+		R2:     objects.NewClient(t),
+		Repos:  repos,
+		Tokens: token.NewTokenService(db),
+		Usage:  usageService,
+		Chat:   chat.NewServices(repos, db, recall.NewService(recall.NewMemoryStore()), usageService, push),
+		SSE:    sse,
+	}
+
+	app := fiber.New()
+	appContainer.Register(app)
+	routes.SetupRoutes(app)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(listener)
+
+	s := &Server{
+		URL:     "http://" + listener.Addr().String(),
+		DB:      db,
+		Repos:   repos,
+		Chat:    appContainer.Chat,
+		SSE:     sse,
+		LLM:     model,
+		Push:    push,
+		Objects: objects,
+		client:  &http.Client{},
+	}
+	t.Cleanup(func() {
+		// 세션을 먼저 끝내야 열려 있는 SSE 스트림이 닫혀 서버가 바로 종료됨
+		sse.Shutdown()
+		s.client.CloseIdleConnections()
+		app.ShutdownWithTimeout(time.Second)
+	})
+	return s
+}
+
+// Do 요청을 보내고 상태 코드와 본문 반환 (body가 nil이 아니면 JSON으로 전송, token이 있으면 Bearer 인증)
+func (s *Server) Do(t testing.TB, method, path, token string, body interface{}) (int, []byte) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal %s %s: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	if err != nil {
+		t.Fatalf("new request %s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.send(t, req, token)
+}
+
+// JSON 요청을 보내고 응답 코드가 want가 아니면 실패, 응답 본문은 out으로 디코딩 (out이 nil이면 무시)
+func (s *Server) JSON(t testing.TB, method, path, token string, body interface{}, want int, out interface{}) {
+	t.Helper()
+
+	status, data := s.Do(t, method, path, token, body)
+	if status != want {
+		t.Fatalf("%s %s = %d, want %d: %s", method, path, status, want, data)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("decode %s %s: %v: %s", method, path, err, data)
+		}
+	}
+}
+
+// SignUp 회원가입 후 로그인해 사용자 UUID와 액세스 토큰 반환
+func (s *Server) SignUp(t testing.TB, id, password string) (userUUID, token string) {
+	t.Helper()
+
+	var signup struct {
+		UUID string `json:"uuid"`
+	}
+	s.JSON(t, http.MethodPost, "/auth/signup", "", map[string]string{
+		"id":       id,
+		"nickname": id,
+		"password": password,
+	}, http.StatusCreated, &signup)
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	s.JSON(t, http.MethodPost, "/auth/login", "", map[string]string{
+		"id":       id,
+		"password": password,
+	}, http.StatusOK, &login)
+
+	return signup.UUID, login.Token
+}
+
+// UploadImage 이미지를 업로드하고 이미지 ID 반환
+func (s *Server) UploadImage(t testing.TB, token, fileName string, data []byte) string {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	header.Set("Content-Type", "image/png")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		t.Fatalf("multipart: %v", err)
+	}
+	part.Write(data)
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+"/image/upload", &body)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	status, respBody := s.send(t, req, token)
+	if status != http.StatusOK {
+		t.Fatalf("POST /image/upload = %d: %s", status, respBody)
+	}
+
+	var uploaded struct {
+		Image struct {
+			ID string `json:"id"`
+		} `json:"image"`
+	}
+	if err := json.Unmarshal(respBody, &uploaded); err != nil {
+		t.Fatalf("decode upload: %v: %s", err, respBody)
+	}
+	return uploaded.Image.ID
+}
+
+// send 인증 헤더를 붙여 요청 전송
+func (s *Server) send(t testing.TB, req *http.Request, token string) (int, []byte) {
+	t.Helper()
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	return resp.StatusCode, data
+}
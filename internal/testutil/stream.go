@@ -0,0 +1,119 @@
+package testutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Event SSE 스트림으로 받은 이벤트
+type Event struct {
+	ID   string
+	Type string
+	Data map[string]interface{}
+}
+
+// Field 이벤트 JSON의 문자열 필드 (없으면 빈 문자열)
+func (e Event) Field(name string) string {
+	value, _ := e.Data[name].(string)
+	return value
+}
+
+// Stream 열려 있는 SSE 연결
+// heartbeat처럼 JSON이 아닌 data는 건너뛰고, type 필드가 있는 이벤트만 순서대로 전달한다.
+type Stream struct {
+	resp   *http.Response
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// OpenStream GET 요청으로 SSE 연결을 열고 200 응답이 아니면 실패 (테스트가 끝나면 닫힘)
+func (s *Server) OpenStream(t testing.TB, path, token string) *Stream {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+path, nil)
+	if err != nil {
+		t.Fatalf("new request GET %s: %v", path, err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var body strings.Builder
+		bufio.NewReader(resp.Body).WriteTo(&body)
+		resp.Body.Close()
+		t.Fatalf("GET %s = %d: %s", path, resp.StatusCode, body.String())
+	}
+
+	stream := &Stream{resp: resp, events: make(chan Event, 64), done: make(chan struct{})}
+	go stream.read()
+	t.Cleanup(stream.Close)
+	return stream
+}
+
+// Next eventType 이벤트가 올 때까지 대기 (그 사이의 다른 이벤트는 버림, WaitTimeout을 넘기면 실패)
+func (s *Stream) Next(t testing.TB, eventType string) Event {
+	t.Helper()
+
+	timeout := time.After(WaitTimeout)
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				t.Fatalf("stream closed before %s event", eventType)
+			}
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", eventType)
+		}
+	}
+}
+
+// Close 연결 종료
+func (s *Stream) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.resp.Body.Close()
+	})
+}
+
+// read 스트림을 읽어 이벤트 단위로 전달 (빈 줄이 이벤트 끝)
+func (s *Stream) read() {
+	defer close(s.events)
+
+	scanner := bufio.NewScanner(s.resp.Body)
+	var id, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			var payload map[string]interface{}
+			if err := json.Unmarshal([]byte(data), &payload); err == nil {
+				eventType, _ := payload["type"].(string)
+				select {
+				case s.events <- Event{ID: id, Type: eventType, Data: payload}:
+				case <-s.done:
+					return
+				}
+			}
+			id, data = "", ""
+		}
+	}
+}
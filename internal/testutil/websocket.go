@@ -0,0 +1,126 @@
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+)
+
+// WebSocket 열려 있는 /chat/ws 연결
+// 서버가 보낸 JSON 텍스트 메시지를 type 필드 기준 이벤트로 순서대로 전달한다.
+type WebSocket struct {
+	conn   *websocket.Conn
+	events chan Event
+	err    error // 읽기 루프를 끝낸 에러 (서버가 보낸 close 프레임 포함, events가 닫힌 뒤에만 유효)
+	closed chan struct{}
+	once   sync.Once
+}
+
+// OpenWebSocket WebSocket 연결을 열고 업그레이드에 실패하면 실패 (테스트가 끝나면 닫힘)
+func (s *Server) OpenWebSocket(t testing.TB, path, token string) *WebSocket {
+	t.Helper()
+
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	url := "ws" + strings.TrimPrefix(s.URL, "http") + path
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial %s = %d: %v", path, status, err)
+	}
+
+	ws := &WebSocket{conn: conn, events: make(chan Event, 64), closed: make(chan struct{})}
+	go ws.read()
+	t.Cleanup(ws.Close)
+	return ws
+}
+
+// Send 클라이언트 메시지 전송 (예: {"type":"user","content":"..."})
+func (w *WebSocket) Send(t testing.TB, message interface{}) {
+	t.Helper()
+
+	w.conn.SetWriteDeadline(time.Now().Add(WaitTimeout))
+	if err := w.conn.WriteJSON(message); err != nil {
+		t.Fatalf("websocket write: %v", err)
+	}
+}
+
+// Next eventType 이벤트가 올 때까지 대기 (그 사이의 다른 이벤트는 버림, WaitTimeout을 넘기면 실패)
+func (w *WebSocket) Next(t testing.TB, eventType string) Event {
+	t.Helper()
+
+	timeout := time.After(WaitTimeout)
+	for {
+		select {
+		case event, ok := <-w.events:
+			if !ok {
+				t.Fatalf("websocket closed before %s event: %v", eventType, w.err)
+			}
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", eventType)
+		}
+	}
+}
+
+// WaitClosed 서버가 연결을 닫을 때까지 남은 이벤트를 버리며 대기하고 종료 에러 반환
+func (w *WebSocket) WaitClosed(t testing.TB) error {
+	t.Helper()
+
+	timeout := time.After(WaitTimeout)
+	for {
+		select {
+		case _, ok := <-w.events:
+			if !ok {
+				return w.err
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the server to close the websocket")
+		}
+	}
+}
+
+// Close stop 없이 연결 종료 (네트워크가 끊긴 것과 같음)
+func (w *WebSocket) Close() {
+	w.once.Do(func() {
+		close(w.closed)
+		w.conn.Close()
+	})
+}
+
+// read 서버 메시지를 읽어 이벤트로 전달
+func (w *WebSocket) read() {
+	defer close(w.events)
+
+	for {
+		_, data, err := w.conn.ReadMessage()
+		if err != nil {
+			w.err = err
+			return
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			continue
+		}
+		eventType, _ := payload["type"].(string)
+		select {
+		case w.events <- Event{Type: eventType, Data: payload}:
+		case <-w.closed:
+			return
+		}
+	}
+}
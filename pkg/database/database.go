@@ -1,12 +1,11 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
-	"sermo-be/internal/models"
-
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -74,30 +73,27 @@ func Close() error {
 	return nil
 }
 
-// RunMigrations 데이터베이스 마이그레이션 실행
-func RunMigrations() error {
+// RunMigrations 미적용 버전 마이그레이션 실행 (pkg/database/migrations)
+func RunMigrations(ctx context.Context) error {
 	if DB == nil {
 		return fmt.Errorf("database not connected")
 	}
 
-	// 마이그레이션할 모델들
-	models := []interface{}{
-		&models.User{},
-		&models.Image{},
-		&models.Chatbot{},
-		&models.ChatMessage{},
-		&models.SentenceBookmark{},
-		&models.WordBookmark{},
-		&models.UserStatus{},
-		&models.FCMToken{},
-		&models.AlarmSchedule{},
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %v", err)
+	}
+
+	migrator, err := NewMigrator(sqlDB)
+	if err != nil {
+		return err
 	}
 
-	err := DB.AutoMigrate(models...)
+	applied, err := migrator.Up(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %v", err)
 	}
 
-	log.Println("✅ Database migrations completed successfully")
+	log.Printf("✅ Database migrations completed successfully (%d applied)", applied)
 	return nil
 }
@@ -0,0 +1,104 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sermo-be/internal/models"
+	"sermo-be/internal/testutil"
+	"sermo-be/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// migratedModels 마이그레이션이 만들어야 하는 모델 테이블 (SQLite 테스트 DB에서 빠지는 memory_embeddings 포함)
+func migratedModels() []interface{} {
+	return append(append([]interface{}{}, testutil.SchemaModels...), &models.MemoryEmbedding{})
+}
+
+// assertModelTables 모델의 테이블과 모든 컬럼이 있는지(present) 또는 테이블이 없는지 확인
+func assertModelTables(t *testing.T, db *gorm.DB, present bool) {
+	t.Helper()
+	for _, model := range migratedModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if db.Migrator().HasTable(table) != present {
+			t.Errorf("table %s exists = %v, want %v", table, !present, present)
+			continue
+		}
+		if !present {
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(table, field.DBName) {
+				t.Errorf("column %s.%s (%T.%s) is missing from the migrations", table, field.DBName, model, field.Name)
+			}
+		}
+	}
+}
+
+func TestMigrationsUpAndDown(t *testing.T) {
+	db := testutil.NewPostgresDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("postgres connection: %v", err)
+	}
+	migrator, err := database.NewMigrator(sqlDB)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	total := len(statuses)
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if applied != total {
+		t.Fatalf("applied %d migrations, want %d", applied, total)
+	}
+	assertModelTables(t, db, true)
+
+	if again, err := migrator.Up(ctx); err != nil || again != 0 {
+		t.Fatalf("second Up = %d, %v, want nothing to apply", again, err)
+	}
+
+	// 모두 되돌리면 모델 테이블이 남지 않고 다시 적용할 수 있어야 함
+	reverted, err := migrator.Down(ctx, total)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if reverted != total {
+		t.Fatalf("reverted %d migrations, want %d", reverted, total)
+	}
+	assertModelTables(t, db, false)
+
+	statuses, err = migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Errorf("migration %d_%s still applied after Down", status.Version, status.Name)
+		}
+	}
+
+	if applied, err := migrator.Up(ctx); err != nil || applied != total {
+		t.Fatalf("Up after Down = %d, %v, want %d", applied, err, total)
+	}
+	assertModelTables(t, db, true)
+}
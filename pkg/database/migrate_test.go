@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsAreValid(t *testing.T) {
+	migrations, err := LoadMigrations(embeddedMigrations, "migrations")
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.DownSQL == "" {
+			t.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		if i > 0 && migrations[i-1].Version >= migration.Version {
+			t.Errorf("migrations not sorted: %d before %d", migrations[i-1].Version, migration.Version)
+		}
+	}
+}
+
+func TestLoadMigrationsSortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0010_add_index.up.sql":    {Data: []byte("CREATE INDEX x;")},
+		"m/0002_create_foo.up.sql":   {Data: []byte("CREATE TABLE foo();")},
+		"m/0002_create_foo.down.sql": {Data: []byte("DROP TABLE foo;")},
+	}
+
+	migrations, err := LoadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 2 || migrations[0].Name != "create_foo" || migrations[0].DownSQL != "DROP TABLE foo;" {
+		t.Errorf("unexpected first migration: %+v", migrations[0])
+	}
+	if migrations[1].Version != 10 || migrations[1].DownSQL != "" {
+		t.Errorf("unexpected second migration: %+v", migrations[1])
+	}
+}
+
+func TestLoadMigrationsRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"bad name": {
+			"m/create_foo.sql": {Data: []byte("x")},
+		},
+		"down without up": {
+			"m/0001_create_foo.down.sql": {Data: []byte("x")},
+		},
+		"conflicting names": {
+			"m/0001_create_foo.up.sql": {Data: []byte("x")},
+			"m/0001_create_bar.up.sql": {Data: []byte("x")},
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadMigrations(fsys, "m"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS alarm_schedules;
+DROP TABLE IF EXISTS fcm_tokens;
+DROP TABLE IF EXISTS user_statuses;
+DROP TABLE IF EXISTS word_bookmarks;
+DROP TABLE IF EXISTS sentence_bookmarks;
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS chatbots;
+DROP TABLE IF EXISTS images;
+DROP TABLE IF EXISTS users;
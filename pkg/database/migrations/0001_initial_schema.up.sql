@@ -0,0 +1,117 @@
+-- 초기 스키마 (기존 AutoMigrate로 생성된 DB와 호환되도록 IF NOT EXISTS 사용)
+
+CREATE TABLE IF NOT EXISTS users (
+    uuid       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    id         VARCHAR(20)  NOT NULL,
+    nickname   VARCHAR(100) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_id ON users (id);
+
+CREATE TABLE IF NOT EXISTS images (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    VARCHAR(255) NOT NULL,
+    file_name  VARCHAR(255) NOT NULL,
+    file_size  BIGINT       NOT NULL,
+    mime_type  VARCHAR(100) NOT NULL,
+    file_key   TEXT         NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_images_user_id ON images (user_id);
+
+CREATE TABLE IF NOT EXISTS chatbots (
+    uuid       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       VARCHAR(100) NOT NULL,
+    image_id   VARCHAR(255) NOT NULL,
+    hashtags   JSONB,
+    gender     VARCHAR(20)  NOT NULL DEFAULT 'unspecified',
+    details    TEXT,
+    summary    TEXT,
+    user_uuid  VARCHAR(36)  NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    uuid         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id   VARCHAR(36) NOT NULL,
+    user_uuid    VARCHAR(36) NOT NULL,
+    chatbot_uuid VARCHAR(36) NOT NULL,
+    message_type VARCHAR(20) NOT NULL,
+    content      TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_session_id ON chat_messages (session_id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_user_uuid ON chat_messages (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_chatbot_uuid ON chat_messages (chatbot_uuid);
+
+CREATE TABLE IF NOT EXISTS sentence_bookmarks (
+    uuid       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid  UUID NOT NULL,
+    sentence   TEXT NOT NULL,
+    meaning    TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sentence_bookmarks_user_uuid ON sentence_bookmarks (user_uuid);
+
+CREATE TABLE IF NOT EXISTS word_bookmarks (
+    uuid       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid  UUID         NOT NULL,
+    word       VARCHAR(100) NOT NULL,
+    meaning    TEXT         NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_word_bookmarks_user_uuid ON word_bookmarks (user_uuid);
+
+CREATE TABLE IF NOT EXISTS user_statuses (
+    uuid         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid    VARCHAR(36)  NOT NULL,
+    chatbot_uuid VARCHAR(36)  NOT NULL,
+    event        VARCHAR(255) NOT NULL,
+    valid_until  TIMESTAMP    NOT NULL,
+    context      TEXT,
+    is_active    BOOLEAN DEFAULT TRUE,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_user_statuses_user_uuid ON user_statuses (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_user_statuses_chatbot_uuid ON user_statuses (chatbot_uuid);
+
+CREATE TABLE IF NOT EXISTS fcm_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    user_uuid   UUID DEFAULT NULL,
+    fcm_token   VARCHAR(255) NOT NULL,
+    device_info VARCHAR(100) DEFAULT '',
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_fcm_tokens_user_uuid ON fcm_tokens (user_uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fcm_tokens_fcm_token ON fcm_tokens (fcm_token);
+CREATE INDEX IF NOT EXISTS idx_fcm_tokens_deleted_at ON fcm_tokens (deleted_at);
+
+CREATE TABLE IF NOT EXISTS alarm_schedules (
+    id             BIGSERIAL PRIMARY KEY,
+    user_uuid      TEXT        NOT NULL,
+    chatbot_uuid   TEXT        NOT NULL,
+    chatbot_name   TEXT        NOT NULL,
+    chatbot_avatar TEXT,
+    message        TEXT        NOT NULL,
+    send_time      TIMESTAMPTZ NOT NULL,
+    keywords       JSON,
+    context        TEXT,
+    sended         BOOLEAN DEFAULT FALSE,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_alarm_schedules_user_uuid ON alarm_schedules (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_alarm_schedules_chatbot_uuid ON alarm_schedules (chatbot_uuid);
+CREATE INDEX IF NOT EXISTS idx_alarm_schedules_send_time ON alarm_schedules (send_time);
+CREATE INDEX IF NOT EXISTS idx_alarm_schedules_sended ON alarm_schedules (sended);
+CREATE INDEX IF NOT EXISTS idx_alarm_schedules_deleted_at ON alarm_schedules (deleted_at);
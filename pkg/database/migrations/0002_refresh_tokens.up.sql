@@ -0,0 +1,19 @@
+-- 리프레시 토큰 (토큰 원문 대신 SHA-256 해시만 저장)
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    uuid           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid      VARCHAR(36)  NOT NULL,
+    family_id      VARCHAR(36)  NOT NULL,
+    token_hash     VARCHAR(64)  NOT NULL,
+    device_info    VARCHAR(255) DEFAULT '',
+    expires_at     TIMESTAMPTZ  NOT NULL,
+    revoked        BOOLEAN DEFAULT FALSE,
+    revoked_at     TIMESTAMPTZ,
+    replaced_by_id UUID,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_uuid ON refresh_tokens (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_revoked ON refresh_tokens (revoked);
@@ -0,0 +1,3 @@
+ALTER TABLE chatbots DROP COLUMN IF EXISTS max_reply_wait_ms;
+ALTER TABLE chatbots DROP COLUMN IF EXISTS typing_extension_ms;
+ALTER TABLE chatbots DROP COLUMN IF EXISTS reply_delay_ms;
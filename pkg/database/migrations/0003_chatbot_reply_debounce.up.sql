@@ -0,0 +1,5 @@
+-- 채팅봇별 봇 응답 대기(debounce) 설정 (NULL이면 서버 기본값 사용)
+
+ALTER TABLE chatbots ADD COLUMN IF NOT EXISTS reply_delay_ms      INTEGER;
+ALTER TABLE chatbots ADD COLUMN IF NOT EXISTS typing_extension_ms INTEGER;
+ALTER TABLE chatbots ADD COLUMN IF NOT EXISTS max_reply_wait_ms   INTEGER;
@@ -0,0 +1,11 @@
+-- 채팅 히스토리 커서 페이지네이션 및 본문 전문 검색
+
+-- (user_uuid, chatbot_uuid) 대화 안에서 (created_at, uuid) 순서로 페이지를 나눔
+CREATE INDEX IF NOT EXISTS idx_chat_messages_conversation_created
+    ON chat_messages (user_uuid, chatbot_uuid, created_at, uuid);
+
+-- 한국어/영어가 섞여 있어 'simple' 사전 사용 (조회 시 plainto_tsquery('simple', ...)와 맞춰야 함)
+ALTER TABLE chat_messages
+    ADD COLUMN IF NOT EXISTS content_tsv tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+CREATE INDEX IF NOT EXISTS idx_chat_messages_content_tsv ON chat_messages USING GIN (content_tsv);
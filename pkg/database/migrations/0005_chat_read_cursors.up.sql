@@ -0,0 +1,10 @@
+-- 대화 목록의 읽지 않은 메시지 수 계산을 위한 사용자-채팅봇별 읽음 위치
+
+CREATE TABLE IF NOT EXISTS chat_read_cursors (
+    user_uuid              VARCHAR(36) NOT NULL,
+    chatbot_uuid           VARCHAR(36) NOT NULL,
+    last_read_message_uuid UUID        NOT NULL,
+    last_read_at           TIMESTAMPTZ NOT NULL,
+    updated_at             TIMESTAMPTZ,
+    PRIMARY KEY (user_uuid, chatbot_uuid)
+);
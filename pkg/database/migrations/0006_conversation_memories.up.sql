@@ -0,0 +1,12 @@
+-- 사용자-채팅봇 대화의 누적 요약 (오래된 메시지를 요약해 봇 응답 프롬프트에 넣음)
+
+CREATE TABLE IF NOT EXISTS conversation_memories (
+    user_uuid             VARCHAR(36) NOT NULL,
+    chatbot_uuid          VARCHAR(36) NOT NULL,
+    summary               TEXT        NOT NULL,
+    summarized_until_at   TIMESTAMPTZ NOT NULL,
+    summarized_until_uuid UUID        NOT NULL,
+    summarized_count      INTEGER     NOT NULL DEFAULT 0,
+    updated_at            TIMESTAMPTZ,
+    PRIMARY KEY (user_uuid, chatbot_uuid)
+);
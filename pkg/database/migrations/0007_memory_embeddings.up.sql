@@ -0,0 +1,17 @@
+-- 지난 대화 검색을 위한 사용자 메시지/상태 정보 임베딩 (pgvector)
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS memory_embeddings (
+    uuid         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid    VARCHAR(36)  NOT NULL,
+    chatbot_uuid VARCHAR(36)  NOT NULL,
+    source_type  VARCHAR(20)  NOT NULL,
+    source_uuid  UUID         NOT NULL,
+    content      TEXT         NOT NULL,
+    embedding    vector(1536) NOT NULL,
+    created_at   TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_memory_embeddings_source ON memory_embeddings (source_type, source_uuid);
+CREATE INDEX IF NOT EXISTS idx_memory_embeddings_conversation ON memory_embeddings (user_uuid, chatbot_uuid);
+CREATE INDEX IF NOT EXISTS idx_memory_embeddings_embedding ON memory_embeddings USING hnsw (embedding vector_cosine_ops);
@@ -0,0 +1,14 @@
+-- 사용자별 AI 사용량 기록 (언어 모델 토큰, 생성 이미지 수)
+
+CREATE TABLE IF NOT EXISTS usage_records (
+    uuid              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid         VARCHAR(36)  NOT NULL,
+    feature           VARCHAR(30)  NOT NULL,
+    model             VARCHAR(100) NOT NULL,
+    prompt_tokens     INTEGER      NOT NULL DEFAULT 0,
+    completion_tokens INTEGER      NOT NULL DEFAULT 0,
+    total_tokens      INTEGER      NOT NULL DEFAULT 0,
+    images            INTEGER      NOT NULL DEFAULT 0,
+    created_at        TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_usage_records_user_created ON usage_records (user_uuid, created_at);
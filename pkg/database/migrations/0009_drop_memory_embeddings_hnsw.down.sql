@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_memory_embeddings_embedding ON memory_embeddings USING hnsw (embedding vector_cosine_ops);
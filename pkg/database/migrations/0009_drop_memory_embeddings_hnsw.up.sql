@@ -0,0 +1,5 @@
+-- 기억 검색은 항상 (user_uuid, chatbot_uuid)로 거른 뒤 거리순으로 정렬한다.
+-- hnsw 인덱스는 거르기 전에 ef_search개 후보만 가져와 다른 대화의 임베딩이 많으면 k개보다 적게 돌려주므로
+-- 삭제하고 대화 인덱스로 거른 행만 정확히 비교한다 (대화 하나의 임베딩 수는 적음).
+
+DROP INDEX IF EXISTS idx_memory_embeddings_embedding;
@@ -10,6 +10,9 @@ import (
 	"time"
 )
 
+// ImageModel 이미지 생성에 사용하는 Gemini 모델
+const ImageModel = "gemini-2.0-flash-preview-image-generation"
+
 // ImageClient Gemini 이미지 생성 API 클라이언트
 type ImageClient struct {
 	apiKey     string
@@ -29,7 +32,16 @@ type ImageGenerationRequest struct {
 
 // Gemini API 응답 구조 (Google 공식 문서 기반)
 type GeminiResponse struct {
-	Candidates []Candidate `json:"candidates"`
+	Candidates    []Candidate    `json:"candidates"`
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"`
+	ModelVersion  string         `json:"modelVersion,omitempty"`
+}
+
+// UsageMetadata 요청의 토큰 사용량
+type UsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
 }
 
 type Candidate struct {
@@ -52,7 +64,9 @@ type InlineData struct {
 
 // ImageGenerationResponse 이미지 생성 응답 (내부 사용)
 type ImageGenerationResponse struct {
-	Images []ImageData `json:"images"`
+	Images []ImageData   `json:"images"`
+	Model  string        `json:"model"`
+	Usage  UsageMetadata `json:"usage"`
 }
 
 // ImageData 이미지 데이터
@@ -108,7 +122,7 @@ func (c *ImageClient) GenerateImage(ctx context.Context, prompt string) (*ImageG
 	}
 
 	// HTTP 요청 생성 (올바른 모델명 사용)
-	url := fmt.Sprintf("%s/gemini-2.0-flash-preview-image-generation:generateContent?key=%s", c.baseURL, c.apiKey)
+	url := fmt.Sprintf("%s/%s:generateContent?key=%s", c.baseURL, ImageModel, c.apiKey)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -162,6 +176,13 @@ func (c *ImageClient) GenerateImage(ctx context.Context, prompt string) (*ImageG
 	// 내부 응답 구조로 변환
 	response := ImageGenerationResponse{
 		Images: images,
+		Model:  geminiResponse.ModelVersion,
+	}
+	if response.Model == "" {
+		response.Model = ImageModel
+	}
+	if geminiResponse.UsageMetadata != nil {
+		response.Usage = *geminiResponse.UsageMetadata
 	}
 
 	return &response, nil
@@ -2,37 +2,68 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key-change-in-production")
+// AccessTokenTTL 액세스 토큰 유효 시간
+var AccessTokenTTL = 15 * time.Minute
 
 type Claims struct {
-	UUID string `json:"uuid"`
+	UUID      string `json:"uuid"`
+	SessionID string `json:"sid"` // 리프레시 토큰 계열 ID (세션 폐기 확인용)
 	jwt.RegisteredClaims
 }
 
-// GenerateToken JWT 토큰 생성
-func GenerateToken(uuid string) (string, error) {
+// GenerateToken 액세스 토큰 생성 (현재 활성 키로 서명하고 kid 헤더 설정)
+func GenerateToken(uuid, sessionID string) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		UUID: uuid,
+		UUID:      uuid,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 24시간 유효
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	set := getKeySet()
+	if set == nil {
+		return "", ErrNotConfigured
+	}
+
+	key := set.active
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.signKey)
 }
 
-// ValidateToken JWT 토큰 검증
+// ValidateToken JWT 토큰 검증 (kid 헤더로 검증 키 선택)
 func ValidateToken(tokenString string) (*Claims, error) {
+	set := getKeySet()
+	if set == nil {
+		return nil, ErrNotConfigured
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+		key := set.active
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+			found, exists := set.keys[kid]
+			if !exists {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+			key = found
+		}
+
+		// 키에 지정된 알고리즘 외의 서명은 거부 (알고리즘 혼동 공격 방지)
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+		}
+
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
@@ -45,8 +76,3 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
-
-// SetSecret JWT 시크릿 키 설정 (환경변수에서 가져올 때 사용)
-func SetSecret(secret string) {
-	jwtSecret = []byte(secret)
-}
@@ -0,0 +1,348 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret-that-is-at-least-32-bytes"
+
+// testKey 테스트용 키 설정과 직접 서명할 때 쓰는 개인키
+type testKey struct {
+	config    KeyConfig
+	signKey   interface{}
+	publicPEM []byte
+}
+
+func newRSAKey(t *testing.T, id string) testKey {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	privateDER := x509.MarshalPKCS1PrivateKey(privateKey)
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal RSA public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return testKey{
+		config: KeyConfig{
+			ID:            id,
+			Algorithm:     AlgorithmRS256,
+			PrivateKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: privateDER})),
+			PublicKeyPEM:  string(publicPEM),
+		},
+		signKey:   privateKey,
+		publicPEM: publicPEM,
+	}
+}
+
+func newEdDSAKey(t *testing.T, id string) testKey {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate Ed25519 key: %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal Ed25519 private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("marshal Ed25519 public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return testKey{
+		config: KeyConfig{
+			ID:            id,
+			Algorithm:     AlgorithmEdDSA,
+			PrivateKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+			PublicKeyPEM:  string(publicPEM),
+		},
+		signKey:   privateKey,
+		publicPEM: publicPEM,
+	}
+}
+
+func newHS256Key(id, secret string) testKey {
+	return testKey{
+		config:  KeyConfig{ID: id, Algorithm: AlgorithmHS256, Secret: secret},
+		signKey: []byte(secret),
+	}
+}
+
+// verifyOnly 개인키를 뺀 검증 전용 설정 (로테이션으로 퇴역한 키)
+func (k testKey) verifyOnly() KeyConfig {
+	cfg := k.config
+	cfg.PrivateKeyPEM = ""
+	return cfg
+}
+
+// configure 키 설정을 적용하고 테스트가 끝나면 이전 키 모음으로 되돌림
+func configure(t *testing.T, activeKeyID string, keys ...KeyConfig) {
+	t.Helper()
+	previous := getKeySet()
+	t.Cleanup(func() {
+		keysMutex.Lock()
+		currentSet = previous
+		keysMutex.Unlock()
+	})
+
+	if err := Configure(&Config{ActiveKeyID: activeKeyID, Keys: keys}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+}
+
+// signToken 패키지를 거치지 않고 원하는 알고리즘과 kid로 직접 서명
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(method, Claims{
+		UUID:      "user-uuid",
+		SessionID: "session-id",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestValidateTokenKeySelection(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	edKey := newEdDSAKey(t, "ed")
+	hsKey := newHS256Key("hs", testSecret)
+	otherRSA := newRSAKey(t, "other")
+
+	configure(t, "rsa", rsaKey.config, edKey.config, hsKey.config)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "active RS256 key",
+			token: signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey.signKey),
+		},
+		{
+			name:  "non-active EdDSA key",
+			token: signToken(t, jwt.SigningMethodEdDSA, "ed", edKey.signKey),
+		},
+		{
+			name:  "non-active HS256 key",
+			token: signToken(t, jwt.SigningMethodHS256, "hs", hsKey.signKey),
+		},
+		{
+			name:  "no kid falls back to active key",
+			token: signToken(t, jwt.SigningMethodRS256, "", rsaKey.signKey),
+		},
+		{
+			name:    "no kid signed by another key",
+			token:   signToken(t, jwt.SigningMethodEdDSA, "", edKey.signKey),
+			wantErr: "unexpected signing method",
+		},
+		{
+			name:    "unknown kid",
+			token:   signToken(t, jwt.SigningMethodRS256, "missing", otherRSA.signKey),
+			wantErr: "unknown key id",
+		},
+		{
+			name:    "wrong key for kid",
+			token:   signToken(t, jwt.SigningMethodRS256, "rsa", otherRSA.signKey),
+			wantErr: "verification error",
+		},
+		{
+			// 공개키를 HMAC 비밀키로 쓰는 알고리즘 혼동 공격
+			name:    "HS256 against RS256 kid",
+			token:   signToken(t, jwt.SigningMethodHS256, "rsa", rsaKey.publicPEM),
+			wantErr: "unexpected signing method",
+		},
+		{
+			name:    "HS256 against EdDSA kid",
+			token:   signToken(t, jwt.SigningMethodHS256, "ed", edKey.publicPEM),
+			wantErr: "unexpected signing method",
+		},
+		{
+			name:    "RS256 against HS256 kid",
+			token:   signToken(t, jwt.SigningMethodRS256, "hs", rsaKey.signKey),
+			wantErr: "unexpected signing method",
+		},
+		{
+			name:    "EdDSA against HS256 kid",
+			token:   signToken(t, jwt.SigningMethodEdDSA, "hs", edKey.signKey),
+			wantErr: "unexpected signing method",
+		},
+		{
+			name:    "EdDSA against RS256 kid",
+			token:   signToken(t, jwt.SigningMethodEdDSA, "rsa", edKey.signKey),
+			wantErr: "unexpected signing method",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ValidateToken(tt.token)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateToken: %v", err)
+				}
+				if claims.UUID != "user-uuid" || claims.SessionID != "session-id" {
+					t.Fatalf("claims = %+v", claims)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetiredKeyStillVerifies(t *testing.T) {
+	oldKey := newRSAKey(t, "2025-07")
+	newKey := newEdDSAKey(t, "2025-10")
+
+	configure(t, "2025-07", oldKey.config)
+	oldToken, err := GenerateToken("user-uuid", "session-id")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	// 새 키로 교체하고 이전 키는 공개키만 남김
+	configure(t, "2025-10", newKey.config, oldKey.verifyOnly())
+	if ActiveKeyID() != "2025-10" {
+		t.Fatalf("active kid = %q", ActiveKeyID())
+	}
+
+	if _, err := ValidateToken(oldToken); err != nil {
+		t.Fatalf("token signed by retired key: %v", err)
+	}
+
+	newToken, err := GenerateToken("user-uuid", "session-id")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Header["kid"] != "2025-10" || parsed.Method.Alg() != AlgorithmEdDSA {
+		t.Fatalf("new token header = %v", parsed.Header)
+	}
+	if _, err := ValidateToken(newToken); err != nil {
+		t.Fatalf("token signed by active key: %v", err)
+	}
+}
+
+func TestConfigureRejectsVerifyOnlyActiveKey(t *testing.T) {
+	retired := newRSAKey(t, "retired")
+	err := Configure(&Config{ActiveKeyID: "retired", Keys: []KeyConfig{retired.verifyOnly()}})
+	if err == nil || !strings.Contains(err.Error(), "no private key") {
+		t.Fatalf("error = %v, want no private key", err)
+	}
+}
+
+func TestPublicJWKSExcludesSymmetricKeys(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	edKey := newEdDSAKey(t, "ed")
+	hsKey := newHS256Key("hs", testSecret)
+
+	configure(t, "hs", hsKey.config, rsaKey.verifyOnly(), edKey.config)
+
+	set := PublicJWKS()
+	kids := make([]string, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		kids = append(kids, key.Kid)
+	}
+	if strings.Join(kids, ",") != "ed,rsa" {
+		t.Fatalf("JWKS kids = %v, want [ed rsa]", kids)
+	}
+
+	body, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(body), testSecret) || strings.Contains(string(body), `"k"`) || strings.Contains(string(body), "HS256") {
+		t.Fatalf("JWKS exposes symmetric key material: %s", body)
+	}
+
+	if set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" || set.Keys[0].X == "" {
+		t.Errorf("Ed25519 JWK = %+v", set.Keys[0])
+	}
+	if set.Keys[1].Kty != "RSA" || set.Keys[1].N == "" || set.Keys[1].E != "AQAB" {
+		t.Errorf("RSA JWK = %+v", set.Keys[1])
+	}
+}
+
+func TestUnconfiguredKeysRejectTokens(t *testing.T) {
+	previous := getKeySet()
+	t.Cleanup(func() {
+		keysMutex.Lock()
+		currentSet = previous
+		keysMutex.Unlock()
+	})
+
+	keysMutex.Lock()
+	currentSet = nil
+	keysMutex.Unlock()
+
+	if _, err := GenerateToken("user-uuid", "session-id"); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("GenerateToken = %v, want ErrNotConfigured", err)
+	}
+
+	// 예전에 소스 코드에 있던 기본 비밀키로 위조한 토큰
+	forged := signToken(t, jwt.SigningMethodHS256, "default", []byte("your-secret-key-change-in-production"))
+	if _, err := ValidateToken(forged); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("ValidateToken = %v, want ErrNotConfigured", err)
+	}
+	if ActiveKeyID() != "" || len(PublicJWKS().Keys) != 0 {
+		t.Fatalf("unconfigured key set exposes kid %q / %d JWKs", ActiveKeyID(), len(PublicJWKS().Keys))
+	}
+}
+
+func TestNewRandomKey(t *testing.T) {
+	first, err := NewRandomKey("dev")
+	if err != nil {
+		t.Fatalf("NewRandomKey: %v", err)
+	}
+	second, err := NewRandomKey("dev")
+	if err != nil {
+		t.Fatalf("NewRandomKey: %v", err)
+	}
+	if first.Secret == second.Secret {
+		t.Fatal("random keys should not repeat")
+	}
+
+	configure(t, "dev", first)
+	signed, err := GenerateToken("user-uuid", "session-id")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := ValidateToken(signed); err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	configure(t, "dev", second)
+	if _, err := ValidateToken(signed); err == nil {
+		t.Fatal("token signed by another random key should not verify")
+	}
+}
@@ -0,0 +1,203 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 지원하는 서명 알고리즘
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// KeyConfig 서명 키 설정
+// HS256은 Secret, RS256/EdDSA는 PEM 형식의 개인키/공개키를 사용한다.
+// 개인키 없이 공개키만 있는 키는 검증 전용으로 사용된다 (로테이션으로 퇴역한 키).
+type KeyConfig struct {
+	ID            string
+	Algorithm     string
+	Secret        string
+	PrivateKeyPEM string
+	PublicKeyPEM  string
+}
+
+// Config JWT 서명 설정
+type Config struct {
+	ActiveKeyID string
+	Keys        []KeyConfig
+}
+
+// signingKey 파싱된 서명/검증 키
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// keySet kid로 식별되는 키 모음
+type keySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// ErrNotConfigured Configure 전에 토큰을 서명하거나 검증하려 할 때 반환
+var ErrNotConfigured = errors.New("JWT keys are not configured")
+
+var (
+	keysMutex  sync.RWMutex
+	currentSet *keySet
+)
+
+// Configure 서명 키 설정 적용
+func Configure(cfg *Config) error {
+	if cfg == nil || len(cfg.Keys) == 0 {
+		return errors.New("at least one JWT key is required")
+	}
+
+	set := &keySet{keys: make(map[string]*signingKey)}
+	for _, keyCfg := range cfg.Keys {
+		key, err := parseKey(keyCfg)
+		if err != nil {
+			return fmt.Errorf("invalid JWT key %q: %w", keyCfg.ID, err)
+		}
+		if _, exists := set.keys[key.id]; exists {
+			return fmt.Errorf("duplicate JWT key id %q", key.id)
+		}
+		set.keys[key.id] = key
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" {
+		activeID = cfg.Keys[0].ID
+	}
+
+	active, exists := set.keys[activeID]
+	if !exists {
+		return fmt.Errorf("active JWT key %q not found", activeID)
+	}
+	if active.signKey == nil {
+		return fmt.Errorf("active JWT key %q has no private key", activeID)
+	}
+	set.active = active
+
+	keysMutex.Lock()
+	currentSet = set
+	keysMutex.Unlock()
+
+	return nil
+}
+
+// ActiveKeyID 현재 서명에 사용하는 키 ID 반환 (설정 전이면 빈 문자열)
+func ActiveKeyID() string {
+	set := getKeySet()
+	if set == nil {
+		return ""
+	}
+	return set.active.id
+}
+
+// NewRandomKey 임의의 HS256 비밀키 생성
+// 프로세스가 끝나면 사라지므로 로컬 개발과 테스트에서만 사용한다 (재시작하면 기존 토큰이 무효가 되고 인스턴스 간에 공유되지 않음).
+func NewRandomKey(id string) (KeyConfig, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return KeyConfig{}, fmt.Errorf("failed to generate JWT secret: %w", err)
+	}
+	return KeyConfig{ID: id, Algorithm: AlgorithmHS256, Secret: hex.EncodeToString(secret)}, nil
+}
+
+// getKeySet 현재 키 모음 반환
+func getKeySet() *keySet {
+	keysMutex.RLock()
+	defer keysMutex.RUnlock()
+	return currentSet
+}
+
+// parseKey 키 설정을 서명/검증 키로 변환
+func parseKey(cfg KeyConfig) (*signingKey, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("key id is required")
+	}
+
+	key := &signingKey{id: cfg.ID}
+
+	switch cfg.Algorithm {
+	case "", AlgorithmHS256:
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(cfg.Secret)
+		key.verifyKey = []byte(cfg.Secret)
+
+	case AlgorithmRS256:
+		key.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyPEM != "" {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(cfg.PrivateKeyPEM))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		}
+		if cfg.PublicKeyPEM != "" {
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.PublicKeyPEM))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse RSA public key: %w", err)
+			}
+			key.verifyKey = publicKey
+		}
+
+	case AlgorithmEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyPEM != "" {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM([]byte(cfg.PrivateKeyPEM))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+			}
+			signer, ok := privateKey.(crypto.Signer)
+			if !ok {
+				return nil, errors.New("Ed25519 private key cannot sign")
+			}
+			key.signKey = privateKey
+			key.verifyKey = signer.Public()
+		}
+		if cfg.PublicKeyPEM != "" {
+			publicKey, err := jwt.ParseEdPublicKeyFromPEM([]byte(cfg.PublicKeyPEM))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ed25519 public key: %w", err)
+			}
+			key.verifyKey = publicKey
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if key.verifyKey == nil {
+		return nil, errors.New("private or public key is required")
+	}
+
+	return key, nil
+}
+
+// isAsymmetric 공개키로 검증 가능한 키인지 확인 (JWKS 노출 대상)
+func (k *signingKey) isAsymmetric() bool {
+	switch k.verifyKey.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,391 @@
+package compat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"sermo-be/pkg/llm"
+)
+
+// defaultTimeout 스트리밍이 아닌 요청의 기본 제한 시간 (ctx에 더 짧은 기한이 있으면 그 기한을 따름)
+const defaultTimeout = 120 * time.Second
+
+// Client OpenAI 호환 API(/chat/completions, /embeddings)를 제공하는 서버용 클라이언트
+// vLLM, Ollama, LM Studio 등 자체 호스팅 모델에 사용한다.
+type Client struct {
+	httpClient          *http.Client
+	baseURL             string
+	apiKey              string
+	model               string
+	maxCompletionTokens int
+	embeddingModel      string
+}
+
+// Config OpenAI 호환 클라이언트 설정
+type Config struct {
+	BaseURL             string // 예: http://localhost:8000/v1
+	APIKey              string // 서버가 인증을 요구하지 않으면 비워둠
+	Model               string
+	MaxCompletionTokens int
+	EmbeddingModel      string       // 비어 있으면 Embed 사용 불가
+	HTTPClient          *http.Client // 비어 있으면 http.DefaultClient
+}
+
+var _ llm.LLM = (*Client)(nil)
+
+// NewClient 새로운 OpenAI 호환 클라이언트 생성
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+
+	if cfg.MaxCompletionTokens == 0 {
+		cfg.MaxCompletionTokens = 2048
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		httpClient:          httpClient,
+		baseURL:             strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:              cfg.APIKey,
+		model:               cfg.Model,
+		maxCompletionTokens: cfg.MaxCompletionTokens,
+		embeddingModel:      cfg.EmbeddingModel,
+	}, nil
+}
+
+// chatCompletionRequest /chat/completions 요청 본문
+// 호환 서버 대부분이 max_completion_tokens 대신 max_tokens를 지원하므로 max_tokens를 사용한다.
+type chatCompletionRequest struct {
+	Model          string            `json:"model"`
+	Messages       []llm.ChatMessage `json:"messages"`
+	MaxTokens      int               `json:"max_tokens,omitempty"`
+	Temperature    *float32          `json:"temperature,omitempty"`
+	Stop           []string          `json:"stop,omitempty"`
+	ResponseFormat *responseFormat   `json:"response_format,omitempty"`
+	Stream         bool              `json:"stream,omitempty"`
+	StreamOptions  *streamOptions    `json:"stream_options,omitempty"`
+}
+
+// responseFormat OpenAI 형식의 response_format (json_object 또는 json_schema)
+type responseFormat struct {
+	Type       string              `json:"type"`
+	JSONSchema *llm.ResponseFormat `json:"json_schema,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatCompletionResponse /chat/completions 응답 (스트리밍 조각도 같은 형식이며 message 대신 delta가 채워짐)
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message      llm.ChatMessage `json:"message"`
+		Delta        llm.ChatMessage `json:"delta"`
+		FinishReason string          `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *llm.Usage `json:"usage"`
+	Model string     `json:"model"`
+}
+
+// ChatCompletion 채팅 완성 API 호출
+func (c *Client) ChatCompletion(ctx context.Context, messages []llm.ChatMessage) (*llm.ChatResponse, error) {
+	return c.ChatCompletionWithOptions(ctx, llm.ChatRequest{Messages: messages})
+}
+
+// ChatCompletionWithOptions 옵션을 지정한 채팅 완성 API 호출
+func (c *Client) ChatCompletionWithOptions(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+	}
+
+	request := c.chatRequest(req, false)
+	var resp chatCompletionResponse
+	if err := c.postJSON(ctx, "/chat/completions", request, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create chat completion: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from model server")
+	}
+
+	choice := resp.Choices[0]
+	response := &llm.ChatResponse{
+		Message:      choice.Message,
+		FinishReason: choice.FinishReason,
+		Model:        resp.Model,
+	}
+	if resp.Usage != nil {
+		response.Usage = *resp.Usage
+	}
+	if response.Model == "" {
+		response.Model = request.Model
+	}
+	return response, nil
+}
+
+// ChatCompletionStream 스트리밍 채팅 완성 API 호출 (SSE 응답의 data: 줄을 [DONE]까지 읽음)
+// onDelta가 에러를 반환하면 스트림을 중단하고 그 에러를 반환한다.
+func (c *Client) ChatCompletionStream(ctx context.Context, messages []llm.ChatMessage, onDelta func(delta string) error) (*llm.ChatResponse, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
+	}
+
+	request := c.chatRequest(llm.ChatRequest{Messages: messages}, true)
+	body, err := c.post(ctx, "/chat/completions", request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat completion stream: %w", err)
+	}
+	defer body.Close()
+
+	var content strings.Builder
+	response := &llm.ChatResponse{Message: llm.ChatMessage{Role: "assistant"}, Model: request.Model}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatCompletionResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to parse chat completion stream: %w", err)
+		}
+
+		// 마지막 조각에만 토큰 사용량이 포함됨 (지원하지 않는 서버는 보내지 않음)
+		if chunk.Usage != nil {
+			response.Usage = *chunk.Usage
+		}
+		if chunk.Model != "" {
+			response.Model = chunk.Model
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			response.FinishReason = choice.FinishReason
+		}
+
+		if delta := choice.Delta.Content; delta != "" {
+			content.WriteString(delta)
+			if onDelta != nil {
+				if err := onDelta(delta); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to receive chat completion stream: %w", err)
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("no response from model server")
+	}
+
+	response.Message.Content = content.String()
+	return response, nil
+}
+
+// embeddingRequest /embeddings 요청 본문
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse /embeddings 응답
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Model string    `json:"model"`
+	Usage llm.Usage `json:"usage"` // 사용량을 보내지 않는 서버는 0
+}
+
+// Embed 임베딩 API 호출 (inputs와 같은 순서로 벡터 반환)
+func (c *Client) Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("inputs are required")
+	}
+	if c.embeddingModel == "" {
+		return nil, fmt.Errorf("embedding model is not configured")
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+	}
+
+	var resp embeddingResponse
+	if err := c.postJSON(ctx, "/embeddings", embeddingRequest{Model: c.embeddingModel, Input: inputs}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+
+	if len(resp.Data) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(resp.Data))
+	}
+
+	vectors := make([][]float32, len(inputs))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(inputs) {
+			return nil, fmt.Errorf("unexpected embedding index %d", data.Index)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = c.embeddingModel
+	}
+	return &llm.EmbeddingResponse{Vectors: vectors, Usage: resp.Usage, Model: model}, nil
+}
+
+// GetModel 현재 설정된 모델 반환
+func (c *Client) GetModel() string {
+	return c.model
+}
+
+// GetEmbeddingModel 현재 설정된 임베딩 모델 반환
+func (c *Client) GetEmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// chatRequest 기본값을 채운 요청 본문 생성
+func (c *Client) chatRequest(req llm.ChatRequest, stream bool) chatCompletionRequest {
+	body := chatCompletionRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		MaxTokens:   req.MaxCompletionTokens,
+		Temperature: req.Temperature,
+		Stop:        req.Stop,
+		Stream:      stream,
+	}
+	if body.Model == "" {
+		body.Model = c.model
+	}
+	if body.MaxTokens == 0 {
+		body.MaxTokens = c.maxCompletionTokens
+	}
+	if format := req.ResponseFormat; format != nil {
+		if len(format.Schema) == 0 {
+			body.ResponseFormat = &responseFormat{Type: "json_object"}
+		} else {
+			body.ResponseFormat = &responseFormat{Type: "json_schema", JSONSchema: format}
+		}
+	}
+	if stream {
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+	return body
+}
+
+// postJSON JSON 요청 후 응답 본문을 out에 디코딩
+func (c *Client) postJSON(ctx context.Context, path string, payload, out any) error {
+	body, err := c.post(ctx, path, payload)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// post JSON 요청 후 성공 응답 본문 반환 (호출한 쪽에서 닫아야 함)
+func (c *Client) post(ctx context.Context, path string, payload any) (io.ReadCloser, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// APIError 모델 서버가 반환한 에러 응답
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("model server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// newAPIError 에러 응답 본문에서 메시지 추출 ({"error":{"message":...}} 또는 {"error":"..."} 형식, 그 외에는 본문 그대로)
+func newAPIError(resp *http.Response) *APIError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && len(body.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		var text string
+		switch {
+		case json.Unmarshal(body.Error, &detail) == nil && detail.Message != "":
+			message = detail.Message
+		case json.Unmarshal(body.Error, &text) == nil && text != "":
+			message = text
+		}
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	return &APIError{StatusCode: resp.StatusCode, Message: message}
+}
@@ -0,0 +1,208 @@
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sermo-be/pkg/llm"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(&Config{
+		BaseURL:        server.URL + "/v1/",
+		APIKey:         "secret",
+		Model:          "local-model",
+		EmbeddingModel: "local-embed",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestChatCompletionWithOptions(t *testing.T) {
+	var got chatCompletionRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"안녕"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`)
+	})
+
+	resp, err := client.ChatCompletionWithOptions(context.Background(), llm.ChatRequest{
+		Messages:            []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		Model:               "small-model",
+		MaxCompletionTokens: 64,
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionWithOptions: %v", err)
+	}
+
+	if got.Model != "small-model" || got.MaxTokens != 64 || got.Stream {
+		t.Errorf("request = %+v", got)
+	}
+	if resp.Message.Content != "안녕" || resp.FinishReason != "stop" || resp.Usage.TotalTokens != 4 || resp.Model != "small-model" {
+		t.Errorf("response = %+v", resp)
+	}
+
+	// 옵션이 없으면 설정된 기본값 사용
+	if _, err := client.ChatCompletion(context.Background(), []llm.ChatMessage{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+	if got.Model != "local-model" || got.MaxTokens != 2048 {
+		t.Errorf("default request = %+v", got)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("stream request = %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"안녕\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"하세요\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":2,\"completion_tokens\":2,\"total_tokens\":4}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	var deltas []string
+	resp, err := client.ChatCompletionStream(context.Background(), []llm.ChatMessage{{Role: "user", Content: "hi"}}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream: %v", err)
+	}
+
+	if strings.Join(deltas, "|") != "안녕|하세요" {
+		t.Errorf("deltas = %v", deltas)
+	}
+	if resp.Message.Content != "안녕하세요" || resp.FinishReason != "stop" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	// onDelta 에러는 그대로 반환
+	stop := errors.New("stop")
+	if _, err := client.ChatCompletionStream(context.Background(), []llm.ChatMessage{{Role: "user", Content: "hi"}}, func(string) error {
+		return stop
+	}); !errors.Is(err, stop) {
+		t.Errorf("err = %v, want %v", err, stop)
+	}
+}
+
+func TestChatCompletionResponseFormat(t *testing.T) {
+	var got map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{}"}}]}`)
+	})
+
+	_, err := client.ChatCompletionWithOptions(context.Background(), llm.ChatRequest{
+		Messages:       []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		Temperature:    llm.Temperature(0),
+		Stop:           []string{"END"},
+		ResponseFormat: llm.JSONSchema("status", `{"type":"object"}`),
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionWithOptions: %v", err)
+	}
+
+	// temperature 0도 생략하지 않고 전달
+	if temperature, ok := got["temperature"].(float64); !ok || temperature != 0 {
+		t.Errorf("temperature = %v", got["temperature"])
+	}
+	if stop, _ := got["stop"].([]any); len(stop) != 1 || stop[0] != "END" {
+		t.Errorf("stop = %v", got["stop"])
+	}
+
+	format, _ := got["response_format"].(map[string]any)
+	schema, _ := format["json_schema"].(map[string]any)
+	if format["type"] != "json_schema" || schema["name"] != "status" || schema["strict"] != true {
+		t.Errorf("response_format = %v", got["response_format"])
+	}
+	if inner, _ := schema["schema"].(map[string]any); inner["type"] != "object" {
+		t.Errorf("schema = %v", schema["schema"])
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req embeddingRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/v1/embeddings" || req.Model != "local-embed" || len(req.Input) != 2 {
+			t.Errorf("embedding request %s = %+v", r.URL.Path, req)
+		}
+		// 순서가 바뀐 응답도 index대로 정렬
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":2,"total_tokens":2}}`)
+	})
+
+	resp, err := client.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	vectors := resp.Vectors
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("vectors = %v", vectors)
+	}
+	// 응답에 모델이 없으면 요청한 임베딩 모델
+	if resp.Usage.PromptTokens != 2 || resp.Usage.TotalTokens != 2 || resp.Model != "local-embed" {
+		t.Errorf("usage = %+v, model = %q", resp.Usage, resp.Model)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"error":{"message":"model not found"}}`, "model not found"},
+		{`{"error":"overloaded"}`, "overloaded"},
+		{`upstream failed`, "upstream failed"},
+		{``, "Service Unavailable"},
+	}
+
+	for _, tt := range tests {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, tt.body)
+		})
+
+		_, err := client.ChatCompletion(context.Background(), []llm.ChatMessage{{Role: "user", Content: "hi"}})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != tt.want {
+			t.Errorf("body %q: error = %+v, want message %q", tt.body, apiErr, tt.want)
+		}
+	}
+}
+
+func TestEmbedWithoutModel(t *testing.T) {
+	client, err := NewClient(&Config{BaseURL: "http://localhost", Model: "m"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.Embed(context.Background(), []string{"a"}); err == nil {
+		t.Error("expected error without embedding model")
+	}
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CompleteJSON JSON 응답 형식을 지정해 채팅 완성을 요청하고 응답을 out에 디코딩
+// 형식 지정을 지원하지 않는 모델이 설명이나 코드 블록을 붙여 응답해도 디코딩한다.
+func CompleteJSON(ctx context.Context, model LLM, req ChatRequest, out any) (*ChatResponse, error) {
+	if req.ResponseFormat == nil {
+		req.ResponseFormat = &ResponseFormat{Name: "response"}
+	}
+
+	response, err := model.ChatCompletionWithOptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := DecodeJSON(response.Message.Content, out); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// DecodeJSON 모델 응답에서 JSON 객체를 찾아 out에 디코딩
+// 앞뒤 설명이나 ``` 코드 블록이 붙은 경우 첫 {부터 마지막 }까지 사용한다.
+func DecodeJSON(content string, out any) error {
+	text := strings.TrimSpace(content)
+	if start, end := strings.IndexByte(text, '{'), strings.LastIndexByte(text, '}'); start >= 0 && end > start {
+		text = text[start : end+1]
+	}
+
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+	return nil
+}
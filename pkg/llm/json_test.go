@@ -0,0 +1,70 @@
+package llm_test
+
+import (
+	"context"
+	"testing"
+
+	"sermo-be/pkg/llm"
+	"sermo-be/pkg/llm/llmtest"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []string{
+		`{"keywords":["a","b"]}`,
+		"```json\n{\"keywords\":[\"a\",\"b\"]}\n```",
+		"Here you go:\n{\"keywords\": [\"a\", \"b\"]}\nHope this helps!",
+	}
+
+	for _, content := range tests {
+		var out struct {
+			Keywords []string `json:"keywords"`
+		}
+		if err := llm.DecodeJSON(content, &out); err != nil {
+			t.Errorf("DecodeJSON(%q): %v", content, err)
+			continue
+		}
+		if len(out.Keywords) != 2 || out.Keywords[0] != "a" {
+			t.Errorf("DecodeJSON(%q) = %v", content, out.Keywords)
+		}
+	}
+
+	var out map[string]any
+	if err := llm.DecodeJSON("Keywords: a, b", &out); err == nil {
+		t.Error("expected error for non-JSON response")
+	}
+}
+
+func TestCompleteJSON(t *testing.T) {
+	fake := llmtest.New(`{"message":"hi","send_time":"2025-01-25 00:00:00"}`)
+	format := llm.JSONSchema("alarm", `{"type":"object"}`)
+
+	var out struct {
+		Message  string `json:"message"`
+		SendTime string `json:"send_time"`
+	}
+	_, err := llm.CompleteJSON(context.Background(), fake, llm.ChatRequest{
+		Messages:       []llm.ChatMessage{{Role: "user", Content: "alarm"}},
+		ResponseFormat: format,
+		Temperature:    llm.Temperature(0),
+	}, &out)
+	if err != nil {
+		t.Fatalf("CompleteJSON: %v", err)
+	}
+	if out.Message != "hi" || out.SendTime != "2025-01-25 00:00:00" {
+		t.Errorf("out = %+v", out)
+	}
+
+	req := fake.LastRequest()
+	if req.ResponseFormat != format || req.Temperature == nil || *req.Temperature != 0 {
+		t.Errorf("request options not passed through: %+v", req)
+	}
+
+	// 형식을 지정하지 않으면 JSON 객체 형식 요청
+	fake.Responses = []string{`{}`}
+	if _, err := llm.CompleteJSON(context.Background(), fake, llm.ChatRequest{Messages: []llm.ChatMessage{{Role: "user", Content: "x"}}}, &out); err != nil {
+		t.Fatalf("CompleteJSON: %v", err)
+	}
+	if format := fake.LastRequest().ResponseFormat; format == nil || len(format.Schema) != 0 {
+		t.Errorf("default response format = %+v", format)
+	}
+}
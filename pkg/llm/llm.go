@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ChatMessage 채팅 메시지 구조
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest 채팅 요청 구조 (비어 있는 옵션은 제공자의 기본값 사용)
+type ChatRequest struct {
+	Messages            []ChatMessage   `json:"messages"`
+	Model               string          `json:"model,omitempty"`
+	MaxCompletionTokens int             `json:"max_completion_tokens,omitempty"`
+	Temperature         *float32        `json:"temperature,omitempty"` // nil이면 모델 기본값, 0도 지정 가능 (openai 제공자는 SDK가 0을 생략하므로 0에 가장 가까운 양수로 전달)
+	Stop                []string        `json:"stop,omitempty"`
+	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat 응답 형식 (JSON Schema를 지정하면 스키마에 맞는 JSON만 생성하도록 요청)
+type ResponseFormat struct {
+	Name   string          `json:"name"`             // 스키마 이름 (영문, 숫자, _, -)
+	Schema json.RawMessage `json:"schema,omitempty"` // 비어 있으면 형식 없는 JSON 객체
+	Strict bool            `json:"strict"`           // 스키마를 엄격하게 따르도록 요청 (모든 필드 required, additionalProperties false 필요)
+}
+
+// JSONSchema 엄격한 JSON Schema 응답 형식 생성
+func JSONSchema(name, schema string) *ResponseFormat {
+	return &ResponseFormat{Name: name, Schema: json.RawMessage(schema), Strict: true}
+}
+
+// Temperature 옵션용 포인터 반환
+func Temperature(value float32) *float32 {
+	return &value
+}
+
+// ChatResponse 채팅 응답 구조
+type ChatResponse struct {
+	Message      ChatMessage `json:"message"`
+	Usage        Usage       `json:"usage"`
+	FinishReason string      `json:"finish_reason"`
+	Model        string      `json:"model"` // 실제로 응답한 모델 (대체 모델을 사용하면 요청한 모델과 다를 수 있음)
+}
+
+// EmbeddingResponse 임베딩 응답 구조
+type EmbeddingResponse struct {
+	Vectors [][]float32 `json:"vectors"` // 입력과 같은 순서
+	Usage   Usage       `json:"usage"`   // 임베딩은 프롬프트 토큰만 사용
+	Model   string      `json:"model"`   // 실제로 응답한 임베딩 모델
+}
+
+// Usage 토큰 사용량 정보
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// LLM 채팅 완성과 임베딩을 제공하는 언어 모델
+// OpenAI(pkg/openai), OpenAI 호환 서버(pkg/llm/compat), 테스트용 가짜 모델(pkg/llm/llmtest)이 구현한다.
+type LLM interface {
+	// ChatCompletion 기본 모델로 채팅 완성
+	ChatCompletion(ctx context.Context, messages []ChatMessage) (*ChatResponse, error)
+	// ChatCompletionStream 응답 조각이 도착할 때마다 onDelta를 호출하고 전체 응답을 반환
+	ChatCompletionStream(ctx context.Context, messages []ChatMessage, onDelta func(delta string) error) (*ChatResponse, error)
+	// ChatCompletionWithOptions 요청별 옵션을 지정한 채팅 완성
+	ChatCompletionWithOptions(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// Embed inputs와 같은 순서로 임베딩 벡터와 토큰 사용량 반환
+	Embed(ctx context.Context, inputs []string) (*EmbeddingResponse, error)
+	// GetModel 기본 채팅 모델 이름
+	GetModel() string
+}
@@ -0,0 +1,244 @@
+// Package llmtest 테스트용 결정적 언어 모델
+package llmtest
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"sermo-be/pkg/llm"
+)
+
+// DefaultDimensions 가짜 임베딩 기본 차원
+const DefaultDimensions = 64
+
+// Fake 네트워크 없이 같은 입력에 항상 같은 결과를 돌려주는 llm.LLM 구현
+// 응답은 Respond → Responses(순서대로 소비) → 마지막 사용자 메시지 되풀이 순으로 정해지고,
+// 받은 요청은 Requests에 기록된다.
+type Fake struct {
+	// Respond 요청별 응답을 직접 정할 때 사용 (에러를 반환하면 호출도 그 에러로 실패)
+	Respond func(req llm.ChatRequest) (string, error)
+	// Responses 순서대로 돌려줄 응답
+	Responses []string
+	// Err 설정되면 모든 채팅/임베딩 호출이 이 에러로 실패
+	Err error
+	// Dimensions 임베딩 차원 (0이면 DefaultDimensions)
+	Dimensions int
+	// Model GetModel이 반환할 모델 이름 (비어 있으면 "fake")
+	Model string
+
+	mu       sync.Mutex
+	requests []llm.ChatRequest
+	embeds   [][]string
+}
+
+var _ llm.LLM = (*Fake)(nil)
+
+// New 응답을 순서대로 돌려주는 Fake 생성
+func New(responses ...string) *Fake {
+	return &Fake{Responses: responses}
+}
+
+// ChatCompletion 채팅 완성
+func (f *Fake) ChatCompletion(ctx context.Context, messages []llm.ChatMessage) (*llm.ChatResponse, error) {
+	return f.ChatCompletionWithOptions(ctx, llm.ChatRequest{Messages: messages})
+}
+
+// ChatCompletionWithOptions 옵션을 지정한 채팅 완성
+func (f *Fake) ChatCompletionWithOptions(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
+	}
+
+	content, err := f.reply(req)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := 0
+	for _, message := range req.Messages {
+		prompt += countTokens(message.Content)
+	}
+	completion := countTokens(content)
+
+	model := req.Model
+	if model == "" {
+		model = f.GetModel()
+	}
+
+	return &llm.ChatResponse{
+		Message:      llm.ChatMessage{Role: "assistant", Content: content},
+		Usage:        llm.Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion},
+		FinishReason: "stop",
+		Model:        model,
+	}, nil
+}
+
+// ChatCompletionStream 응답을 공백 단위 조각으로 나눠 onDelta 호출
+func (f *Fake) ChatCompletionStream(ctx context.Context, messages []llm.ChatMessage, onDelta func(delta string) error) (*llm.ChatResponse, error) {
+	response, err := f.ChatCompletion(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	if onDelta != nil {
+		for _, delta := range splitDeltas(response.Message.Content) {
+			if err := onDelta(delta); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return response, nil
+}
+
+// Embed 단어 해시 기반 임베딩 (같은 단어를 공유하는 텍스트일수록 코사인 유사도가 높음)
+func (f *Fake) Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("inputs are required")
+	}
+
+	f.mu.Lock()
+	f.embeds = append(f.embeds, append([]string(nil), inputs...))
+	err := f.Err
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := f.Dimensions
+	if dimensions <= 0 {
+		dimensions = DefaultDimensions
+	}
+
+	vectors := make([][]float32, len(inputs))
+	tokens := 0
+	for i, input := range inputs {
+		vectors[i] = embed(input, dimensions)
+		tokens += countTokens(input)
+	}
+	return &llm.EmbeddingResponse{
+		Vectors: vectors,
+		Usage:   llm.Usage{PromptTokens: tokens, TotalTokens: tokens},
+		Model:   f.GetModel(),
+	}, nil
+}
+
+// GetModel 모델 이름
+func (f *Fake) GetModel() string {
+	if f.Model == "" {
+		return "fake"
+	}
+	return f.Model
+}
+
+// Requests 지금까지 받은 채팅 요청
+func (f *Fake) Requests() []llm.ChatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]llm.ChatRequest(nil), f.requests...)
+}
+
+// LastRequest 마지막 채팅 요청 (없으면 빈 요청)
+func (f *Fake) LastRequest() llm.ChatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.requests) == 0 {
+		return llm.ChatRequest{}
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+// Embeds 지금까지 받은 임베딩 요청
+func (f *Fake) Embeds() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.embeds...)
+}
+
+// reply 요청을 기록하고 응답 결정
+func (f *Fake) reply(req llm.ChatRequest) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if f.Respond != nil {
+		return f.Respond(req)
+	}
+	if len(f.Responses) > 0 {
+		content := f.Responses[0]
+		f.Responses = f.Responses[1:]
+		return content, nil
+	}
+
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			return "echo: " + req.Messages[i].Content, nil
+		}
+	}
+	return "echo: " + req.Messages[len(req.Messages)-1].Content, nil
+}
+
+// embed 소문자 단어를 해시해 차원에 누적한 뒤 정규화
+func embed(text string, dimensions int) []float32 {
+	vector := make([]float32, dimensions)
+	for _, word := range words(text) {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%uint32(dimensions)]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
+
+// words 문자/숫자가 아닌 문자로 나눈 소문자 단어
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// countTokens 공백 단위 토큰 수
+func countTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+// splitDeltas 다음 단어 앞까지의 공백을 포함해 단어별로 나눔 (이어 붙이면 원문과 같음)
+func splitDeltas(text string) []string {
+	var deltas []string
+	start := 0
+	for i := 1; i < len(text); i++ {
+		if text[i-1] == ' ' && text[i] != ' ' {
+			deltas = append(deltas, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		deltas = append(deltas, text[start:])
+	}
+	return deltas
+}
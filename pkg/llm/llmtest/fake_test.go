@@ -0,0 +1,74 @@
+package llmtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"sermo-be/pkg/llm"
+)
+
+func TestFakeResponses(t *testing.T) {
+	fake := New("first", "second")
+	ctx := context.Background()
+	messages := []llm.ChatMessage{{Role: "system", Content: "sys"}, {Role: "user", Content: "hello"}}
+
+	for _, want := range []string{"first", "second", "echo: hello"} {
+		resp, err := fake.ChatCompletion(ctx, messages)
+		if err != nil {
+			t.Fatalf("ChatCompletion: %v", err)
+		}
+		if resp.Message.Content != want {
+			t.Errorf("content = %q, want %q", resp.Message.Content, want)
+		}
+	}
+	if len(fake.Requests()) != 3 {
+		t.Errorf("requests = %d, want 3", len(fake.Requests()))
+	}
+}
+
+func TestFakeStream(t *testing.T) {
+	fake := New("안녕 하세요  반가워요")
+
+	var deltas []string
+	resp, err := fake.ChatCompletionStream(context.Background(), []llm.ChatMessage{{Role: "user", Content: "hi"}}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream: %v", err)
+	}
+	if len(deltas) != 3 || strings.Join(deltas, "") != resp.Message.Content {
+		t.Errorf("deltas = %q, content = %q", deltas, resp.Message.Content)
+	}
+}
+
+func TestFakeEmbed(t *testing.T) {
+	fake := &Fake{}
+	resp, err := fake.Embed(context.Background(), []string{"강아지 산책", "강아지 산책!", "회사 야근"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	vectors := resp.Vectors
+	if resp.Usage.PromptTokens == 0 || resp.Usage.TotalTokens != resp.Usage.PromptTokens {
+		t.Errorf("usage = %+v, want prompt tokens only", resp.Usage)
+	}
+
+	dot := func(a, b []float32) float32 {
+		var sum float32
+		for i := range a {
+			sum += a[i] * b[i]
+		}
+		return sum
+	}
+
+	if len(vectors[0]) != DefaultDimensions {
+		t.Fatalf("dimensions = %d", len(vectors[0]))
+	}
+	if same := dot(vectors[0], vectors[1]); same < 0.999 {
+		t.Errorf("same words similarity = %f, want 1", same)
+	}
+	if other := dot(vectors[0], vectors[2]); other > 0.5 {
+		t.Errorf("different words similarity = %f", other)
+	}
+}
@@ -0,0 +1,57 @@
+package llm
+
+// Feature 언어 모델을 사용하는 기능 (기능별로 다른 모델을 지정할 수 있음)
+type Feature string
+
+const (
+	FeatureChat        Feature = "chat"         // 봇 응답 생성 및 검증
+	FeatureStatus      Feature = "status"       // 사용자 메시지에서 상태 정보 추출
+	FeatureSummary     Feature = "summary"      // 캐릭터/대화 요약
+	FeatureAlarm       Feature = "alarm"        // 채팅 종료 후 알람 메시지 생성
+	FeatureBookmark    Feature = "bookmark"     // 북마크한 단어/문장 뜻 생성
+	FeatureImagePrompt Feature = "image_prompt" // 프로필 이미지 프롬프트의 외형 특징 추출
+	FeatureEmbedding   Feature = "embedding"    // 지난 대화 검색용 임베딩
+)
+
+// Features 모든 기능 목록
+var Features = []Feature{
+	FeatureChat,
+	FeatureStatus,
+	FeatureSummary,
+	FeatureAlarm,
+	FeatureBookmark,
+	FeatureImagePrompt,
+	FeatureEmbedding,
+}
+
+// Router 기능별 언어 모델 선택
+type Router struct {
+	routes map[Feature]LLM
+}
+
+// NewRouter 빈 Router 생성
+func NewRouter() *Router {
+	return &Router{routes: make(map[Feature]LLM)}
+}
+
+// Route 기능에 언어 모델 지정
+func (r *Router) Route(feature Feature, model LLM) *Router {
+	r.routes[feature] = model
+	return r
+}
+
+// RouteAll 모든 기능에 같은 언어 모델 지정
+func (r *Router) RouteAll(model LLM) *Router {
+	for _, feature := range Features {
+		r.routes[feature] = model
+	}
+	return r
+}
+
+// For 기능에 지정된 언어 모델 (지정되지 않았거나 Router가 nil이면 nil)
+func (r *Router) For(feature Feature) LLM {
+	if r == nil {
+		return nil
+	}
+	return r.routes[feature]
+}
@@ -2,16 +2,46 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"sermo-be/pkg/llm"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
-// Client OpenAI API 클라이언트
+const (
+	// DefaultEmbeddingModel 기본 임베딩 모델
+	DefaultEmbeddingModel = "text-embedding-3-small"
+	// EmbeddingDimensions 임베딩 벡터 차원 (DB의 vector 컬럼 차원과 같아야 함)
+	EmbeddingDimensions = 1536
+)
+
+// Client OpenAI API 클라이언트 (llm.LLM 구현)
+// 모든 호출은 시도별 제한 시간 안에서 일시적 장애(429, 5xx, 네트워크 오류)일 때 재시도하고,
+// 모델별 회로 차단기와 대체 모델 목록을 거친다.
 type Client struct {
 	client              *openai.Client
 	model               string
 	maxCompletionTokens int
+	embeddingModel      string
+
+	fallbackModels   []string
+	maxAttempts      int
+	callTimeout      time.Duration
+	retryBaseDelay   time.Duration
+	retryMaxDelay    time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	breakersMu sync.Mutex
+	breakers   map[string]*circuitBreaker
 }
 
 // Config OpenAI 클라이언트 설정
@@ -19,34 +49,26 @@ type Config struct {
 	APIKey              string
 	Model               string
 	MaxCompletionTokens int
-}
-
-// ChatMessage 채팅 메시지 구조
-type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// ChatRequest 채팅 요청 구조
-type ChatRequest struct {
-	Messages            []ChatMessage `json:"messages"`
-	Model               string        `json:"model,omitempty"`
-	MaxCompletionTokens int           `json:"max_completion_tokens,omitempty"`
-}
+	EmbeddingModel      string
+	// BaseURL API 주소 (비어 있으면 OpenAI 기본 주소, 프록시나 테스트 서버용)
+	BaseURL string
 
-// ChatResponse 채팅 응답 구조
-type ChatResponse struct {
-	Message      ChatMessage `json:"message"`
-	Usage        Usage       `json:"usage"`
-	FinishReason string      `json:"finish_reason"`
+	// FallbackModels 채팅 모델이 계속 실패할 때 순서대로 시도할 대체 모델
+	FallbackModels []string
+	// MaxAttempts 모델별 최대 시도 횟수 (0이면 DefaultMaxAttempts)
+	MaxAttempts int
+	// CallTimeout 시도 한 번의 제한 시간 (0이면 DefaultCallTimeout)
+	CallTimeout time.Duration
+	// RetryBaseDelay, RetryMaxDelay 재시도 대기 시간의 시작값과 최대값 (Retry-After가 최대값보다 길면 대체 모델로 넘어감)
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerThreshold 회로를 여는 연속 실패 횟수 (0이면 DefaultBreakerThreshold, 음수면 회로 차단 안 함)
+	BreakerThreshold int
+	// BreakerCooldown 회로가 열린 뒤 시험 호출까지 기다리는 시간 (0이면 DefaultBreakerCooldown)
+	BreakerCooldown time.Duration
 }
 
-// Usage 토큰 사용량 정보
-type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
-}
+var _ llm.LLM = (*Client)(nil)
 
 // NewClient 새로운 OpenAI 클라이언트 생성
 func NewClient(cfg *Config) (*Client, error) {
@@ -62,40 +84,78 @@ func NewClient(cfg *Config) (*Client, error) {
 		cfg.MaxCompletionTokens = 2048
 	}
 
-	client := openai.NewClient(cfg.APIKey)
+	if cfg.EmbeddingModel == "" {
+		cfg.EmbeddingModel = DefaultEmbeddingModel
+	}
+
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = defaultRetryBaseDelay
+	}
+
+	if cfg.RetryMaxDelay <= 0 {
+		cfg.RetryMaxDelay = defaultRetryMaxDelay
+	}
+
+	if cfg.BreakerThreshold == 0 {
+		cfg.BreakerThreshold = DefaultBreakerThreshold
+	}
+
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = DefaultBreakerCooldown
+	}
+
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	if cfg.BaseURL != "" {
+		clientConfig.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	}
+	// 실패 응답의 Retry-After 헤더를 재시도 대기 시간에 반영하기 위해 HTTP 계층에서 기록
+	clientConfig.HTTPClient = &retryAfterDoer{doer: &http.Client{}}
 
 	return &Client{
-		client:              client,
+		client:              openai.NewClientWithConfig(clientConfig),
 		model:               cfg.Model,
 		maxCompletionTokens: cfg.MaxCompletionTokens,
+		embeddingModel:      cfg.EmbeddingModel,
+
+		fallbackModels:   cfg.FallbackModels,
+		maxAttempts:      cfg.MaxAttempts,
+		callTimeout:      cfg.CallTimeout,
+		retryBaseDelay:   cfg.RetryBaseDelay,
+		retryMaxDelay:    cfg.RetryMaxDelay,
+		breakerThreshold: cfg.BreakerThreshold,
+		breakerCooldown:  cfg.BreakerCooldown,
+		breakers:         make(map[string]*circuitBreaker),
 	}, nil
 }
 
 // ChatCompletion 채팅 완성 API 호출
-func (c *Client) ChatCompletion(ctx context.Context, messages []ChatMessage) (*ChatResponse, error) {
-	if len(messages) == 0 {
-		return nil, fmt.Errorf("messages are required")
-	}
+func (c *Client) ChatCompletion(ctx context.Context, messages []llm.ChatMessage) (*llm.ChatResponse, error) {
+	return c.ChatCompletionWithOptions(ctx, llm.ChatRequest{Messages: messages})
+}
 
-	// OpenAI SDK 형식으로 메시지 변환
-	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
-	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
+// ChatCompletionWithOptions 옵션을 지정한 채팅 완성 API 호출 (지정하지 않은 모델과 최대 토큰 수는 클라이언트 설정 사용)
+func (c *Client) ChatCompletionWithOptions(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
 	}
 
-	// API 호출
-	resp, err := c.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model:               c.model,
-			Messages:            openaiMessages,
-			MaxCompletionTokens: c.maxCompletionTokens,
-		},
-	)
-
+	// API 호출 (실패하면 재시도 후 대체 모델 사용)
+	request := c.chatRequest(req)
+	var resp openai.ChatCompletionResponse
+	err := c.call(ctx, request.Model, true, func(ctx context.Context, model string) error {
+		request.Model = model
+		var err error
+		resp, err = c.client.CreateChatCompletion(ctx, request)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chat completion: %w", err)
 	}
@@ -105,35 +165,172 @@ func (c *Client) ChatCompletion(ctx context.Context, messages []ChatMessage) (*C
 	}
 
 	choice := resp.Choices[0]
-	message := ChatMessage{
+	message := llm.ChatMessage{
 		Role:    choice.Message.Role,
 		Content: choice.Message.Content,
 	}
 
-	usage := Usage{
+	usage := llm.Usage{
 		PromptTokens:     resp.Usage.PromptTokens,
 		CompletionTokens: resp.Usage.CompletionTokens,
 		TotalTokens:      resp.Usage.TotalTokens,
 	}
 
-	return &ChatResponse{
+	model := resp.Model
+	if model == "" {
+		model = request.Model
+	}
+
+	return &llm.ChatResponse{
 		Message:      message,
 		Usage:        usage,
 		FinishReason: string(choice.FinishReason),
+		Model:        model,
 	}, nil
 }
 
-// ChatCompletionWithOptions 옵션을 지정한 채팅 완성 API 호출
-func (c *Client) ChatCompletionWithOptions(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
-	// 기본값 설정
-	if req.Model == "" {
-		req.Model = c.model
+// ChatCompletionStream 스트리밍 채팅 완성 API 호출
+// 응답 조각이 도착할 때마다 onDelta를 호출하고, 스트림이 끝나면 전체 응답을 반환한다.
+// onDelta가 에러를 반환하면 스트림을 중단하고 그 에러를 반환한다.
+func (c *Client) ChatCompletionStream(ctx context.Context, messages []llm.ChatMessage, onDelta func(delta string) error) (*llm.ChatResponse, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
+	}
+
+	req := c.chatRequest(llm.ChatRequest{Messages: messages})
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	// 응답 조각을 전달하기 전에 실패한 경우에만 재시도하거나 대체 모델을 사용
+	var response *llm.ChatResponse
+	err := c.call(ctx, req.Model, true, func(ctx context.Context, model string) error {
+		req.Model = model
+		var emitted bool
+		var err error
+		response, err = c.stream(ctx, req, func(delta string) error {
+			emitted = true
+			if onDelta == nil {
+				return nil
+			}
+			return onDelta(delta)
+		})
+		if err != nil && emitted {
+			return &permanentError{err: err}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	if req.MaxCompletionTokens == 0 {
-		req.MaxCompletionTokens = c.maxCompletionTokens
+	return response, nil
+}
+
+// stream 스트리밍 요청 한 번 실행
+func (c *Client) stream(ctx context.Context, req openai.ChatCompletionRequest, onDelta func(delta string) error) (*llm.ChatResponse, error) {
+	stream, err := c.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat completion stream: %w", err)
 	}
+	defer stream.Close()
+
+	var content strings.Builder
+	response := &llm.ChatResponse{Message: llm.ChatMessage{Role: openai.ChatMessageRoleAssistant}, Model: req.Model}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive chat completion stream: %w", err)
+		}
 
-	return c.ChatCompletion(ctx, req.Messages)
+		// 마지막 조각에만 토큰 사용량이 포함됨
+		if chunk.Usage != nil {
+			response.Usage = llm.Usage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		if chunk.Model != "" {
+			response.Model = chunk.Model
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			response.FinishReason = string(choice.FinishReason)
+		}
+
+		if delta := choice.Delta.Content; delta != "" {
+			content.WriteString(delta)
+			if err := onDelta(delta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("no response from OpenAI")
+	}
+
+	response.Message.Content = content.String()
+	return response, nil
+}
+
+// Embed 임베딩 API 호출 (inputs와 같은 순서로 벡터 반환)
+func (c *Client) Embed(ctx context.Context, inputs []string) (*llm.EmbeddingResponse, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("inputs are required")
+	}
+
+	req := openai.EmbeddingRequestStrings{
+		Input: inputs,
+		Model: openai.EmbeddingModel(c.embeddingModel),
+	}
+	// text-embedding-3 계열은 차원을 줄여 받을 수 있으므로 DB 컬럼 차원에 맞춤
+	if strings.HasPrefix(c.embeddingModel, "text-embedding-3") {
+		req.Dimensions = EmbeddingDimensions
+	}
+
+	// 임베딩 차원이 DB 컬럼과 같아야 하므로 대체 모델 없이 재시도만 함
+	var resp openai.EmbeddingResponse
+	err := c.call(ctx, c.embeddingModel, false, func(ctx context.Context, _ string) error {
+		var err error
+		resp, err = c.client.CreateEmbeddings(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+
+	if len(resp.Data) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(resp.Data))
+	}
+
+	vectors := make([][]float32, len(inputs))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(inputs) {
+			return nil, fmt.Errorf("unexpected embedding index %d", data.Index)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+
+	model := string(resp.Model)
+	if model == "" {
+		model = c.embeddingModel
+	}
+	return &llm.EmbeddingResponse{
+		Vectors: vectors,
+		Usage: llm.Usage{
+			PromptTokens: resp.Usage.PromptTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		},
+		Model: model,
+	}, nil
 }
 
 // GetModel 현재 설정된 모델 반환
@@ -146,6 +343,11 @@ func (c *Client) GetMaxCompletionTokens() int {
 	return c.maxCompletionTokens
 }
 
+// GetEmbeddingModel 현재 설정된 임베딩 모델 반환
+func (c *Client) GetEmbeddingModel() string {
+	return c.embeddingModel
+}
+
 // SetModel 모델 변경
 func (c *Client) SetModel(model string) {
 	c.model = model
@@ -157,3 +359,60 @@ func (c *Client) SetMaxCompletionTokens(maxCompletionTokens int) {
 		c.maxCompletionTokens = maxCompletionTokens
 	}
 }
+
+// zeroTemperature Temperature 0 요청 시 SDK로 보내는 값
+// go-openai의 Temperature는 포인터가 아닌 omitempty float32라 0을 넣으면 필드가 빠지고 모델 기본값(1)이 적용된다.
+// 0에 가장 가까운 양수를 대신 보내 사실상 0과 같은 결정적 샘플링을 요청한다.
+const zeroTemperature = math.SmallestNonzeroFloat32
+
+// chatRequest 요청 옵션을 OpenAI SDK 요청으로 변환 (비어 있는 옵션은 클라이언트 설정 사용)
+func (c *Client) chatRequest(req llm.ChatRequest) openai.ChatCompletionRequest {
+	request := openai.ChatCompletionRequest{
+		Model:               req.Model,
+		Messages:            toOpenAIMessages(req.Messages),
+		MaxCompletionTokens: req.MaxCompletionTokens,
+		Stop:                req.Stop,
+	}
+	if request.Model == "" {
+		request.Model = c.model
+	}
+	if request.MaxCompletionTokens == 0 {
+		request.MaxCompletionTokens = c.maxCompletionTokens
+	}
+
+	if req.Temperature != nil {
+		request.Temperature = *req.Temperature
+		if request.Temperature == 0 {
+			request.Temperature = zeroTemperature
+		}
+	}
+
+	if format := req.ResponseFormat; format != nil {
+		if len(format.Schema) == 0 {
+			request.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+		} else {
+			request.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   format.Name,
+					Schema: format.Schema,
+					Strict: format.Strict,
+				},
+			}
+		}
+	}
+
+	return request
+}
+
+// toOpenAIMessages OpenAI SDK 형식으로 메시지 변환
+func toOpenAIMessages(messages []llm.ChatMessage) []openai.ChatCompletionMessage {
+	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
+	for i, msg := range messages {
+		openaiMessages[i] = openai.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+	return openaiMessages
+}
@@ -0,0 +1,59 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sermo-be/pkg/llm"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestChatRequestOptions(t *testing.T) {
+	client := &Client{model: "default-model", maxCompletionTokens: 2048}
+	messages := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+
+	// 옵션이 없으면 클라이언트 설정 사용
+	req := client.chatRequest(llm.ChatRequest{Messages: messages})
+	if req.Model != "default-model" || req.MaxCompletionTokens != 2048 || req.Temperature != 0 || req.ResponseFormat != nil {
+		t.Errorf("default request = %+v", req)
+	}
+
+	req = client.chatRequest(llm.ChatRequest{
+		Messages:            messages,
+		Model:               "small-model",
+		MaxCompletionTokens: 100,
+		Temperature:         llm.Temperature(0),
+		Stop:                []string{"\n\n"},
+		ResponseFormat:      llm.JSONSchema("status", `{"type":"object"}`),
+	})
+	if req.Model != "small-model" || req.MaxCompletionTokens != 100 || len(req.Stop) != 1 {
+		t.Errorf("request = %+v", req)
+	}
+	// 0은 SDK가 생략하므로 0에 가장 가까운 값으로 전달
+	if req.Temperature != zeroTemperature {
+		t.Errorf("temperature = %v", req.Temperature)
+	}
+	if body, _ := json.Marshal(req); !strings.Contains(string(body), `"temperature":`) {
+		t.Errorf("temperature omitted from request body: %s", body)
+	}
+
+	format := req.ResponseFormat
+	if format == nil || format.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || format.JSONSchema == nil {
+		t.Fatalf("response format = %+v", format)
+	}
+	if format.JSONSchema.Name != "status" || !format.JSONSchema.Strict {
+		t.Errorf("json schema = %+v", format.JSONSchema)
+	}
+	schema, err := json.Marshal(format.JSONSchema.Schema)
+	if err != nil || string(schema) != `{"type":"object"}` {
+		t.Errorf("schema = %s, %v", schema, err)
+	}
+
+	// 스키마 없이 형식만 지정하면 JSON 객체 모드
+	req = client.chatRequest(llm.ChatRequest{Messages: messages, ResponseFormat: &llm.ResponseFormat{Name: "any"}})
+	if req.ResponseFormat == nil || req.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONObject {
+		t.Errorf("json object format = %+v", req.ResponseFormat)
+	}
+}
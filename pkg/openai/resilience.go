@@ -0,0 +1,349 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// 재시도와 회로 차단 기본값
+const (
+	// DefaultMaxAttempts 모델별 최대 시도 횟수
+	DefaultMaxAttempts = 3
+	// DefaultCallTimeout 시도 한 번의 제한 시간
+	DefaultCallTimeout = 60 * time.Second
+	// DefaultBreakerThreshold 회로를 여는 연속 실패 횟수
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown 회로가 열린 뒤 다시 시도해 보기까지 기다리는 시간
+	DefaultBreakerCooldown = 30 * time.Second
+
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// ErrCircuitOpen 연속 실패로 회로가 열려 모델 호출을 건너뜀
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// permanentError 재시도하거나 대체 모델로 넘기면 안 되는 에러 (스트림 중간 실패 등)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// retryable 일시적인 장애(429, 408, 5xx, 네트워크 오류, 시도 시간 초과)라 다시 시도할 만한 에러인지 여부
+func retryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	if status := httpStatus(err); status != 0 {
+		return status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// fallbackable 대체 모델로 넘어갈 에러인지 여부 (일시적 장애, 모델 없음, 회로 열림)
+func fallbackable(err error) bool {
+	return retryable(err) || errors.Is(err, ErrCircuitOpen) || httpStatus(err) == http.StatusNotFound
+}
+
+// httpStatus OpenAI SDK 에러의 HTTP 상태 코드 (응답을 받지 못했으면 0)
+func httpStatus(err error) int {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode
+	}
+	return 0
+}
+
+// call 요청 모델과 대체 모델을 차례로 시도하며 fn 실행
+// 일시적 장애로 모델의 시도를 모두 쓰거나 회로가 열려 있으면 다음 대체 모델로 넘어간다.
+func (c *Client) call(ctx context.Context, model string, fallback bool, fn func(ctx context.Context, model string) error) error {
+	models := []string{model}
+	if fallback {
+		for _, m := range c.fallbackModels {
+			if m != model {
+				models = append(models, m)
+			}
+		}
+	}
+
+	var err error
+	for i, m := range models {
+		if err = c.callModel(ctx, m, fn); err == nil {
+			if i > 0 {
+				log.Printf("대체 모델로 응답 생성 - 모델: %s", m)
+			}
+			return nil
+		}
+		if ctx.Err() != nil || !fallbackable(err) {
+			return err
+		}
+		if i+1 < len(models) {
+			log.Printf("OpenAI 모델 호출 실패, 대체 모델 사용 - 모델: %s → %s, 에러: %v", m, models[i+1], err)
+		}
+	}
+	return err
+}
+
+// callModel 한 모델에 대해 회로 차단기를 거쳐 지터 백오프로 재시도
+func (c *Client) callModel(ctx context.Context, model string, fn func(ctx context.Context, model string) error) error {
+	breaker := c.breaker(model)
+
+	var err error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		if !breaker.allow() {
+			return fmt.Errorf("model %s: %w", model, ErrCircuitOpen)
+		}
+
+		hint := &retryHint{}
+		attemptCtx, cancel := context.WithTimeout(context.WithValue(ctx, retryHintKey{}, hint), c.callTimeout)
+		err = fn(attemptCtx, model)
+		cancel()
+
+		switch {
+		case err == nil:
+			breaker.success()
+			return nil
+		case ctx.Err() != nil:
+			// 호출한 쪽이 취소했으므로 모델 상태와 무관
+			breaker.release()
+			return err
+		case !retryable(err):
+			// 응답은 받았으므로 (400 등) 회로 상태에는 성공으로 기록
+			breaker.success()
+			return err
+		}
+
+		breaker.failure()
+		if attempt == c.maxAttempts {
+			break
+		}
+
+		wait := c.backoff(attempt)
+		if after := hint.get(); after > 0 {
+			// 서버가 요청한 대기 시간이 너무 길면 기다리지 않고 대체 모델로 넘김
+			if after > c.retryMaxDelay {
+				return err
+			}
+			wait = after
+		}
+
+		log.Printf("OpenAI 호출 재시도 - 모델: %s, 시도: %d/%d, 대기: %v, 에러: %v", model, attempt, c.maxAttempts, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// backoff 시도 횟수에 따라 지수적으로 늘어나는 최대값 안에서 무작위 대기 시간 (full jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.retryMaxDelay
+	if shift := attempt - 1; shift < 30 {
+		if d := c.retryBaseDelay << shift; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// breaker 모델별 회로 차단기 (없으면 생성)
+func (c *Client) breaker(model string) *circuitBreaker {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	breaker, ok := c.breakers[model]
+	if !ok {
+		breaker = newCircuitBreaker(c.breakerThreshold, c.breakerCooldown)
+		c.breakers[model] = breaker
+	}
+	return breaker
+}
+
+// breakerState 회로 차단기 상태
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 정상 호출
+	breakerOpen                         // 호출하지 않고 바로 실패
+	breakerHalfOpen                     // 대기 시간이 지나 한 번만 시험 호출
+)
+
+// circuitBreaker 연속 실패가 threshold에 이르면 cooldown 동안 호출을 막는 회로 차단기
+// cooldown이 지나면 한 번의 시험 호출을 허용하고, 성공하면 닫고 실패하면 다시 연다.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow 호출해도 되는지 여부 (threshold가 0 이하면 항상 허용)
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success 호출 성공 기록 (회로를 닫음)
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != breakerClosed {
+		log.Printf("OpenAI 회로 차단 해제")
+	}
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure 호출 실패 기록 (연속 실패가 threshold에 이르거나 시험 호출이 실패하면 회로를 엶)
+func (b *circuitBreaker) failure() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			log.Printf("OpenAI 회로 차단 - 연속 실패: %d, 대기: %v", b.failures, b.cooldown)
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// release 결과를 판단할 수 없는 호출 (호출한 쪽의 취소) 이후 시험 호출 자리 반납
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// retryHintKey 요청 컨텍스트에 retryHint를 담는 키
+type retryHintKey struct{}
+
+// retryHint 실패 응답의 Retry-After 헤더 값 (SDK 에러에는 헤더가 없어 HTTP 계층에서 기록)
+type retryHint struct {
+	mu    sync.Mutex
+	after time.Duration
+}
+
+func (h *retryHint) set(after time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.after = after
+}
+
+func (h *retryHint) get() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.after
+}
+
+// retryAfterDoer 실패 응답의 Retry-After 헤더를 요청 컨텍스트의 retryHint에 기록하는 HTTP 클라이언트
+type retryAfterDoer struct {
+	doer openai.HTTPDoer
+}
+
+func (d *retryAfterDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.doer.Do(req)
+	if err != nil || resp.StatusCode < 400 {
+		return resp, err
+	}
+
+	if hint, ok := req.Context().Value(retryHintKey{}).(*retryHint); ok {
+		hint.set(parseRetryAfter(resp.Header, time.Now()))
+	}
+	return resp, err
+}
+
+// parseRetryAfter retry-after-ms(밀리초), Retry-After(초 또는 HTTP 날짜) 헤더에서 대기 시간 추출 (없으면 0)
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After-Ms"); value != "" {
+		if ms, err := strconv.ParseFloat(value, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
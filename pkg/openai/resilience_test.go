@@ -0,0 +1,263 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"sermo-be/pkg/llm"
+)
+
+// newTestClient 요청마다 handler를 호출하는 테스트 서버에 연결된 클라이언트 (재시도 대기는 짧게)
+func newTestClient(t *testing.T, cfg Config, handler func(w http.ResponseWriter, model string, attempt int)) (*Client, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		models = append(models, req.Model)
+		attempt := len(models)
+		mu.Unlock()
+
+		handler(w, req.Model, attempt)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.APIKey = "sk-test"
+	cfg.BaseURL = server.URL + "/v1"
+	if cfg.Model == "" {
+		cfg.Model = "primary"
+	}
+	if cfg.RetryBaseDelay == 0 {
+		cfg.RetryBaseDelay = time.Millisecond
+	}
+	if cfg.RetryMaxDelay == 0 {
+		cfg.RetryMaxDelay = 50 * time.Millisecond
+	}
+
+	client, err := NewClient(&cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), models...)
+	}
+}
+
+func writeCompletion(w http.ResponseWriter, content string) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q},"finish_reason":"stop"}]}`, content)
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, `{"error":{"message":"upstream failed","type":"server_error"}}`)
+}
+
+var hello = []llm.ChatMessage{{Role: "user", Content: "hi"}}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	client, requests := newTestClient(t, Config{}, func(w http.ResponseWriter, _ string, attempt int) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After-Ms", "20")
+			writeError(w, http.StatusTooManyRequests)
+			return
+		}
+		writeCompletion(w, "안녕")
+	})
+
+	start := time.Now()
+	resp, err := client.ChatCompletion(context.Background(), hello)
+	if err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+	if resp.Message.Content != "안녕" || len(requests()) != 2 {
+		t.Errorf("content = %q, requests = %v", resp.Message.Content, requests())
+	}
+	// Retry-After 만큼 기다린 뒤 재시도
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least Retry-After", elapsed)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	client, requests := newTestClient(t, Config{FallbackModels: []string{"backup"}}, func(w http.ResponseWriter, _ string, _ int) {
+		writeError(w, http.StatusBadRequest)
+	})
+
+	if _, err := client.ChatCompletion(context.Background(), hello); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := requests(); len(got) != 1 {
+		t.Errorf("requests = %v, want a single attempt", got)
+	}
+}
+
+func TestFallbackModels(t *testing.T) {
+	client, requests := newTestClient(t, Config{MaxAttempts: 2, FallbackModels: []string{"backup"}}, func(w http.ResponseWriter, model string, _ int) {
+		if model == "primary" {
+			writeError(w, http.StatusServiceUnavailable)
+			return
+		}
+		writeCompletion(w, "대체 응답")
+	})
+
+	resp, err := client.ChatCompletion(context.Background(), hello)
+	if err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+	if resp.Message.Content != "대체 응답" || resp.Model != "backup" {
+		t.Errorf("content = %q, model = %q", resp.Message.Content, resp.Model)
+	}
+	if got := fmt.Sprint(requests()); got != "[primary primary backup]" {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestCallTimeoutRetries(t *testing.T) {
+	client, requests := newTestClient(t, Config{CallTimeout: 50 * time.Millisecond}, func(w http.ResponseWriter, _ string, attempt int) {
+		if attempt == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		writeCompletion(w, "늦었지만 응답")
+	})
+
+	if _, err := client.ChatCompletion(context.Background(), hello); err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+	if len(requests()) != 2 {
+		t.Errorf("requests = %v, want retry after timeout", requests())
+	}
+}
+
+func TestCircuitBreakerSkipsModel(t *testing.T) {
+	cfg := Config{MaxAttempts: 1, BreakerThreshold: 2, BreakerCooldown: time.Hour, FallbackModels: []string{"backup"}}
+	client, requests := newTestClient(t, cfg, func(w http.ResponseWriter, model string, _ int) {
+		if model == "primary" {
+			writeError(w, http.StatusInternalServerError)
+			return
+		}
+		writeCompletion(w, "ok")
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ChatCompletion(context.Background(), hello); err != nil {
+			t.Fatalf("ChatCompletion %d: %v", i, err)
+		}
+	}
+	// 두 번 실패한 뒤에는 회로가 열려 primary를 호출하지 않음
+	if got := fmt.Sprint(requests()); got != "[primary backup primary backup backup]" {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.failure()
+	if !breaker.allow() {
+		t.Fatal("one failure should not open the circuit")
+	}
+	breaker.failure()
+	if breaker.allow() {
+		t.Fatal("circuit should be open after threshold")
+	}
+
+	// 대기 시간이 지나면 시험 호출 한 번만 허용
+	now = now.Add(time.Minute)
+	if !breaker.allow() {
+		t.Fatal("half-open circuit should allow a probe")
+	}
+	if breaker.allow() {
+		t.Fatal("only one probe at a time")
+	}
+
+	// 시험 호출이 실패하면 다시 열림
+	breaker.failure()
+	if breaker.allow() {
+		t.Fatal("failed probe should reopen the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	if !breaker.allow() {
+		t.Fatal("probe after second cooldown")
+	}
+	breaker.success()
+	if !breaker.allow() || !breaker.allow() {
+		t.Fatal("successful probe should close the circuit")
+	}
+}
+
+func TestStreamNotRetriedAfterDelta(t *testing.T) {
+	client, requests := newTestClient(t, Config{}, func(w http.ResponseWriter, _ string, _ int) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"안녕\"}}]}\n\n")
+	})
+
+	stop := errors.New("client gone")
+	_, err := client.ChatCompletionStream(context.Background(), hello, func(string) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want %v", err, stop)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("requests = %v, want no retry once deltas were sent", requests())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{http.Header{"Retry-After": {"3"}}, 3 * time.Second},
+		{http.Header{"Retry-After-Ms": {"250"}, "Retry-After": {"1"}}, 250 * time.Millisecond},
+		{http.Header{"Retry-After": {now.Add(5 * time.Second).Format(http.TimeFormat)}}, 5 * time.Second},
+		{http.Header{"Retry-After": {"soon"}}, 0},
+		{http.Header{}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%v) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestEmbedReturnsUsage(t *testing.T) {
+	client, requests := newTestClient(t, Config{}, func(w http.ResponseWriter, _ string, _ int) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[{"index":0,"embedding":[0.5,0.5]}],"model":"text-embedding-3-small","usage":{"prompt_tokens":3,"total_tokens":3}}`)
+	})
+
+	resp, err := client.Embed(context.Background(), []string{"math exam tomorrow"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(resp.Vectors) != 1 || len(resp.Vectors[0]) != 2 {
+		t.Errorf("vectors = %v", resp.Vectors)
+	}
+	if resp.Usage.PromptTokens != 3 || resp.Usage.TotalTokens != 3 || resp.Model != "text-embedding-3-small" {
+		t.Errorf("usage = %+v, model = %q", resp.Usage, resp.Model)
+	}
+	if got := requests(); len(got) != 1 || got[0] != DefaultEmbeddingModel {
+		t.Errorf("requests = %v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package password
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultCost bcrypt 해시 비용 (값이 바뀌면 다음 로그인 시 재해시됨)
+const DefaultCost = 12
+
+// MaxLength bcrypt가 처리할 수 있는 최대 비밀번호 길이 (바이트)
+const MaxLength = 72
+
+// ErrTooLong 비밀번호가 bcrypt 최대 길이를 초과한 경우
+var ErrTooLong = errors.New("password exceeds 72 bytes")
+
+// Hash 평문 비밀번호를 bcrypt로 해시
+func Hash(plain string) (string, error) {
+	if len(plain) > MaxLength {
+		return "", ErrTooLong
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hashed), nil
+}
+
+// Verify 저장된 값과 평문 비밀번호 비교
+// 해시되지 않은 레거시 평문 값도 상수 시간 비교로 허용한다 (NeedsRehash로 업그레이드 필요 여부 확인)
+func Verify(stored, plain string) bool {
+	if !IsHashed(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(plain)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain)) == nil
+}
+
+// NeedsRehash 저장된 값이 평문이거나 현재 비용보다 낮은 해시인지 확인
+func NeedsRehash(stored string) bool {
+	if !IsHashed(stored) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(stored))
+	if err != nil {
+		return true
+	}
+	return cost < DefaultCost
+}
+
+// IsHashed 저장된 값이 bcrypt 해시 형식인지 확인
+func IsHashed(stored string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(stored, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	hashed, err := Hash("correct-horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if !IsHashed(hashed) {
+		t.Fatalf("Hash() = %q, want bcrypt hash", hashed)
+	}
+	if !Verify(hashed, "correct-horse") {
+		t.Error("Verify() = false for correct password")
+	}
+	if Verify(hashed, "wrong-horse") {
+		t.Error("Verify() = true for wrong password")
+	}
+	if NeedsRehash(hashed) {
+		t.Error("NeedsRehash() = true for fresh hash")
+	}
+}
+
+func TestVerifyLegacyPlaintext(t *testing.T) {
+	if !Verify("legacy-pass", "legacy-pass") {
+		t.Error("Verify() = false for matching legacy plaintext")
+	}
+	if Verify("legacy-pass", "legacy-pas") {
+		t.Error("Verify() = true for mismatched legacy plaintext")
+	}
+	if !NeedsRehash("legacy-pass") {
+		t.Error("NeedsRehash() = false for legacy plaintext")
+	}
+}
+
+func TestNeedsRehashLowCost(t *testing.T) {
+	weak, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	if !Verify(string(weak), "secret") {
+		t.Error("Verify() = false for low cost hash")
+	}
+	if !NeedsRehash(string(weak)) {
+		t.Error("NeedsRehash() = false for low cost hash")
+	}
+}
+
+func TestHashTooLong(t *testing.T) {
+	if _, err := Hash(strings.Repeat("a", MaxLength+1)); err != ErrTooLong {
+		t.Errorf("Hash() error = %v, want ErrTooLong", err)
+	}
+}
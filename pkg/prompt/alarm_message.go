@@ -48,11 +48,11 @@ Keywords should focus on:
 1. Most recent and frequent status events
 2. User's current emotional or practical needs
 
-Format your response as:
-Keywords: [keyword1], [keyword2]
+Respond in JSON only:
+{"keywords": ["keyword1", "keyword2"]}
 
 Example:
-Keywords: birthday, celebration`
+{"keywords": ["birthday", "celebration"]}`
 
 	return prompt
 }
@@ -65,6 +65,11 @@ func BuildPersonalizedAlarmPrompt(keywords []string, userStatuses []models.UserS
 
 	latestStatus := userStatuses[0]
 
+	summary := ""
+	if chatbot.GetSummary() != nil {
+		summary = *chatbot.GetSummary()
+	}
+
 	prompt := fmt.Sprintf(`Chatbot Character: %s (%s)
 Character Details: %s
 Character Summary: %s
@@ -84,11 +89,11 @@ Create a personalized alarm message that:
 
 Also suggest the best time to send this alarm message based on the event type and user's situation.
 
-Format your response as:
-[Your personalized alarm message]
+Respond in JSON only:
+{"message": "Your personalized alarm message", "send_time": "YYYY-MM-DD HH:MM:SS"}
 
-Send Time: YYYY-MM-DD HH:MM:SS (e.g., "2025-01-25 00:00:00" for birthday at midnight, "2025-01-25 13:30:00" for 30 minutes before exam)`,
-		chatbot.Name, chatbot.Gender, chatbot.Details, chatbot.GetSummary(),
+send_time examples: "2025-01-25 00:00:00" for birthday at midnight, "2025-01-25 13:30:00" for 30 minutes before exam`,
+		chatbot.Name, chatbot.Gender, chatbot.Details, summary,
 		user.Nickname, latestStatus.Event, latestStatus.Context,
 		strings.Join(keywords, ", "))
 
@@ -16,7 +16,8 @@ type ChatbotInfo struct {
 }
 
 // BuildSystemPrompt 채팅봇 시스템 프롬프트 구성
-func BuildSystemPrompt(chatbotInfo *ChatbotInfo, userStatus *models.UserStatus) string {
+// memory는 이전 대화의 누적 요약 (없으면 빈 문자열), recalled는 현재 메시지와 관련된 지난 사용자 메시지
+func BuildSystemPrompt(chatbotInfo *ChatbotInfo, userStatus *models.UserStatus, memory string, recalled []string) string {
 	var prompt strings.Builder
 
 	// 기본 캐릭터 설정 - 친구로 인식
@@ -30,6 +31,22 @@ func BuildSystemPrompt(chatbotInfo *ChatbotInfo, userStatus *models.UserStatus)
 		prompt.WriteString(fmt.Sprintf("Personality and characteristics: %s\n", chatbotInfo.Details))
 	}
 
+	// 최근 대화 이전의 기억 (요약)
+	if strings.TrimSpace(memory) != "" {
+		prompt.WriteString("\nWhat you remember from earlier conversations with this friend:\n")
+		prompt.WriteString(memory + "\n")
+		prompt.WriteString("Use these memories naturally when relevant, but don't recite them.\n")
+	}
+
+	// 현재 메시지와 관련된 지난 대화
+	if len(recalled) > 0 {
+		prompt.WriteString("\nThings your friend told you before that may be relevant now:\n")
+		for _, snippet := range recalled {
+			prompt.WriteString("- " + snippet + "\n")
+		}
+		prompt.WriteString("Follow up on these naturally if they fit (e.g. ask how it went).\n")
+	}
+
 	// 상태 정보가 맥락에 맞는 경우 추가
 	if userStatus != nil {
 		prompt.WriteString(fmt.Sprintf("\nCurrent situation: %s\n", userStatus.Event))
@@ -0,0 +1,44 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"sermo-be/internal/models"
+)
+
+// ConversationSummarySystemPrompt 대화 요약 요청의 시스템 메시지
+const ConversationSummarySystemPrompt = "You maintain the long-term memory of an AI character. Merge the existing memory with the new conversation into one concise, factual summary written from the character's point of view."
+
+// BuildConversationSummaryPrompt 기존 요약과 새 대화를 합쳐 누적 요약을 만드는 프롬프트 구성
+func BuildConversationSummaryPrompt(chatbotName, previousSummary string, messages []models.ChatMessage) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Character: %s\n\n", chatbotName))
+
+	prompt.WriteString("Existing memory:\n")
+	if strings.TrimSpace(previousSummary) == "" {
+		prompt.WriteString("(none)\n\n")
+	} else {
+		prompt.WriteString(previousSummary + "\n\n")
+	}
+
+	prompt.WriteString("New conversation (oldest first):\n")
+	for _, msg := range messages {
+		speaker := "User"
+		if msg.MessageType == models.MessageTypeChatbot {
+			speaker = chatbotName
+		}
+		prompt.WriteString(fmt.Sprintf("[%s] %s: %s\n", msg.CreatedAt.Format("2006-01-02 15:04"), speaker, msg.Content))
+	}
+
+	prompt.WriteString("\nWrite the updated memory:\n")
+	prompt.WriteString("- Keep facts about the user (name, preferences, plans, relationships, important events) and promises made by either side\n")
+	prompt.WriteString("- Keep the emotional tone of the relationship and any running jokes\n")
+	prompt.WriteString("- Mention dates for plans and events when known\n")
+	prompt.WriteString("- Drop small talk and anything already outdated\n")
+	prompt.WriteString("- Use plain English bullet points, 200 words or less\n")
+	prompt.WriteString("- Return only the memory, without any introduction\n")
+
+	return prompt.String()
+}
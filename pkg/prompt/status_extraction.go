@@ -17,11 +17,11 @@ JSON 형식으로만 응답하세요:
 {"needs_save": true, "event": "이벤트명", "valid_until": "2025-01-02T15:04:05Z", "context": "설명"}
 
 상태 정보가 없는 경우:
-{"needs_save": false}
+{"needs_save": false, "event": null, "valid_until": null, "context": null}
 
 예시:
 - "내일 시험" → {"needs_save": true, "event": "시험", "valid_until": "2025-01-XXT23:59:59Z", "context": "내일 시험"}
-- "안녕하세요" → {"needs_save": false}`
+- "안녕하세요" → {"needs_save": false, "event": null, "valid_until": null, "context": null}`
 }
 
 // GetStatusSavePrompt 저장된 상태 정보를 정리하는 프롬프트